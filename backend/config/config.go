@@ -46,9 +46,37 @@ type WebServerConfig struct {
 	EnableTLS       bool          `json:"enableTls"`
 	CertFilePath    string        `json:"certFilePath"`
 	KeyFilePath     string        `json:"keyFilePath"`
+	PreviewBackend  string        `json:"previewBackend"` // "hls-generator", "ffmpeg" or "obs" (screenshots of an OBS input)
 	ReadTimeout     time.Duration `json:"-"`
 	WriteTimeout    time.Duration `json:"-"`
 	ShutdownTimeout time.Duration `json:"-"`
+
+	PreviewGenerators PreviewGeneratorsConfig `json:"previewGenerators"`
+	PreviewLimits     PreviewLimitsConfig     `json:"previewLimits"`
+}
+
+// PreviewLimitsConfig bounds the resources used by source previews. Clients
+// previewing the same source share one generator process.
+type PreviewLimitsConfig struct {
+	MaxProcesses      int `json:"maxProcesses"`      // Generator processes running at once; further previews wait (0 = unlimited)
+	MaxRuntimeSeconds int `json:"maxRuntimeSeconds"` // Time a client may watch a preview (0 = unlimited)
+	GraceSeconds      int `json:"graceSeconds"`      // Time a preview keeps running after its last viewer leaves (0 = stop at once)
+}
+
+// PreviewGeneratorsConfig holds the arguments of each external program that
+// can generate source previews as HLS streams.
+type PreviewGeneratorsConfig struct {
+	HLSGenerator PreviewGeneratorConfig `json:"hlsGenerator"`
+	FFmpeg       PreviewGeneratorConfig `json:"ffmpeg"`
+}
+
+// PreviewGeneratorConfig holds the arguments of a preview generator. Empty
+// values leave the choice to the generator.
+type PreviewGeneratorConfig struct {
+	Path            string `json:"path"`            // Binary; empty = next to the executable, then PATH
+	SegmentDuration int    `json:"segmentDuration"` // Seconds per HLS segment
+	Resolution      string `json:"resolution"`      // "WIDTHxHEIGHT", e.g. "640x360"
+	VideoBitrate    string `json:"videoBitrate"`    // e.g. "800k"
 }
 
 type OBSConfig struct {
@@ -59,11 +87,111 @@ type OBSConfig struct {
 	ScheduleScene     string `json:"scheduleScene"`
 	ScheduleSceneAux  string `json:"scheduleSceneAux"`
 	SourceNamePrefix  string `json:"sourceNamePrefix"`
+	PreviewScene      string `json:"previewScene"` // Scene holding source preview inputs, never put on air
+
+	// Transition is used for programs that do not define their own.
+	Transition Transition `json:"transition"`
+
+	// Loudness steers the volume of the programs on air toward a target.
+	Loudness LoudnessConfig `json:"loudness"`
+
+	// Health watches live sources on air and covers them with a fallback
+	// source while they are down.
+	Health HealthConfig `json:"health"`
+
+	// FrameCheck analyses screenshots of the program output for black,
+	// frozen and largely static pictures.
+	FrameCheck FrameCheckConfig `json:"frameCheck"`
+
+	// Silence raises an alert when the program on air goes quiet.
+	Silence SilenceConfig `json:"silence"`
+
+	// Thumbnails captures small images of the program on air and of the
+	// next program's source for the web monitor view.
+	Thumbnails ThumbnailConfig `json:"thumbnails"`
+
+	// Preflight checks that upcoming programs can air, a few minutes before
+	// they start.
+	Preflight PreflightConfig `json:"preflight"`
+}
+
+// HealthConfig controls the health monitoring of the program on air. Live
+// media inputs (stream URLs) are watched through their OBS media state, and
+// browser sources by reaching their page.
+type HealthConfig struct {
+	Enabled         bool `json:"enabled"`
+	StallSeconds    int  `json:"stallSeconds"`    // Time a source may be down before the fallback is shown
+	RecoverySeconds int  `json:"recoverySeconds"` // Time a source must be back up before the fallback is removed
+}
+
+// LoudnessConfig controls loudness normalization. The loudness of the inputs
+// on air is estimated from the OBS volume meters, and their volume is nudged
+// toward the target within the given limits.
+type LoudnessConfig struct {
+	Enabled    bool    `json:"enabled"`
+	TargetLUFS float64 `json:"targetLufs"` // e.g. -23 (EBU R128) or -24 (ATSC A/85)
+	MaxBoostDb float64 `json:"maxBoostDb"` // Largest gain added on top of the program's own volume
+	MaxCutDb   float64 `json:"maxCutDb"`   // Largest gain removed from the program's own volume
+	StepDb     float64 `json:"stepDb"`     // Largest change per second, so corrections are inaudible
+}
+
+// FrameCheckConfig controls the analysis of program output screenshots.
+// Programs that are meant to be still, such as slides, are reported as
+// frozen too, so the fallback is only triggered when asked for.
+type FrameCheckConfig struct {
+	Enabled           bool    `json:"enabled"`
+	IntervalSeconds   int     `json:"intervalSeconds"`   // Time between screenshots
+	FrozenSamples     int     `json:"frozenSamples"`     // Consecutive unchanged screenshots that make a frozen picture
+	StaticRegionRatio float64 `json:"staticRegionRatio"` // Share of the picture unchanged over frozenSamples that is reported (0 = not checked)
+	SustainSeconds    int     `json:"sustainSeconds"`    // Time an anomaly must last before it is reported, or be gone before it clears
+	TriggerFallback   bool    `json:"triggerFallback"`   // Cover the program with its fallback while an anomaly lasts
+}
+
+// SilenceConfig controls dead-air detection on the main track, based on the
+// OBS volume meters of the inputs on air. Programs with no audio to expect,
+// such as images, are exempt by input kind or by their expectSilence flag.
+type SilenceConfig struct {
+	Enabled          bool     `json:"enabled"`
+	ThresholdDb      float64  `json:"thresholdDb"`      // Peak level in dBFS below which audio counts as silence
+	DurationSeconds  int      `json:"durationSeconds"`  // Time the program must stay silent before the alert
+	TriggerFallback  bool     `json:"triggerFallback"`  // Cover the program with its fallback while it stays silent
+	ExemptInputKinds []string `json:"exemptInputKinds"` // Input kinds that are never checked
+}
+
+// PreflightConfig controls the pre-flight checks of upcoming programs: local
+// files must be readable, URLs must respond, and OBS must support the input
+// kinds and scenes. Failures raise an operator alert, and are checked again
+// until the program starts.
+type PreflightConfig struct {
+	Enabled          bool `json:"enabled"`
+	LeadMinutes      int  `json:"leadMinutes"`      // How long before its start a program is checked
+	TrialInput       bool `json:"trialInput"`       // Also create the program's inputs hidden in the aux scene
+	ReplaceOnFailure bool `json:"replaceOnFailure"` // Air the program's fallback instead if it still fails at start
+}
+
+// ThumbnailConfig controls the confidence thumbnails served by the web
+// server: the program on air, and the last known image of the next program.
+type ThumbnailConfig struct {
+	Enabled         bool   `json:"enabled"`
+	IntervalSeconds int    `json:"intervalSeconds"` // Time between captures
+	Width           int    `json:"width"`           // Width in pixels; the height follows the aspect ratio
+	Format          string `json:"format"`          // "jpg" or "png"
+}
+
+// Transition describes how a program replaces the one before it: "cut",
+// "fade" or "stinger".
+type Transition struct {
+	Type           string `json:"type"`
+	DurationMs     int    `json:"durationMs"`     // Length of the fade, or of the stinger clip
+	StingerURI     string `json:"stingerUri"`     // Stinger clip, required for "stinger"
+	StingerPointMs int    `json:"stingerPointMs"` // Point in the stinger clip where the cut happens
+	AudioFadeMs    int    `json:"audioFadeMs"`    // Audio crossfade between programs (0 = hard audio cut)
 }
 
 type PathsConfig struct {
 	LogFile  string `json:"logFile"`
 	Schedule string `json:"schedule"`
+	AsRunLog string `json:"asRunLog"` // JSON Lines record of everything that aired (empty disables it)
 }
 
 // SchedulerConfig holds settings specific to the scheduler module.
@@ -71,6 +199,15 @@ type SchedulerConfig struct {
 	// DefaultSource is an optional source that will be activated when no other
 	// program is scheduled to be active.
 	DefaultSource DefaultSource `json:"defaultSource"`
+
+	// FillerPool is an optional rotation of sources used to fill schedule gaps.
+	// When it contains items, it takes precedence over DefaultSource.
+	FillerPool FillerPool `json:"fillerPool"`
+
+	// BreakPools are named sets of items aired during program breaks, referenced
+	// by the break rules of a program. Each item airs for MaxDurationSeconds
+	// (0 = the rest of the break).
+	BreakPools map[string][]FillerItem `json:"breakPools"`
 }
 
 // DefaultSource defines a backup source to be used by the scheduler.
@@ -82,6 +219,24 @@ type DefaultSource struct {
 	Transform     interface{} `json:"transform"`
 }
 
+// FillerPool defines the set of sources the scheduler rotates through during gaps.
+type FillerPool struct {
+	Items []FillerItem `json:"items"`
+}
+
+// FillerItem defines a single source in the filler pool and its rotation rules.
+type FillerItem struct {
+	Name               string      `json:"name"`
+	Title              string      `json:"title"`
+	InputKind          string      `json:"inputKind"`
+	URI                string      `json:"uri"`
+	InputSettings      interface{} `json:"inputSettings"`
+	Transform          interface{} `json:"transform"`
+	Weight             int         `json:"weight"`             // Relative selection weight (0 is treated as 1)
+	MinRepeatSeconds   int         `json:"minRepeatSeconds"`   // Minimum time off air before the item can air again
+	MaxDurationSeconds int         `json:"maxDurationSeconds"` // Maximum time on air before rotating (0 = until the gap ends)
+}
+
 // NewConfig loads, decodes, and validates the configuration from the fixed default path.
 func NewConfig() (*Config, error) {
 	data, err := os.ReadFile(defaultConfigPath)
@@ -114,6 +269,9 @@ func (c *Config) applyDefaults() {
 	c.MediaSource.Quality = "low"
 	c.WebServer.Port = "8080"
 	c.WebServer.HlsPath = "hls"
+	c.WebServer.PreviewBackend = "hls-generator"
+	c.WebServer.PreviewGenerators.FFmpeg = PreviewGeneratorConfig{SegmentDuration: 2, Resolution: "640x360", VideoBitrate: "800k"}
+	c.WebServer.PreviewLimits = PreviewLimitsConfig{MaxProcesses: 4, MaxRuntimeSeconds: 30}
 	c.WebServer.ReadTimeout = 30 * time.Second
 	c.WebServer.WriteTimeout = 30 * time.Second
 	c.WebServer.ShutdownTimeout = 15 * time.Second
@@ -121,7 +279,20 @@ func (c *Config) applyDefaults() {
 	c.OBS.Port = 4455
 	c.OBS.ReconnectInterval = 15
 	c.OBS.SourceNamePrefix = "_sched_"
+	c.OBS.PreviewScene = "_sched_preview"
+	c.OBS.Transition = Transition{Type: "cut", DurationMs: 500}
+	c.OBS.Loudness = LoudnessConfig{TargetLUFS: -23, MaxBoostDb: 6, MaxCutDb: 12, StepDb: 0.5}
+	c.OBS.Health = HealthConfig{StallSeconds: 5, RecoverySeconds: 10}
+	c.OBS.FrameCheck = FrameCheckConfig{IntervalSeconds: 2, FrozenSamples: 5, StaticRegionRatio: 0.9, SustainSeconds: 10}
+	c.OBS.Silence = SilenceConfig{
+		ThresholdDb:      -50,
+		DurationSeconds:  10,
+		ExemptInputKinds: []string{"image_source", "color_source_v3", "text_ft2_source_v2", "text_gdiplus_v3", "slideshow", "slideshow_v2"},
+	}
+	c.OBS.Thumbnails = ThumbnailConfig{IntervalSeconds: 5, Width: 320, Format: "jpg"}
+	c.OBS.Preflight = PreflightConfig{LeadMinutes: 5}
 	c.Paths.Schedule = "schedule.json"
+	c.Paths.AsRunLog = "asrun.jsonl"
 }
 
 func (c *Config) validate() error {
@@ -138,6 +309,55 @@ func (c *Config) validate() error {
 		return fmt.Errorf("webServer.certFilePath and webServer.keyFilePath are required when TLS is enabled")
 	}
 
+	switch c.WebServer.PreviewBackend {
+	case "hls-generator", "ffmpeg", "obs":
+	default:
+		return fmt.Errorf("webServer.previewBackend must be \"hls-generator\", \"ffmpeg\" or \"obs\", got %q", c.WebServer.PreviewBackend)
+	}
+	if err := c.WebServer.PreviewGenerators.HLSGenerator.validate("webServer.previewGenerators.hlsGenerator"); err != nil {
+		return err
+	}
+	if err := c.WebServer.PreviewGenerators.FFmpeg.validate("webServer.previewGenerators.ffmpeg"); err != nil {
+		return err
+	}
+	if l := c.WebServer.PreviewLimits; l.MaxProcesses < 0 || l.MaxRuntimeSeconds < 0 || l.GraceSeconds < 0 {
+		return fmt.Errorf("webServer.previewLimits: maxProcesses, maxRuntimeSeconds and graceSeconds cannot be negative")
+	}
+	if c.WebServer.PreviewBackend == "obs" && c.OBS.PreviewScene == "" {
+		return fmt.Errorf("obs.previewScene is required when webServer.previewBackend is \"obs\"")
+	}
+
+	if err := c.OBS.Transition.validate("obs.transition"); err != nil {
+		return err
+	}
+	if err := c.OBS.Loudness.validate(); err != nil {
+		return err
+	}
+	if c.OBS.Health.Enabled && (c.OBS.Health.StallSeconds <= 0 || c.OBS.Health.RecoverySeconds <= 0) {
+		return fmt.Errorf("obs.health: stallSeconds and recoverySeconds must be positive")
+	}
+	if err := c.OBS.FrameCheck.validate(); err != nil {
+		return err
+	}
+	if c.OBS.Silence.Enabled && (c.OBS.Silence.ThresholdDb >= 0 || c.OBS.Silence.DurationSeconds <= 0) {
+		return fmt.Errorf("obs.silence: thresholdDb must be below 0 and durationSeconds must be positive")
+	}
+	if err := c.OBS.Thumbnails.validate(); err != nil {
+		return err
+	}
+	if c.OBS.Preflight.Enabled && c.OBS.Preflight.LeadMinutes <= 0 {
+		return fmt.Errorf("obs.preflight: leadMinutes must be positive")
+	}
+
+	if err := c.Scheduler.FillerPool.validate(); err != nil {
+		return err
+	}
+	for name, items := range c.Scheduler.BreakPools {
+		if err := validateFillerItems(items, fmt.Sprintf("scheduler.breakPools[%q]", name)); err != nil {
+			return err
+		}
+	}
+
 	// Validate hlsPath is a safe relative path
 	if err := validateSafeRelativePath(c.WebServer.HlsPath, "webServer.hlsPath"); err != nil {
 		return err
@@ -146,6 +366,114 @@ func (c *Config) validate() error {
 	return nil
 }
 
+// validate checks that the transition type is known and has what it needs.
+func (t *Transition) validate(field string) error {
+	switch t.Type {
+	case "cut", "fade":
+	case "stinger":
+		if t.StingerURI == "" {
+			return fmt.Errorf("%s.stingerUri is required for stinger transitions", field)
+		}
+	default:
+		return fmt.Errorf("%s.type must be \"cut\", \"fade\" or \"stinger\", got %q", field, t.Type)
+	}
+	if t.DurationMs < 0 || t.StingerPointMs < 0 || t.AudioFadeMs < 0 {
+		return fmt.Errorf("%s: durationMs, stingerPointMs and audioFadeMs cannot be negative", field)
+	}
+	if t.StingerPointMs > t.DurationMs {
+		return fmt.Errorf("%s.stingerPointMs cannot be past the end of the transition", field)
+	}
+	return nil
+}
+
+// validate checks that the loudness target and limits are usable.
+func (l *LoudnessConfig) validate() error {
+	if !l.Enabled {
+		return nil
+	}
+	if l.TargetLUFS >= 0 || l.TargetLUFS < -70 {
+		return fmt.Errorf("obs.loudness.targetLufs must be between -70 and 0, got %g", l.TargetLUFS)
+	}
+	if l.MaxBoostDb < 0 || l.MaxCutDb < 0 {
+		return fmt.Errorf("obs.loudness: maxBoostDb and maxCutDb cannot be negative")
+	}
+	if l.StepDb <= 0 {
+		return fmt.Errorf("obs.loudness.stepDb must be positive")
+	}
+	return nil
+}
+
+// validate checks that the frame check samples often and long enough to
+// tell a frozen picture from a still moment.
+func (f *FrameCheckConfig) validate() error {
+	if !f.Enabled {
+		return nil
+	}
+	if f.IntervalSeconds <= 0 || f.SustainSeconds < 0 {
+		return fmt.Errorf("obs.frameCheck: intervalSeconds must be positive and sustainSeconds cannot be negative")
+	}
+	if f.FrozenSamples < 2 {
+		return fmt.Errorf("obs.frameCheck.frozenSamples must be at least 2")
+	}
+	if f.StaticRegionRatio < 0 || f.StaticRegionRatio > 1 {
+		return fmt.Errorf("obs.frameCheck.staticRegionRatio must be between 0 and 1")
+	}
+	return nil
+}
+
+// validate checks that thumbnails are captured in a format browsers show.
+func (t *ThumbnailConfig) validate() error {
+	if !t.Enabled {
+		return nil
+	}
+	if t.IntervalSeconds <= 0 || t.Width <= 0 {
+		return fmt.Errorf("obs.thumbnails: intervalSeconds and width must be positive")
+	}
+	if t.Format != "jpg" && t.Format != "png" {
+		return fmt.Errorf("obs.thumbnails.format must be \"jpg\" or \"png\", got %q", t.Format)
+	}
+	return nil
+}
+
+// validate checks the arguments of a preview generator; field is the config
+// path used in error messages.
+func (g *PreviewGeneratorConfig) validate(field string) error {
+	if g.SegmentDuration < 0 {
+		return fmt.Errorf("%s.segmentDuration cannot be negative", field)
+	}
+	if g.Resolution != "" {
+		var width, height int
+		if n, err := fmt.Sscanf(g.Resolution, "%dx%d", &width, &height); err != nil || n != 2 || width <= 0 || height <= 0 {
+			return fmt.Errorf("%s.resolution must look like \"1280x720\", got %q", field, g.Resolution)
+		}
+	}
+	return nil
+}
+
+// validate checks that every filler item is uniquely named and has sane rotation rules.
+func (p *FillerPool) validate() error {
+	return validateFillerItems(p.Items, "scheduler.fillerPool.items")
+}
+
+// validateFillerItems checks a list of filler or break items. The field name
+// prefixes every error message.
+func validateFillerItems(items []FillerItem, field string) error {
+	seen := make(map[string]bool, len(items))
+	for i, item := range items {
+		if item.Name == "" || item.InputKind == "" {
+			return fmt.Errorf("%s[%d]: name and inputKind are required", field, i)
+		}
+		if seen[item.Name] {
+			return fmt.Errorf("%s[%d]: duplicate name %q", field, i, item.Name)
+		}
+		seen[item.Name] = true
+		if item.Weight < 0 || item.MinRepeatSeconds < 0 || item.MaxDurationSeconds < 0 {
+			return fmt.Errorf("%s[%d]: weight, minRepeatSeconds and maxDurationSeconds cannot be negative", field, i)
+		}
+	}
+	return nil
+}
+
 // validateSafeRelativePath ensures a path is relative, doesn't contain path traversal,
 // and doesn't escape the current directory.
 func validateSafeRelativePath(path, fieldName string) error {
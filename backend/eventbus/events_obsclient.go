@@ -68,11 +68,172 @@ func (e OBSStreamStateChanged) GetTopic() string { return "obs.stream.state.chan
 type OBSRecordStateChanged struct {
     IsRecording bool      `json:"isRecording"`
     OutputName  string    `json:"outputName,omitempty"`
+    OutputPath  string    `json:"outputPath,omitempty"` // File written, set when recording stops
     Timestamp   time.Time `json:"timestamp"`
 }
 
 func (e OBSRecordStateChanged) GetTopic() string { return "obs.record.state.changed" }
 
+// OBSMacroExecuted is emitted when a scheduled macro has run, with the
+// outcome of each of its steps.
+type OBSMacroExecuted struct {
+    EventID   string            `json:"eventId"` // Timeline event that scheduled the macro
+    Title     string            `json:"title"`
+    Success   bool              `json:"success"` // Every step that ran succeeded
+    Steps     []MacroStepResult `json:"steps"`
+    Timestamp time.Time         `json:"timestamp"`
+}
+
+func (e OBSMacroExecuted) GetTopic() string { return "obs.macro.executed" }
+
+// Caption playback states reported by OBSCaptionStatusChanged.
+const (
+    CaptionStatusOff      = "off"      // The program on air has no captions
+    CaptionStatusPlaying  = "playing"  // Cues are being sent to the stream
+    CaptionStatusFinished = "finished" // Every cue of the program has been sent
+    CaptionStatusError    = "error"    // The caption file could not be read, or a cue could not be sent
+)
+
+// OBSCaptionStatusChanged is emitted when the caption playback of the program
+// on air starts, stops, finishes or fails.
+type OBSCaptionStatusChanged struct {
+    ProgramID string    `json:"programId,omitempty"`
+    Title     string    `json:"title,omitempty"`
+    File      string    `json:"file,omitempty"`   // Caption file of the program
+    Status    string    `json:"status"`           // One of the CaptionStatus* constants
+    Cues      int       `json:"cues,omitempty"`   // Number of cues in the file
+    Error     string    `json:"error,omitempty"`
+    Timestamp time.Time `json:"timestamp"`
+}
+
+func (e OBSCaptionStatusChanged) GetTopic() string { return "obs.captions.status" }
+
+// =============================================================================
+// Media Events
+// =============================================================================
+
+// Source health states reported by OBSSourceHealthChanged.
+const (
+    SourceHealthy   = "healthy"
+    SourceUnhealthy = "unhealthy"
+)
+
+// OBSSourceHealthChanged is emitted when the input of the program on air goes
+// down for longer than the configured stall time, and again once it has
+// recovered.
+type OBSSourceHealthChanged struct {
+    ProgramID      string    `json:"programId"`
+    Title          string    `json:"title"`
+    InputName      string    `json:"inputName"`
+    Status         string    `json:"status"`           // SourceHealthy or SourceUnhealthy
+    Reason         string    `json:"reason,omitempty"` // Why the source is down, e.g. its OBS media state
+    FallbackActive bool      `json:"fallbackActive"`   // The fallback source is covering the program
+    Timestamp      time.Time `json:"timestamp"`
+}
+
+func (e OBSSourceHealthChanged) GetTopic() string { return "obs.source.health" }
+
+// Picture anomalies reported by OBSFrameAnomaly.
+const (
+    FrameAnomalyBlack  = "black"        // The picture is black
+    FrameAnomalyFrozen = "frozen"       // The picture has not changed over several screenshots
+    FrameAnomalyStatic = "staticRegion" // A large part of the picture has not changed
+)
+
+// OBSFrameAnomaly is emitted when the program output shows a black, frozen
+// or largely static picture for longer than the configured time, and again
+// when the anomaly clears.
+type OBSFrameAnomaly struct {
+    ProgramID      string    `json:"programId"`
+    Title          string    `json:"title"`
+    Kind           string    `json:"kind"`             // One of the FrameAnomaly* constants
+    Active         bool      `json:"active"`           // False once the anomaly has cleared
+    Detail         string    `json:"detail,omitempty"`
+    Since          time.Time `json:"since"`            // When the anomaly started
+    FallbackActive bool      `json:"fallbackActive"`   // The fallback source is covering the program
+    Timestamp      time.Time `json:"timestamp"`
+}
+
+func (e OBSFrameAnomaly) GetTopic() string { return "obs.frame.anomaly" }
+
+// OBSSilenceChanged is emitted when the program on air stays below the
+// silence threshold for longer than the configured time, and again when its
+// audio is back.
+type OBSSilenceChanged struct {
+    ProgramID      string    `json:"programId"`
+    Title          string    `json:"title"`
+    Silent         bool      `json:"silent"`         // False once audio is back
+    Since          time.Time `json:"since"`          // When the program was last heard
+    FallbackActive bool      `json:"fallbackActive"` // The fallback source is covering the program
+    Timestamp      time.Time `json:"timestamp"`
+}
+
+func (e OBSSilenceChanged) GetTopic() string { return "obs.audio.silence" }
+
+// OBSPreflightResult is emitted when the pre-flight checks of an upcoming
+// program find problems, and again when a later check of the same
+// occurrence passes.
+type OBSPreflightResult struct {
+    ProgramID   string    `json:"programId"`
+    Title       string    `json:"title"`
+    Start       time.Time `json:"start"`              // Start of the checked occurrence
+    Passed      bool      `json:"passed"`
+    Problems    []string  `json:"problems,omitempty"` // What would keep the program from airing
+    WillReplace bool      `json:"willReplace"`        // The fallback airs instead if the problems remain at start
+    Timestamp   time.Time `json:"timestamp"`
+}
+
+func (e OBSPreflightResult) GetTopic() string { return "obs.preflight.result" }
+
+// Thumbnail slots published by OBSThumbnailUpdated.
+const (
+    ThumbnailSlotProgram = "program" // The program on air
+    ThumbnailSlotNext    = "next"    // The last known image of the next program's source
+)
+
+// OBSThumbnailUpdated is emitted when the image of a thumbnail slot changes.
+// An empty Image clears the slot, e.g. when no next program is known.
+type OBSThumbnailUpdated struct {
+    Slot       string    `json:"slot"`                // One of the ThumbnailSlot* constants
+    ProgramID  string    `json:"programId,omitempty"`
+    Title      string    `json:"title,omitempty"`
+    Format     string    `json:"format"`              // "jpg" or "png"
+    Image      []byte    `json:"-"`
+    CapturedAt time.Time `json:"capturedAt"`          // When the image was taken, earlier than Timestamp for a last known image
+    Timestamp  time.Time `json:"timestamp"`
+}
+
+func (e OBSThumbnailUpdated) GetTopic() string { return "obs.thumbnail.updated" }
+
+// OBSSourcePreviewReady is emitted when the input of a source preview has
+// been created in OBS and frames are being sent to the client.
+type OBSSourcePreviewReady struct {
+    PreviewID uint64
+    Timestamp time.Time
+}
+
+func (e OBSSourcePreviewReady) GetTopic() string { return "obs.preview.ready" }
+
+// OBSSourcePreviewFailed is emitted when a source preview cannot be created
+// in OBS or stops producing frames. The preview has already been removed.
+type OBSSourcePreviewFailed struct {
+    PreviewID uint64
+    Error     string
+    Timestamp time.Time
+}
+
+func (e OBSSourcePreviewFailed) GetTopic() string { return "obs.preview.failed" }
+
+// OBSMediaEnded is emitted when the media input of the active program finishes
+// playing. It is only published for the program currently on air.
+type OBSMediaEnded struct {
+    ProgramID  string    `json:"programId"`
+    SourceName string    `json:"sourceName"`
+    Timestamp  time.Time `json:"timestamp"`
+}
+
+func (e OBSMediaEnded) GetTopic() string { return "obs.media.ended" }
+
 // =============================================================================
 // Program Change Events
 // =============================================================================
@@ -17,7 +17,74 @@ type TargetProgramState struct {
 	TargetProgram  *Program
 	NextProgram    *Program
 	SeekOffset     time.Duration
+	Break          *BreakInfo // Set while a break interrupts the scheduled program
+	Tracks         []string   // IDs of the parallel tracks in the schedule, see TrackTargetState
+}
+
+// BreakInfo describes a break interrupting a scheduled program. After the
+// break, the program resumes where it left off.
+type BreakInfo struct {
+	ProgramID    string    `json:"programId"`    // Interrupted program
+	ProgramTitle string    `json:"programTitle"` // Title of the interrupted program
+	Index        int       `json:"index"`        // Break number within the occurrence, from 0
+	Pool         string    `json:"pool"`         // Break pool the items come from
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+}
+
+// GetTopic returns the unique topic identifier for this event.
+func (e TargetProgramState) GetTopic() string { return "scheduler.state.targetProgram" }
+
+// TrackTargetState is published by the Scheduler every evaluation cycle for
+// each parallel track of the schedule, right after TargetProgramState. It
+// declares the desired state of a single track, which is converged
+// independently of the main track and of the other tracks.
+type TrackTargetState struct {
+	Timestamp     time.Time
+	TrackID       string
+	SceneName     string // Sub-scene nested in the schedule scene (empty = derived from TrackID)
+	ZIndex        int    // Stacking relative to the main track, which sits at 0
+	TargetProgram *Program
+	NextProgram   *Program
+	SeekOffset    time.Duration
+}
+
+// GetTopic returns the unique topic identifier for this event.
+func (e TrackTargetState) GetTopic() string { return "scheduler.state.trackTarget" }
+
+// RundownPositionChanged is published by the Scheduler whenever the live
+// segment of a rundown block changes, and once with Active=false when the
+// rundown is left. Web clients use it to show what is live and up next.
+type RundownPositionChanged struct {
+	ProgramID string    `json:"programId"`
+	Title     string    `json:"title"`
+	Active    bool      `json:"active"`
+	Index     int       `json:"index"`
+	Total     int       `json:"total"`
+	Current   *Program  `json:"current,omitempty"`
+	Next      *Program  `json:"next,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// GetTopic returns the unique topic identifier for this event.
+func (e RundownPositionChanged) GetTopic() string { return "scheduler.state.rundownPosition" }
+
+// =============================================================================
+// Scheduler Action Events
+// =============================================================================
+
+// OBSActionRequested is published by the Scheduler when an OBS output action
+// of a program or of a timeline event is due. The OBSClient executes it and
+// records the outcome in the as-run log.
+type OBSActionRequested struct {
+	Action      string      `json:"action"`             // One of the OBSAction* constants
+	SourceID    string      `json:"sourceId"`           // Program or timeline event that scheduled the action
+	Title       string      `json:"title"`              // Title of the program or timeline event
+	Filename    string      `json:"filename,omitempty"` // Recording filename for start and split actions (empty = keep)
+	Steps       []MacroStep `json:"steps,omitempty"`    // Requests of a macro action, in order
+	ScheduledAt time.Time   `json:"scheduledAt"`
+	Timestamp   time.Time   `json:"timestamp"`
 }
 
 // GetTopic returns the unique topic identifier for this event.
-func (e TargetProgramState) GetTopic() string { return "scheduler.state.targetProgram" }
\ No newline at end of file
+func (e OBSActionRequested) GetTopic() string { return "scheduler.action.obs" }
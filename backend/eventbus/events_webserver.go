@@ -120,6 +120,33 @@ type CommitScheduleRequested struct {
 
 func (e CommitScheduleRequested) GetTopic() string { return "webserver.command.commitSchedule" }
 
+// AutoFillScheduleRequested is a command to generate draft programs for the
+// gaps of the current schedule. The result is sent only to the requesting client.
+type AutoFillScheduleRequested struct {
+    ClientID string
+    Payload  json.RawMessage
+}
+
+func (e AutoFillScheduleRequested) GetTopic() string { return "webserver.command.autoFillSchedule" }
+
+// MediaDurationRequested is a command to determine the duration of a media file.
+// The result is sent only to the requesting client.
+type MediaDurationRequested struct {
+    ClientID string
+    Payload  json.RawMessage
+}
+
+func (e MediaDurationRequested) GetTopic() string { return "webserver.command.probeMediaDuration" }
+
+// ExpandScheduleRequested is a command to expand the schedule into concrete
+// occurrences, including breaks, over a time range.
+type ExpandScheduleRequested struct {
+    ClientID string
+    Payload  json.RawMessage
+}
+
+func (e ExpandScheduleRequested) GetTopic() string { return "webserver.command.expandSchedule" }
+
 // GetStatusRequested is a command to request the current status of OBS and VirtualCam.
 type GetStatusRequested struct {
     ClientID string
@@ -127,6 +154,26 @@ type GetStatusRequested struct {
 
 func (e GetStatusRequested) GetTopic() string { return "webserver.command.getStatus" }
 
+// SourcePreviewRequested is a command to preview a source through OBS. The
+// OBS client sends the frames only to the requesting client, until the
+// preview is stopped with SourcePreviewStopRequested.
+type SourcePreviewRequested struct {
+    PreviewID     uint64
+    ClientID      string
+    InputKind     string
+    URI           string
+    InputSettings map[string]interface{}
+}
+
+func (e SourcePreviewRequested) GetTopic() string { return "webserver.command.startSourcePreview" }
+
+// SourcePreviewStopRequested is a command to stop a source preview in OBS.
+type SourcePreviewStopRequested struct {
+    PreviewID uint64
+}
+
+func (e SourcePreviewStopRequested) GetTopic() string { return "webserver.command.stopSourcePreview" }
+
 // StatusResponse is sent in response to GetStatusRequested with the current system status.
 type StatusResponse struct {
     ClientID         string
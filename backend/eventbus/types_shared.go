@@ -1,24 +1,146 @@
 // backend/eventbus/types_shared.go
 package eventbus
 
-import "time"
+import (
+    "encoding/json"
+    "time"
+)
 
 // =============================================================================
 // Shared Domain Types
 // =============================================================================
 
+// Program kinds describe where a Program published by the scheduler comes from.
+const (
+    ProgramKindScheduled = "scheduled" // A program defined in schedule.json
+    ProgramKindFiller    = "filler"    // An item rotated in from the filler pool during a gap
+    ProgramKindDefault   = "default"   // The configured default source
+    ProgramKindSegment   = "segment"   // A segment of a rundown block
+    ProgramKindBreak     = "break"     // An item aired during a break of a scheduled program
+)
+
 // Program represents a program in the schedule with all its properties.
 // This type is used across multiple events (scheduler, OBS client, etc.)
 // to ensure consistency and avoid duplication.
 type Program struct {
     ID            string      `json:"id"`
     Title         string      `json:"title"`
+    Kind          string      `json:"kind,omitempty"`
+    ParentID      string      `json:"parentId,omitempty"` // Program a segment or break item belongs to
     SourceName    string      `json:"sourceName,omitempty"`
-    SceneName     string      `json:"sceneName,omitempty"`
+    SceneName     string      `json:"sceneName,omitempty"` // Existing OBS scene aired instead of an input
+    SceneMode     string      `json:"sceneMode,omitempty"` // How SceneName is aired, see the SceneMode* constants
     InputKind     string      `json:"inputKind,omitempty"`
     URI           string      `json:"uri,omitempty"`
     InputSettings interface{} `json:"inputSettings,omitempty"`
     Transform     interface{} `json:"transform,omitempty"`
+    Audio         *Audio      `json:"audio,omitempty"`         // Audio settings of the input (nil = OBS defaults)
+    Filters       []Filter    `json:"filters,omitempty"`       // Filters added to the input when it is created
+    Captions      string      `json:"captions,omitempty"`      // SRT or WebVTT file sent as stream captions while on air
+    Fallback      *Program    `json:"fallback,omitempty"`      // Source shown over the program while its input is down
+    ExpectSilence bool        `json:"expectSilence,omitempty"` // The program has no audio, so silence is not reported
+    Layers        []Layer     `json:"layers,omitempty"`        // Additional sources composited with the main source
+    Transition    *Transition `json:"transition,omitempty"`    // How the program is brought on air (nil = configured default)
     Start         time.Time   `json:"start,omitempty"`
     End           time.Time   `json:"end,omitempty"`
-}
\ No newline at end of file
+}
+
+// Scene modes for programs that air an existing OBS scene.
+const (
+    SceneModeNested  = "nested"  // The scene is nested as a source inside the schedule scene
+    SceneModeProgram = "program" // The scene becomes the current program scene in OBS
+)
+
+// Layer is an additional source of a composite program. Layers are stacked
+// by ZIndex around the main source, which sits at 0.
+type Layer struct {
+    SourceName    string      `json:"sourceName"`
+    SceneName     string      `json:"sceneName,omitempty"` // Existing OBS scene nested instead of an input
+    InputKind     string      `json:"inputKind"`
+    URI           string      `json:"uri,omitempty"`
+    InputSettings interface{} `json:"inputSettings,omitempty"`
+    Transform     interface{} `json:"transform,omitempty"`
+    Audio         *Audio      `json:"audio,omitempty"`
+    Filters       []Filter    `json:"filters,omitempty"`
+    ZIndex        int         `json:"zIndex,omitempty"`
+}
+
+// Filter is an OBS source filter added to an input when it is created, such
+// as a color correction or a chroma key.
+type Filter struct {
+    Kind     string                 `json:"kind"`               // OBS filter kind (e.g. color_filter_v2, chroma_key_filter_v2)
+    Name     string                 `json:"name"`               // Filter name, unique within the input
+    Settings map[string]interface{} `json:"settings,omitempty"` // Kind-specific filter settings (nil = OBS defaults)
+}
+
+// Audio holds the audio settings applied to an input when it is created.
+type Audio struct {
+    VolumeDb    *float64 `json:"volumeDb,omitempty"`    // Input volume in dB, 0 is unity (nil = unchanged)
+    Muted       bool     `json:"muted,omitempty"`
+    MonitorType string   `json:"monitorType,omitempty"` // One of the AudioMonitor* constants (empty = unchanged)
+    Balance     *float64 `json:"balance,omitempty"`     // 0.0 (left) to 1.0 (right), 0.5 is centered (nil = unchanged)
+}
+
+// Audio monitor types, as named by obs-websocket.
+const (
+    AudioMonitorNone      = "OBS_MONITORING_TYPE_NONE"
+    AudioMonitorOnly      = "OBS_MONITORING_TYPE_MONITOR_ONLY"
+    AudioMonitorAndOutput = "OBS_MONITORING_TYPE_MONITOR_AND_OUTPUT"
+)
+
+// OBS output actions that programs and timeline events can schedule.
+const (
+    OBSActionStartStreaming   = "startStreaming"
+    OBSActionStopStreaming    = "stopStreaming"
+    OBSActionStartRecording   = "startRecording"
+    OBSActionStopRecording    = "stopRecording"
+    OBSActionSplitRecording   = "splitRecording"   // Starts a new recording file without a gap
+    OBSActionSaveReplayBuffer = "saveReplayBuffer" // Requires the replay buffer to be running
+    OBSActionMacro            = "macro"            // Runs the ordered obs-websocket requests of a timeline event
+)
+
+// Error policies of a macro step.
+const (
+    MacroOnErrorStop     = "stop"     // A failed step aborts the rest of the macro (default)
+    MacroOnErrorContinue = "continue" // A failed step is reported and the macro goes on
+)
+
+// MacroStep is one obs-websocket request of a scheduled macro.
+type MacroStep struct {
+    RequestType string                 `json:"requestType"`           // obs-websocket request name, e.g. "TriggerHotkeyByName"
+    RequestData map[string]interface{} `json:"requestData,omitempty"` // Request fields as documented by obs-websocket
+    OnError     string                 `json:"onError,omitempty"`     // One of the MacroOnError* constants
+    DelayMs     int                    `json:"delayMs,omitempty"`     // Wait before the step is sent
+}
+
+// MacroStepResult is the outcome of one macro step.
+type MacroStepResult struct {
+    RequestType  string          `json:"requestType"`
+    Status       string          `json:"status"` // One of the MacroStep* status constants
+    Error        string          `json:"error,omitempty"`
+    ResponseData json.RawMessage `json:"responseData,omitempty"`
+    DurationMs   int64           `json:"durationMs"`
+}
+
+// Status values of a MacroStepResult.
+const (
+    MacroStepOK      = "ok"
+    MacroStepFailed  = "failed"
+    MacroStepSkipped = "skipped" // Not run because an earlier step failed with the stop policy
+)
+
+// Transition types supported by the OBS switcher.
+const (
+    TransitionCut     = "cut"     // The new program replaces the previous one instantly
+    TransitionFade    = "fade"    // The new program fades in over the previous one
+    TransitionStinger = "stinger" // A media clip plays over the switch
+)
+
+// Transition describes how a program replaces the one before it.
+type Transition struct {
+    Type           string `json:"type"`                     // One of the Transition* constants
+    DurationMs     int    `json:"durationMs,omitempty"`     // Length of the fade, or of the stinger clip
+    StingerURI     string `json:"stingerUri,omitempty"`     // Stinger clip (empty = configured default)
+    StingerPointMs int    `json:"stingerPointMs,omitempty"` // Point in the stinger clip where the cut happens
+    AudioFadeMs    int    `json:"audioFadeMs,omitempty"`    // Audio crossfade between the programs (0 = configured default)
+}
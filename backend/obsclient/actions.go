@@ -0,0 +1,304 @@
+// backend/obsclient/actions.go
+//
+// This file executes the OBS output actions scheduled by programs and
+// timeline events: starting and stopping the stream and the recording,
+// splitting the recording per program, saving the replay buffer and running
+// macros (see macros.go). Every action is recorded in the as-run log along
+// with its outcome.
+//
+// Contents:
+// - Action Execution
+// - Recording Helpers
+// - Output State Events
+
+package obsclient
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andreykaipov/goobs"
+	obsconfig "github.com/andreykaipov/goobs/api/requests/config"
+	"scenescheduler/backend/eventbus"
+)
+
+const (
+	// recordStateTimeout bounds how long an action waits for the recording
+	// to start, stop or switch to a new file.
+	recordStateTimeout = 10 * time.Second
+
+	// recordPollInterval is how often the record status is checked while
+	// waiting for the recording to start or stop.
+	recordPollInterval = 200 * time.Millisecond
+
+	// filenameTimestamp is appended to recording filenames so recordings of
+	// the same program never overwrite each other. See the OBS
+	// "Filename Formatting" setting for the available specifiers.
+	filenameTimestamp = " %CCYY-%MM-%DD %hh-%mm-%ss"
+)
+
+// OBS output states reported by StreamStateChanged and RecordStateChanged.
+const (
+	outputStateStarted = "OBS_WEBSOCKET_OUTPUT_STARTED"
+	outputStateStopped = "OBS_WEBSOCKET_OUTPUT_STOPPED"
+)
+
+// ============================================================================
+// ACTION EXECUTION
+// ============================================================================
+
+// executeAction runs one scheduled action and records it in the as-run log.
+// Actions are serialized so a split cannot interleave with a stop.
+func (c *OBSClient) executeAction(event eventbus.OBSActionRequested) {
+	c.actionMu.Lock()
+	defer c.actionMu.Unlock()
+
+	client, _ := c.getActiveClientAndContext()
+	if client == nil {
+		c.recordAction(event, ErrNotConnected)
+		return
+	}
+
+	var err error
+	switch event.Action {
+	case eventbus.OBSActionStartStreaming:
+		_, err = client.Stream.StartStream()
+	case eventbus.OBSActionStopStreaming:
+		_, err = client.Stream.StopStream()
+	case eventbus.OBSActionStartRecording:
+		err = c.startRecording(client, event.Filename)
+	case eventbus.OBSActionStopRecording:
+		_, err = client.Record.StopRecord()
+	case eventbus.OBSActionSplitRecording:
+		err = c.splitRecording(client, event.Filename)
+	case eventbus.OBSActionSaveReplayBuffer:
+		_, err = client.Outputs.SaveReplayBuffer()
+	case eventbus.OBSActionMacro:
+		err = c.runMacro(client, event)
+	default:
+		err = fmt.Errorf("unknown action %q", event.Action)
+	}
+
+	if err != nil {
+		c.logger.WarnGui("Scheduled OBS action failed",
+			"action", event.Action,
+			"source", event.SourceID,
+			"error", err)
+	} else {
+		c.logger.InfoGui("Scheduled OBS action executed",
+			"action", event.Action,
+			"source", event.SourceID)
+	}
+	c.recordAction(event, err)
+}
+
+// recordAction appends the outcome of an action to the as-run log.
+func (c *OBSClient) recordAction(event eventbus.OBSActionRequested, actionErr error) {
+	entry := asRunEntry{
+		Timestamp: time.Now(),
+		Event:     asRunEventAction,
+		Action:    event.Action,
+		ProgramID: event.SourceID,
+		Title:     event.Title,
+	}
+	if actionErr != nil {
+		entry.Error = actionErr.Error()
+	}
+	if err := c.asRun.append(entry); err != nil {
+		c.logger.Warn("Failed to record as-run entry", "error", err)
+	}
+}
+
+// ============================================================================
+// RECORDING HELPERS
+// ============================================================================
+
+// startRecording starts the recording with a file named after the program.
+// The profile's filename format is restored once the recording has started.
+func (c *OBSClient) startRecording(client *goobs.Client, filename string) error {
+	restore, err := c.setRecordingFilename(client, filename)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	if _, err := client.Record.StartRecord(); err != nil {
+		return err
+	}
+	return waitForRecordState(client, true)
+}
+
+// splitRecording starts a new recording file named after the next program.
+// OBS splits natively when the recording output supports it; otherwise the
+// recording is stopped and restarted, leaving a short gap. A recording that
+// is not running is simply started. Either way, the profile's filename
+// format is restored once OBS writes to the new file.
+func (c *OBSClient) splitRecording(client *goobs.Client, filename string) error {
+	restore, err := c.setRecordingFilename(client, filename)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	status, err := client.Record.GetRecordStatus()
+	if err != nil {
+		return fmt.Errorf("failed to query record status: %w", err)
+	}
+	if !status.OutputActive {
+		if _, err := client.Record.StartRecord(); err != nil {
+			return err
+		}
+		return waitForRecordState(client, true)
+	}
+
+	// Forget file changes reported before this split
+	select {
+	case <-c.recordFileCh:
+	default:
+	}
+
+	_, err = client.Record.SplitRecordFile()
+	if err == nil {
+		select {
+		case <-c.recordFileCh:
+		case <-time.After(recordStateTimeout):
+			c.logger.Debug("OBS did not report the new recording file", "timeout", recordStateTimeout)
+		}
+		return nil
+	}
+	c.logger.Debug("Native record split unavailable, restarting the recording", "error", err)
+
+	if _, err := client.Record.StopRecord(); err != nil {
+		return fmt.Errorf("failed to stop recording: %w", err)
+	}
+	if err := waitForRecordState(client, false); err != nil {
+		return err
+	}
+	if _, err := client.Record.StartRecord(); err != nil {
+		return err
+	}
+	return waitForRecordState(client, true)
+}
+
+// waitForRecordState polls the record status until the recording is active
+// (or inactive), or recordStateTimeout has passed.
+func waitForRecordState(client *goobs.Client, active bool) error {
+	deadline := time.Now().Add(recordStateTimeout)
+	for {
+		status, err := client.Record.GetRecordStatus()
+		if err != nil {
+			return fmt.Errorf("failed to query record status: %w", err)
+		}
+		if status.OutputActive == active {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			if active {
+				return fmt.Errorf("recording did not start within %s", recordStateTimeout)
+			}
+			return fmt.Errorf("recording did not stop within %s", recordStateTimeout)
+		}
+		time.Sleep(recordPollInterval)
+	}
+}
+
+// setRecordingFilename sets the filename format of the next recording file
+// in the current OBS profile. It returns a function that puts the previous
+// format back, so later recordings, including manual ones, are named as the
+// operator configured them. An empty name keeps the profile setting.
+func (c *OBSClient) setRecordingFilename(client *goobs.Client, name string) (func(), error) {
+	name = sanitizeFilename(name)
+	if name == "" {
+		return func() {}, nil
+	}
+
+	category, parameter := "Output", "FilenameFormatting"
+	previous, err := client.Config.GetProfileParameter(&obsconfig.GetProfileParameterParams{
+		ParameterCategory: &category,
+		ParameterName:     &parameter,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording filename: %w", err)
+	}
+	original := previous.ParameterValue
+	if original == "" {
+		original = previous.DefaultParameterValue
+	}
+
+	if err := setFilenameFormatting(client, name+filenameTimestamp); err != nil {
+		return nil, fmt.Errorf("failed to set recording filename: %w", err)
+	}
+	return func() {
+		if err := setFilenameFormatting(client, original); err != nil {
+			c.logger.Warn("Failed to restore the recording filename format", "format", original, "error", err)
+		}
+	}, nil
+}
+
+// setFilenameFormatting writes the recording filename format of the current
+// OBS profile.
+func setFilenameFormatting(client *goobs.Client, format string) error {
+	category, parameter := "Output", "FilenameFormatting"
+	_, err := client.Config.SetProfileParameter(&obsconfig.SetProfileParameterParams{
+		ParameterCategory: &category,
+		ParameterName:     &parameter,
+		ParameterValue:    &format,
+	})
+	return err
+}
+
+// sanitizeFilename removes characters that are invalid in filenames on any
+// platform, as well as "%", which OBS interprets as a format specifier.
+func sanitizeFilename(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r < 0x20, strings.ContainsRune(`<>:"/\|?*%`, r):
+			return '_'
+		}
+		return r
+	}, name)
+	return strings.Trim(strings.TrimSpace(name), ".")
+}
+
+// ============================================================================
+// OUTPUT STATE EVENTS
+// ============================================================================
+
+// handleStreamStateChanged publishes OBSStreamStateChanged once the stream
+// has actually started or stopped. Intermediate states are ignored.
+func (c *OBSClient) handleStreamStateChanged(outputActive bool, outputState string) {
+	if outputState != outputStateStarted && outputState != outputStateStopped {
+		return
+	}
+	c.logger.Debug("Event from OBS: Stream state changed", "active", outputActive)
+	eventbus.Publish(c.bus, eventbus.OBSStreamStateChanged{
+		IsStreaming: outputActive,
+		Timestamp:   time.Now(),
+	})
+}
+
+// handleRecordFileChanged wakes a split waiting for OBS to switch to the new
+// recording file.
+func (c *OBSClient) handleRecordFileChanged(outputPath string) {
+	c.logger.Debug("Event from OBS: Record file changed", "path", outputPath)
+	select {
+	case c.recordFileCh <- struct{}{}:
+	default:
+	}
+}
+
+// handleRecordStateChanged publishes OBSRecordStateChanged once the
+// recording has actually started or stopped, with the file written when it
+// stops. Intermediate states such as paused are ignored.
+func (c *OBSClient) handleRecordStateChanged(outputActive bool, outputState, outputPath string) {
+	if outputState != outputStateStarted && outputState != outputStateStopped {
+		return
+	}
+	c.logger.Debug("Event from OBS: Record state changed", "active", outputActive, "path", outputPath)
+	eventbus.Publish(c.bus, eventbus.OBSRecordStateChanged{
+		IsRecording: outputActive,
+		OutputPath:  outputPath,
+		Timestamp:   time.Now(),
+	})
+}
@@ -0,0 +1,114 @@
+// backend/obsclient/asrun.go
+//
+// This file implements the as-run log: an append-only JSON Lines record of
+// every program that actually went on air, as opposed to what was scheduled,
+// of every scheduled output action (streaming, recording) with its outcome,
+// and of the loudness measured for each program.
+// Breaks and their items appear with their kind and parent program, so the
+// log shows where each program was interrupted and resumed.
+//
+// Contents:
+// - As-Run Entry
+// - As-Run Writer
+
+package obsclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"scenescheduler/backend/eventbus"
+)
+
+// ============================================================================
+// AS-RUN ENTRY
+// ============================================================================
+
+// Kinds of as-run entries.
+const (
+	asRunEventSwitch   = "switch"   // A program went on air
+	asRunEventAction   = "action"   // A scheduled output action was executed
+	asRunEventLoudness = "loudness" // Loudness measured while a program was on air
+)
+
+// asRunEntry is one line of the as-run log.
+type asRunEntry struct {
+	Timestamp         time.Time `json:"timestamp"`
+	Event             string    `json:"event"`           // One of the asRunEvent* constants
+	Track             string    `json:"track,omitempty"` // Parallel track (empty = main track)
+	ProgramID         string    `json:"programId,omitempty"`
+	ParentID          string    `json:"parentId,omitempty"`
+	Title             string    `json:"title,omitempty"`
+	Kind              string    `json:"kind,omitempty"`
+	SourceName        string    `json:"sourceName,omitempty"`
+	URI               string    `json:"uri,omitempty"`
+	SeekOffsetMs      int64     `json:"seekOffsetMs,omitempty"`
+	PreviousProgramID string    `json:"previousProgramId,omitempty"`
+	Action            string    `json:"action,omitempty"`       // Output action, for action entries
+	Error             string    `json:"error,omitempty"`        // Why the action failed
+	LoudnessLUFS      float64   `json:"loudnessLufs,omitempty"` // Loudness of the media itself, before any correction
+	GainDb            float64   `json:"gainDb,omitempty"`       // Loudness correction applied when the program left the air
+}
+
+// newAsRunEntry builds the entry for a completed switch. A nil current
+// program is recorded as an entry without a program (the scene was cleared).
+func newAsRunEntry(event eventbus.OBSProgramChanged) asRunEntry {
+	entry := asRunEntry{
+		Timestamp:    event.Timestamp,
+		Event:        asRunEventSwitch,
+		SeekOffsetMs: event.SeekOffsetMs,
+	}
+	if p := event.CurrentProgram; p != nil {
+		entry.ProgramID = p.ID
+		entry.ParentID = p.ParentID
+		entry.Title = p.Title
+		entry.Kind = p.Kind
+		entry.SourceName = p.SourceName
+		entry.URI = p.URI
+	}
+	if event.PreviousProgram != nil {
+		entry.PreviousProgramID = event.PreviousProgram.ID
+	}
+	return entry
+}
+
+// ============================================================================
+// AS-RUN WRITER
+// ============================================================================
+
+// asRunLog appends entries to the as-run file. The file is opened for each
+// entry so it can be rotated or moved by external tools at any time.
+type asRunLog struct {
+	mu   sync.Mutex
+	path string // Empty disables the log
+}
+
+// append writes one entry as a single JSON line.
+func (l *asRunLog) append(entry asRunEntry) error {
+	if l == nil || l.path == "" {
+		return nil
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("could not encode as-run entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("could not open as-run log '%s': %w", l.path, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(line); err != nil {
+		return fmt.Errorf("could not write as-run log '%s': %w", l.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+// backend/obsclient/captions.go
+//
+// This file plays the closed captions of the program on air. A program may
+// reference an SRT or WebVTT file; while it is on air, each cue is sent to
+// the stream output with the SendStreamCaption request at its time, counted
+// from the start of the media. A program joined late skips the cues before
+// its seek offset. Playback stops, and the last caption is cleared, when the
+// program leaves the air.
+//
+// Only the main track is captioned, as OBS has a single stream output.
+//
+// Contents:
+// - Caption Playback
+// - Caption Status
+// - Caption Parsing
+
+package obsclient
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andreykaipov/goobs/api/requests/stream"
+	"scenescheduler/backend/eventbus"
+)
+
+// captionCue is one timed caption, relative to the start of the media.
+type captionCue struct {
+	start time.Duration
+	end   time.Duration
+	text  string
+}
+
+// captionPlayer is the playback of one program's captions.
+type captionPlayer struct {
+	program *eventbus.Program
+	cues    []captionCue
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	status  string // Last published status and error, so only changes are published
+	lastErr string
+}
+
+// ============================================================================
+// CAPTION PLAYBACK
+// ============================================================================
+
+// startCaptions stops the captions of the previous program and starts those
+// of the program now on air. airedAt is when the program went on air and
+// offset how far into its media it was joined.
+func (c *OBSClient) startCaptions(program *eventbus.Program, airedAt time.Time, offset time.Duration) {
+	c.stopCaptions()
+	if program == nil || program.Captions == "" {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	player := &captionPlayer{program: program, cancel: cancel, done: make(chan struct{})}
+
+	// The player is kept even if the file cannot be read, so the error
+	// status is cleared when the program leaves the air
+	c.captionsMu.Lock()
+	c.captions = player
+	c.captionsMu.Unlock()
+
+	cues, err := loadCaptions(program.Captions)
+	if err != nil {
+		c.logger.WarnGui("Could not load captions, the program airs without them",
+			"program", getProgramTitle(program),
+			"file", program.Captions,
+			"error", err)
+		player.publishStatus(c.bus, eventbus.CaptionStatusError, err.Error())
+		close(player.done)
+		return
+	}
+	player.cues = cues
+
+	c.logger.Info("Starting captions",
+		"program", getProgramTitle(program),
+		"file", program.Captions,
+		"cues", len(cues),
+		"offset", offset)
+	player.publishStatus(c.bus, eventbus.CaptionStatusPlaying, "")
+
+	go c.playCaptions(ctx, player, airedAt.Add(-offset))
+}
+
+// stopCaptions stops the current caption playback, if any, and clears the
+// caption shown on the stream.
+func (c *OBSClient) stopCaptions() {
+	c.captionsMu.Lock()
+	player := c.captions
+	c.captions = nil
+	c.captionsMu.Unlock()
+
+	if player == nil {
+		return
+	}
+	player.cancel()
+	<-player.done
+
+	// CLEANUP: Best-effort, the stream may not be running
+	_ = c.sendCaption("")
+	player.publishStatus(c.bus, eventbus.CaptionStatusOff, "")
+	c.logger.Debug("Captions stopped", "program", getProgramTitle(player.program))
+}
+
+// playCaptions sends the cues of a program at their time. mediaStart is the
+// wall-clock time at which the media would have started playing. A caption
+// is cleared at the end of its cue unless the next cue follows directly.
+func (c *OBSClient) playCaptions(ctx context.Context, player *captionPlayer, mediaStart time.Time) {
+	defer close(player.done)
+
+	for i, cue := range player.cues {
+		if !mediaStart.Add(cue.end).After(time.Now()) {
+			continue // Joined after the cue ended
+		}
+		if !sleepUntil(ctx, mediaStart.Add(cue.start)) {
+			return
+		}
+		c.sendCue(player, cue.text)
+
+		if i+1 < len(player.cues) && player.cues[i+1].start <= cue.end {
+			continue
+		}
+		if !sleepUntil(ctx, mediaStart.Add(cue.end)) {
+			return
+		}
+		c.sendCue(player, "")
+	}
+	player.publishStatus(c.bus, eventbus.CaptionStatusFinished, "")
+}
+
+// sendCue sends one caption. Failures, e.g. while the stream is not running,
+// are reported once until a send succeeds again.
+func (c *OBSClient) sendCue(player *captionPlayer, text string) {
+	if err := c.sendCaption(text); err != nil {
+		if player.publishStatus(c.bus, eventbus.CaptionStatusError, err.Error()) {
+			c.logger.Warn("Failed to send caption", "program", getProgramTitle(player.program), "error", err)
+		}
+		return
+	}
+	player.publishStatus(c.bus, eventbus.CaptionStatusPlaying, "")
+}
+
+// sendCaption sends caption text to the stream output. An empty text clears
+// the caption.
+func (c *OBSClient) sendCaption(text string) error {
+	client, _ := c.getActiveClientAndContext()
+	if client == nil {
+		return ErrNotConnected
+	}
+	_, err := client.Stream.SendStreamCaption(&stream.SendStreamCaptionParams{CaptionText: &text})
+	return err
+}
+
+// sleepUntil waits until t, returning false if the context is cancelled first.
+func sleepUntil(ctx context.Context, t time.Time) bool {
+	wait := time.Until(t)
+	if wait <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// ============================================================================
+// CAPTION STATUS
+// ============================================================================
+
+// publishStatus publishes the caption status for web clients and reports
+// whether it changed. A player's status is only published by one goroutine
+// at a time: the playback while it runs, then the switch that stopped it.
+func (p *captionPlayer) publishStatus(bus *eventbus.EventBus, status, errMsg string) bool {
+	if status == p.status && errMsg == p.lastErr {
+		return false
+	}
+	p.status, p.lastErr = status, errMsg
+
+	eventbus.Publish(bus, eventbus.OBSCaptionStatusChanged{
+		ProgramID: p.program.ID,
+		Title:     p.program.Title,
+		File:      p.program.Captions,
+		Status:    status,
+		Cues:      len(p.cues),
+		Error:     errMsg,
+		Timestamp: time.Now(),
+	})
+	return true
+}
+
+// ============================================================================
+// CAPTION PARSING
+// ============================================================================
+
+// captionTag matches the markup of WebVTT cues (<b>, <v Speaker>, <00:01.000>)
+// and the font tags some SRT files carry.
+var captionTag = regexp.MustCompile(`<[^>]*>`)
+
+// loadCaptions reads an SRT or WebVTT file. Both formats are blocks separated
+// by blank lines, where a cue is a "start --> end" line followed by its text;
+// blocks without a timing line (WEBVTT header, NOTE, STYLE) are skipped.
+func loadCaptions(path string) ([]captionCue, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var cues []captionCue
+	var current *captionCue
+	var text []string
+	flush := func() {
+		if current != nil && len(text) > 0 {
+			current.text = strings.Join(text, "\n")
+			cues = append(cues, *current)
+		}
+		current, text = nil, nil
+	}
+
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		switch {
+		case line == "":
+			flush()
+		case current == nil && strings.Contains(line, "-->"):
+			cue, err := parseCueTiming(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			current = &cue
+		case current != nil:
+			if stripped := strings.TrimSpace(captionTag.ReplaceAllString(line, "")); stripped != "" {
+				text = append(text, stripped)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	if len(cues) == 0 {
+		return nil, fmt.Errorf("no caption cues found")
+	}
+	return cues, nil
+}
+
+// parseCueTiming parses a "start --> end" line. WebVTT cue settings after
+// the end time are ignored.
+func parseCueTiming(line string) (captionCue, error) {
+	startText, rest, _ := strings.Cut(line, "-->")
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return captionCue{}, fmt.Errorf("missing cue end time")
+	}
+	start, err := parseCaptionTime(strings.TrimSpace(startText))
+	if err != nil {
+		return captionCue{}, err
+	}
+	end, err := parseCaptionTime(fields[0])
+	if err != nil {
+		return captionCue{}, err
+	}
+	if end <= start {
+		return captionCue{}, fmt.Errorf("cue ends before it starts")
+	}
+	return captionCue{start: start, end: end}, nil
+}
+
+// parseCaptionTime parses "hh:mm:ss,mmm" (SRT) and "[hh:]mm:ss.mmm" (WebVTT).
+func parseCaptionTime(value string) (time.Duration, error) {
+	clock, fraction, _ := strings.Cut(strings.Replace(value, ",", ".", 1), ".")
+	parts := strings.Split(clock, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid cue time %q", value)
+	}
+
+	var total time.Duration
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid cue time %q", value)
+		}
+		total = total*60 + time.Duration(n)*time.Second
+	}
+	if fraction != "" {
+		ms, err := strconv.Atoi((fraction + "00")[:3])
+		if err != nil || ms < 0 {
+			return 0, fmt.Errorf("invalid cue time %q", value)
+		}
+		total += time.Duration(ms) * time.Millisecond
+	}
+	return total, nil
+}
@@ -0,0 +1,121 @@
+package obsclient
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCaptionTime(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "00:00:01,500", want: 1500 * time.Millisecond},
+		{value: "01:02:03.004", want: time.Hour + 2*time.Minute + 3*time.Second + 4*time.Millisecond},
+		{value: "02:03.250", want: 2*time.Minute + 3*time.Second + 250*time.Millisecond},
+		{value: "00:00:05", want: 5 * time.Second},
+		{value: "00:00:01.5", want: 1500 * time.Millisecond},
+		{value: "12", wantErr: true},
+		{value: "1:2:3:4", wantErr: true},
+		{value: "00:xx:01.000", wantErr: true},
+		{value: "00:-1:01.000", wantErr: true},
+		{value: "00:00:01.abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseCaptionTime(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("parseCaptionTime(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadCaptions(t *testing.T) {
+	ms := func(v int) time.Duration { return time.Duration(v) * time.Millisecond }
+
+	tests := []struct {
+		name    string
+		content string
+		want    []captionCue
+		wantErr string // Substring of the error, if any
+	}{
+		{
+			name: "srt",
+			content: "\ufeff1\r\n00:00:01,000 --> 00:00:02,500\r\nHello\r\n\r\n" +
+				"2\r\n00:00:03,000 --> 00:00:04,000\r\nTwo\r\nlines\r\n",
+			want: []captionCue{
+				{start: ms(1000), end: ms(2500), text: "Hello"},
+				{start: ms(3000), end: ms(4000), text: "Two\nlines"},
+			},
+		},
+		{
+			name: "webvtt with settings, identifiers and markup",
+			content: "WEBVTT\n\nNOTE a comment\n\nintro\n00:01.000 --> 00:02.000 align:start line:0\n" +
+				"<v Anna>Hi <b>there</b></v>\n\n00:02.000 --> 00:03.000\n<i></i>\n\n" +
+				"01:00:00.000 --> 01:00:01.000\nLate\n",
+			want: []captionCue{
+				{start: ms(1000), end: ms(2000), text: "Hi there"},
+				{start: time.Hour, end: time.Hour + ms(1000), text: "Late"},
+			},
+		},
+		{
+			name:    "cue ending before it starts",
+			content: "1\n00:00:02,000 --> 00:00:01,000\nBackwards\n",
+			wantErr: "line 2: cue ends before it starts",
+		},
+		{
+			name:    "missing end time",
+			content: "WEBVTT\n\n00:01.000 -->\nText\n",
+			wantErr: "line 3: missing cue end time",
+		},
+		{
+			name:    "invalid time",
+			content: "1\n00:00:aa,000 --> 00:00:01,000\nText\n",
+			wantErr: "line 2: invalid cue time",
+		},
+		{
+			name:    "no cues",
+			content: "WEBVTT\n\n",
+			wantErr: "no caption cues found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "captions.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			cues, err := loadCaptions(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to mention %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(cues, tt.want) {
+				t.Errorf("cues = %+v, want %+v", cues, tt.want)
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := loadCaptions(filepath.Join(t.TempDir(), "missing.srt")); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
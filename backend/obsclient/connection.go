@@ -21,6 +21,7 @@ import (
 
 	"github.com/andreykaipov/goobs"
 	"github.com/andreykaipov/goobs/api/events"
+	"github.com/andreykaipov/goobs/api/events/subscriptions"
 	"github.com/gorilla/websocket"
 	"scenescheduler/backend/eventbus"
 )
@@ -43,12 +44,17 @@ func (c *OBSClient) connect() error {
 		HandshakeTimeout: 5 * time.Second,
 	}
 
-	client, err := goobs.New(
-		fmt.Sprintf("%s:%d", c.config.Host, c.config.Port),
+	options := []goobs.Option{
 		goobs.WithPassword(c.config.Password),
-		goobs.WithResponseTimeout(5*time.Second),
+		goobs.WithResponseTimeout(5 * time.Second),
 		goobs.WithDialer(dialer),
-	)
+	}
+	if c.loudness != nil || c.silence != nil {
+		// Volume meters are high-volume events and must be requested explicitly
+		options = append(options, goobs.WithEventSubscriptions(subscriptions.All|subscriptions.InputVolumeMeters))
+	}
+
+	client, err := goobs.New(fmt.Sprintf("%s:%d", c.config.Host, c.config.Port), options...)
 	if err != nil {
 		return fmt.Errorf("failed to connect and authenticate: %w", err)
 	}
@@ -103,6 +109,9 @@ func (c *OBSClient) disconnect(reason string) {
 	}
 
 	c.connection = nil
+	if c.loudness != nil {
+		c.loudness.reset()
+	}
 
 	eventbus.Publish(c.bus, eventbus.OBSDisconnected{
 		Error:     errors.New(reason),
@@ -178,11 +187,44 @@ func (c *OBSClient) processOBSEvent(event any) {
 			c.logger.Debug("Event from OBS: Virtualcam Stopped")
 			eventbus.Publish(c.bus, eventbus.OBSVirtualCamStopped{Timestamp: time.Now()})
 		}
+	case *events.MediaInputPlaybackEnded:
+		c.handleMediaPlaybackEnded(e.InputName)
+	case *events.StreamStateChanged:
+		c.handleStreamStateChanged(e.OutputActive, e.OutputState)
+	case *events.RecordStateChanged:
+		c.handleRecordStateChanged(e.OutputActive, e.OutputState, e.OutputPath)
+	case *events.RecordFileChanged:
+		c.handleRecordFileChanged(e.NewOutputPath)
+	case *events.InputVolumeMeters:
+		c.handleVolumeMeters(e.Inputs)
+		if c.silence != nil {
+			c.silence.hear(e.Inputs, time.Now())
+		}
 	default:
 		// Other events can be handled here.
 	}
 }
 
+// handleMediaPlaybackEnded publishes OBSMediaEnded when the input that ended
+// belongs to the program currently on air. Media ending in other inputs,
+// such as sources staged in the aux scene, is ignored.
+func (c *OBSClient) handleMediaPlaybackEnded(inputName string) {
+	c.stateMu.RLock()
+	active := c.activeProgram
+	c.stateMu.RUnlock()
+
+	if active == nil || inputName != c.config.SourceNamePrefix+active.SourceName {
+		return
+	}
+
+	c.logger.Debug("Event from OBS: Media playback ended", "inputName", inputName, "program", getProgramTitle(active))
+	eventbus.Publish(c.bus, eventbus.OBSMediaEnded{
+		ProgramID:  active.ID,
+		SourceName: active.SourceName,
+		Timestamp:  time.Now(),
+	})
+}
+
 // ============================================================================
 // INITIAL STATE SYNCHRONIZATION
 // ============================================================================
@@ -195,6 +237,18 @@ func (c *OBSClient) checkInitialState() {
 		return
 	}
 
+	// Check stream and record status, so clients start from the real state.
+	if stream, err := client.Stream.GetStreamStatus(); err != nil {
+		c.logger.Warn("Could not get initial stream status", "error", err)
+	} else {
+		eventbus.Publish(c.bus, eventbus.OBSStreamStateChanged{IsStreaming: stream.OutputActive, Timestamp: time.Now()})
+	}
+	if record, err := client.Record.GetRecordStatus(); err != nil {
+		c.logger.Warn("Could not get initial record status", "error", err)
+	} else {
+		eventbus.Publish(c.bus, eventbus.OBSRecordStateChanged{IsRecording: record.OutputActive, Timestamp: time.Now()})
+	}
+
 	// Check Virtual Camera status.
 	resp, err := client.Outputs.GetVirtualCamStatus()
 	if err != nil {
@@ -27,12 +27,17 @@ import (
 // level orchestrator, delegating complex tasks to specialized internal components.
 type OBSClient struct {
 	// --- Configuration and Dependencies ---
-	logger *logger.Logger
-	config *config.OBSConfig
-	bus    *eventbus.EventBus
+	logger     *logger.Logger
+	baseLogger *logger.Logger // Unscoped logger, for components created after construction
+	config     *config.OBSConfig
+	bus        *eventbus.EventBus
 
 	// --- Internal Components ---
-	switcher *switcher.Switcher
+	switcher  *switcher.Switcher
+	asRun     *asRunLog
+	loudness  *loudnessNormalizer // nil when loudness normalization is disabled
+	silence   *silenceDetector    // nil when silence detection is disabled
+	preflight *preflightChecker   // nil when pre-flight checks are disabled
 
 	// --- Lifecycle Management ---
 	ctx              context.Context
@@ -43,13 +48,31 @@ type OBSClient struct {
 	cleanupOnce      sync.Once
 
 	// --- Synchronization ---
-	stateMu  sync.RWMutex // Protects state, connection, and activeProgram
-	switchMu sync.Mutex   // Serializes all convergence operations to prevent races
+	stateMu      sync.RWMutex  // Protects state, connection, activeProgram, and nextProgram
+	switchMu     sync.Mutex    // Serializes all convergence operations to prevent races
+	actionMu     sync.Mutex    // Serializes scheduled output actions (streaming, recording)
+	recordFileCh chan struct{} // Signalled when OBS starts writing a new recording file
 
 	// --- Internal State (protected by stateMu) ---
 	state         State
 	connection    *connection
 	activeProgram *eventbus.Program // Holds the currently active program
+	nextProgram   *eventbus.Program // Holds the next program announced by the scheduler
+
+	// --- Captions of the main track (protected by captionsMu) ---
+	captionsMu sync.Mutex
+	captions   *captionPlayer
+
+	// --- Fallback shown over the main track (protected by switchMu) ---
+	cover fallbackCover
+
+	// --- Source previews rendered in OBS (protected by previewsMu) ---
+	previewsMu sync.Mutex
+	previews   map[uint64]*sourcePreview // Keyed by preview ID
+
+	// --- Parallel Tracks (protected by tracksMu) ---
+	tracksMu sync.Mutex
+	tracks   map[string]*trackState // Keyed by track ID
 }
 
 // ============================================================================
@@ -63,20 +86,36 @@ type OBSClient struct {
 //   - appCtx: Parent context for lifecycle management
 //   - log: Logger instance
 //   - cfg: OBS connection configuration
+//   - paths: File paths configuration (as-run log)
 //   - bus: EventBus for inter-module communication
 //
 // Returns:
 //   - *OBSClient: Configured OBSClient instance ready to Run()
-func New(appCtx context.Context, log *logger.Logger, cfg *config.OBSConfig, bus *eventbus.EventBus) *OBSClient {
+func New(appCtx context.Context, log *logger.Logger, cfg *config.OBSConfig, paths *config.PathsConfig, bus *eventbus.EventBus) *OBSClient {
 	c := &OBSClient{
 		logger:           log.WithModule("obsclient"),
+		baseLogger:       log,
 		config:           cfg,
 		bus:              bus,
 		switcher:         switcher.New(log, cfg),
+		asRun:            &asRunLog{path: paths.AsRunLog},
 		signalCh:         make(chan struct{}, 1),
+		recordFileCh:     make(chan struct{}, 1),
 		unsubscribeFuncs: make([]func(), 0),
 		state:            StateDisconnected,
 		activeProgram:    nil, // Starts with no active program
+		tracks:           make(map[string]*trackState),
+		previews:         make(map[uint64]*sourcePreview),
+	}
+
+	if cfg.Loudness.Enabled {
+		c.loudness = newLoudnessNormalizer(cfg.Loudness)
+	}
+	if cfg.Silence.Enabled {
+		c.silence = newSilenceDetector(cfg.Silence)
+	}
+	if cfg.Preflight.Enabled {
+		c.preflight = newPreflightChecker(cfg.Preflight)
 	}
 
 	// Create derived context for this module's lifecycle
@@ -36,6 +36,41 @@ func (c *OBSClient) subscribeToEvents() {
 		return
 	}
 	c.unsubscribeFuncs = append(c.unsubscribeFuncs, unsub2)
+
+	unsub3, err3 := eventbus.Subscribe(c.bus, "ObsClient", c.handleMediaDurationRequested)
+	if err3 != nil {
+		c.logger.Error("Failed to subscribe to MediaDurationRequested", "error", err3)
+		return
+	}
+	c.unsubscribeFuncs = append(c.unsubscribeFuncs, unsub3)
+
+	unsub4, err4 := eventbus.Subscribe(c.bus, "ObsClient", c.handleTrackTargetState)
+	if err4 != nil {
+		c.logger.Error("Failed to subscribe to TrackTargetState", "error", err4)
+		return
+	}
+	c.unsubscribeFuncs = append(c.unsubscribeFuncs, unsub4)
+
+	unsub5, err5 := eventbus.Subscribe(c.bus, "ObsClient", c.handleOBSActionRequested)
+	if err5 != nil {
+		c.logger.Error("Failed to subscribe to OBSActionRequested", "error", err5)
+		return
+	}
+	c.unsubscribeFuncs = append(c.unsubscribeFuncs, unsub5)
+
+	unsub6, err6 := eventbus.Subscribe(c.bus, "ObsClient", c.handleSourcePreviewRequested)
+	if err6 != nil {
+		c.logger.Error("Failed to subscribe to SourcePreviewRequested", "error", err6)
+		return
+	}
+	c.unsubscribeFuncs = append(c.unsubscribeFuncs, unsub6)
+
+	unsub7, err7 := eventbus.Subscribe(c.bus, "ObsClient", c.handleSourcePreviewStopRequested)
+	if err7 != nil {
+		c.logger.Error("Failed to subscribe to SourcePreviewStopRequested", "error", err7)
+		return
+	}
+	c.unsubscribeFuncs = append(c.unsubscribeFuncs, unsub7)
 }
 
 // unsubscribeAllEvents cleans up all event bus subscriptions.
@@ -64,8 +99,48 @@ func (c *OBSClient) handleTargetProgramState(event eventbus.TargetProgramState)
 		return
 	}
 
+	// Remembered for the thumbnail of the next program; see thumbnails.go
+	c.stateMu.Lock()
+	c.nextProgram = event.NextProgram
+	c.stateMu.Unlock()
+
+	// Upcoming programs are checked ahead of time; see preflight.go
+	c.schedulePreflight(event.NextProgram)
+	c.schedulePreflight(event.TargetProgram)
+	event.TargetProgram = c.preflightTarget(event.TargetProgram)
+
 	// Delegate the convergence logic to the dedicated method in switcher.go
 	c.convergeToState(event)
+
+	// Tracks no longer in the schedule are taken off air; see tracks.go
+	c.pruneTracks(event.Tracks)
+}
+
+// handleTrackTargetState processes the desired state of one parallel track.
+// Each track converges independently of the main track; see tracks.go.
+//
+// Topic:   scheduler.state.trackTarget
+func (c *OBSClient) handleTrackTargetState(event eventbus.TrackTargetState) {
+	if c.GetState() != StateConnected {
+		return
+	}
+	c.schedulePreflight(event.NextProgram)
+	c.schedulePreflight(event.TargetProgram)
+	event.TargetProgram = c.preflightTarget(event.TargetProgram)
+	c.convergeTrack(event)
+}
+
+// handleOBSActionRequested executes a scheduled streaming, recording or
+// replay buffer action in the background; see actions.go.
+//
+// Topic:   scheduler.action.obs
+func (c *OBSClient) handleOBSActionRequested(event eventbus.OBSActionRequested) {
+	if c.GetState() != StateConnected {
+		c.logger.Warn("Scheduled OBS action skipped: not connected", "action", event.Action, "source", event.SourceID)
+		c.recordAction(event, ErrNotConnected)
+		return
+	}
+	go c.executeAction(event)
 }
 
 // handleGetStatusRequested responds to status requests from clients.
@@ -84,3 +159,27 @@ func (c *OBSClient) handleGetStatusRequested(event eventbus.GetStatusRequested)
 		VirtualCamActive: status.VirtualCamActive,
 	})
 }
+
+// handleSourcePreviewRequested starts a source preview in OBS; see preview.go.
+//
+// Topic:   webserver.command.startSourcePreview
+func (c *OBSClient) handleSourcePreviewRequested(event eventbus.SourcePreviewRequested) {
+	c.logger.Debug("Received source preview request", "clientID", event.ClientID, "previewID", event.PreviewID)
+	c.startSourcePreview(event)
+}
+
+// handleSourcePreviewStopRequested stops a source preview in OBS.
+//
+// Topic:   webserver.command.stopSourcePreview
+func (c *OBSClient) handleSourcePreviewStopRequested(event eventbus.SourcePreviewStopRequested) {
+	c.stopSourcePreview(event.PreviewID)
+}
+
+// handleMediaDurationRequested determines the duration of a media file for
+// the editor. Discovery runs in the background; see mediaduration.go.
+//
+// Topic:   webserver.command.probeMediaDuration
+func (c *OBSClient) handleMediaDurationRequested(event eventbus.MediaDurationRequested) {
+	c.logger.Debug("Received media duration request", "clientID", event.ClientID)
+	c.probeMediaDuration(event.ClientID, event.Payload)
+}
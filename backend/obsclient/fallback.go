@@ -0,0 +1,90 @@
+// backend/obsclient/fallback.go
+//
+// This file coordinates the fallback source of the program on air. Several
+// monitors may want the program covered at once, e.g. because its input is
+// down and because the output is black; the fallback is shown for the first
+// reason and removed once no reason is left.
+//
+// Contents:
+// - Fallback Cover
+
+package obsclient
+
+import (
+	"github.com/andreykaipov/goobs"
+	"scenescheduler/backend/eventbus"
+)
+
+// Reasons the fallback of the program on air can be shown for.
+const (
+	fallbackForSourceHealth = "sourceHealth"
+	fallbackForFrameCheck   = "frameCheck"
+	fallbackForSilence      = "silence"
+)
+
+// fallbackCover is the fallback shown over the program on air, with the
+// reasons it is shown for. It is protected by switchMu, so covering and
+// uncovering never interleave with a program switch.
+type fallbackCover struct {
+	program *eventbus.Program
+	reasons map[string]bool
+}
+
+// ============================================================================
+// FALLBACK COVER
+// ============================================================================
+
+// showFallback covers a program with its fallback for the given reason and
+// reports whether the fallback is shown. Nothing is shown if the program has
+// no fallback or is no longer on air.
+func (c *OBSClient) showFallback(client *goobs.Client, program *eventbus.Program, reason string) bool {
+	c.switchMu.Lock()
+	defer c.switchMu.Unlock()
+
+	c.stateMu.RLock()
+	onAir := isProgramSame(c.activeProgram, program)
+	c.stateMu.RUnlock()
+	if !onAir || program.Fallback == nil {
+		return false
+	}
+
+	if c.cover.program != nil && !isProgramSame(c.cover.program, program) {
+		c.removeFallbackLocked(client) // Left over from a program no longer on air
+	}
+	if c.cover.program == nil {
+		if err := c.switcher.ShowFallback(client, program.Fallback); err != nil {
+			c.logger.Error("Failed to show fallback source", "program", getProgramTitle(program), "error", err)
+			return false
+		}
+		c.arrangeTrackScenes(client)
+		c.cover = fallbackCover{program: program, reasons: make(map[string]bool)}
+	}
+	c.cover.reasons[reason] = true
+	return true
+}
+
+// releaseFallback withdraws a reason for covering a program. The fallback is
+// removed once no reason is left.
+func (c *OBSClient) releaseFallback(client *goobs.Client, program *eventbus.Program, reason string) {
+	c.switchMu.Lock()
+	defer c.switchMu.Unlock()
+
+	if c.cover.program == nil || !isProgramSame(c.cover.program, program) {
+		return
+	}
+	delete(c.cover.reasons, reason)
+	if len(c.cover.reasons) == 0 {
+		c.removeFallbackLocked(client)
+	}
+}
+
+// removeFallbackLocked removes the fallback shown, whatever the reasons. The
+// cover is forgotten even if OBS cannot be reached, since the next session
+// starts from a cleared scene. Must be called with switchMu held.
+func (c *OBSClient) removeFallbackLocked(client *goobs.Client) {
+	// IMPORTANT: A fallback left in place hides the program, log failures
+	if err := c.switcher.HideFallback(client, c.cover.program.Fallback); err != nil {
+		c.logger.Warn("Failed to remove fallback source", "program", getProgramTitle(c.cover.program), "error", err)
+	}
+	c.cover = fallbackCover{}
+}
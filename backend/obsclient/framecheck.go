@@ -0,0 +1,338 @@
+// backend/obsclient/framecheck.go
+//
+// This file watches the picture on air. Low resolution screenshots of the
+// program output are taken at a fixed interval and analysed for black
+// frames, a frozen picture (no change over several screenshots) and large
+// static regions. Anomalies that last longer than the configured time are
+// reported on the bus and, if configured, covered with the program's
+// fallback. While covered, the program's own source is analysed instead of
+// the output, so its recovery can be seen.
+//
+// Contents:
+// - Frame Monitor
+// - Anomaly Handling
+// - Frame Analysis
+
+package obsclient
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/png"
+	"time"
+
+	"github.com/andreykaipov/goobs"
+	"scenescheduler/backend/eventbus"
+)
+
+const (
+	// frameWidth is the width of the screenshots, enough to judge the picture.
+	frameWidth = 160
+
+	// frameGrid divides screenshots into frameGrid x frameGrid regions whose
+	// changes are tracked separately.
+	frameGrid = 4
+
+	// blackLuma is the brightness (0-255) below which a pixel counts as black,
+	// and blackRatio the share of black pixels that makes a black frame.
+	blackLuma  = 24
+	blackRatio = 0.98
+)
+
+// frameSample is the analysis of one screenshot.
+type frameSample struct {
+	black   bool
+	regions [frameGrid * frameGrid]uint64 // Hash of each region's pixels
+}
+
+// frameCheck follows the picture of one program on air. It is owned by the
+// monitor goroutine of the current session.
+type frameCheck struct {
+	program *eventbus.Program
+	source  string        // Source the screenshots are taken of
+	samples []frameSample // Most recent last, at most FrozenSamples
+
+	kind          string // Current anomaly, kept while a reported one clears ("" = none)
+	detail        string
+	since         time.Time // When the current anomaly started
+	clearSince    time.Time // When a reported anomaly went away
+	reported      bool
+	fallbackShown bool
+}
+
+// ============================================================================
+// FRAME MONITOR
+// ============================================================================
+
+// monitorFrames analyses the program output for the length of the session.
+// It does nothing unless the frame check is enabled. Nothing is checked
+// while no program is on air, since the output is then black on purpose.
+func (c *OBSClient) monitorFrames() {
+	cfg := c.config.FrameCheck
+	if !cfg.Enabled {
+		return
+	}
+	client, sessionCtx := c.getActiveClientAndContext()
+	if client == nil {
+		return
+	}
+
+	c.logger.Debug("Frame monitor started.")
+	defer c.logger.Debug("Frame monitor stopped.")
+
+	ticker := time.NewTicker(time.Duration(cfg.IntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	var check *frameCheck
+	defer func() {
+		if check != nil {
+			c.endFrameCheck(client, check)
+		}
+	}()
+
+	for {
+		select {
+		case <-sessionCtx.Done():
+			return
+		case now := <-ticker.C:
+			c.stateMu.RLock()
+			active := c.activeProgram
+			c.stateMu.RUnlock()
+
+			if check == nil || !isProgramSame(check.program, active) {
+				if check != nil {
+					c.endFrameCheck(client, check)
+				}
+				check = nil
+				if active != nil {
+					check = &frameCheck{program: active, source: c.outputSource(active)}
+				}
+			}
+			if check != nil {
+				c.checkFrames(client, check, now)
+			}
+		}
+	}
+}
+
+// checkFrames takes one screenshot and acts on anomalies that last, or are
+// gone for, the sustain time.
+func (c *OBSClient) checkFrames(client *goobs.Client, check *frameCheck, now time.Time) {
+	cfg := c.config.FrameCheck
+	sustain := time.Duration(cfg.SustainSeconds) * time.Second
+
+	sample, err := captureFrame(client, check.source)
+	if err != nil {
+		c.logger.Debug("Could not take screenshot for the frame check", "source", check.source, "error", err)
+		return
+	}
+	check.samples = append(check.samples, sample)
+	if len(check.samples) > cfg.FrozenSamples {
+		check.samples = check.samples[1:]
+	}
+
+	kind, detail, known := detectAnomaly(check.samples, cfg.FrozenSamples, cfg.StaticRegionRatio)
+	if !known {
+		return // Too few screenshots yet to tell
+	}
+
+	if kind != "" {
+		if check.kind == "" {
+			check.since = now
+		}
+		check.kind, check.detail = kind, detail
+		check.clearSince = time.Time{}
+		if !check.reported && now.Sub(check.since) >= sustain {
+			c.reportFrameAnomaly(client, check)
+		}
+		return
+	}
+
+	if !check.reported {
+		check.kind = ""
+		return
+	}
+	if check.clearSince.IsZero() {
+		check.clearSince = now
+	}
+	if now.Sub(check.clearSince) >= sustain {
+		c.clearFrameAnomaly(client, check)
+	}
+}
+
+// endFrameCheck stops following a program that left the air or a session
+// that ended, releasing its fallback and closing an anomaly still reported.
+func (c *OBSClient) endFrameCheck(client *goobs.Client, check *frameCheck) {
+	if check.fallbackShown {
+		c.releaseFallback(client, check.program, fallbackForFrameCheck)
+		check.fallbackShown = false
+	}
+	if check.reported {
+		c.publishFrameAnomaly(check, false)
+	}
+}
+
+// outputSource returns the source whose picture is on air for a program.
+func (c *OBSClient) outputSource(program *eventbus.Program) string {
+	if program.SceneName != "" && program.SceneMode == eventbus.SceneModeProgram {
+		return program.SceneName
+	}
+	return c.config.ScheduleScene
+}
+
+// programSource returns the program's own source, as seen under a fallback.
+func (c *OBSClient) programSource(program *eventbus.Program) string {
+	if program.SceneName != "" {
+		return program.SceneName
+	}
+	return c.config.SourceNamePrefix + program.SourceName
+}
+
+// ============================================================================
+// ANOMALY HANDLING
+// ============================================================================
+
+// reportFrameAnomaly reports an anomaly that lasted the sustain time and,
+// if configured, covers the program with its fallback.
+func (c *OBSClient) reportFrameAnomaly(client *goobs.Client, check *frameCheck) {
+	check.reported = true
+	if c.config.FrameCheck.TriggerFallback {
+		check.fallbackShown = c.showFallback(client, check.program, fallbackForFrameCheck)
+		if check.fallbackShown {
+			// The output now shows the fallback; watch the program itself
+			check.source = c.programSource(check.program)
+			check.samples = nil
+		}
+	}
+
+	c.logger.WarnGui("Picture anomaly on air",
+		"program", getProgramTitle(check.program),
+		"anomaly", check.kind,
+		"detail", check.detail,
+		"fallback", check.fallbackShown)
+	c.publishFrameAnomaly(check, true)
+}
+
+// clearFrameAnomaly uncovers the program and reports that the picture is
+// back to normal.
+func (c *OBSClient) clearFrameAnomaly(client *goobs.Client, check *frameCheck) {
+	if check.fallbackShown {
+		c.releaseFallback(client, check.program, fallbackForFrameCheck)
+		check.fallbackShown = false
+		check.source = c.outputSource(check.program)
+		check.samples = nil
+	}
+	check.reported = false
+	check.clearSince = time.Time{}
+
+	c.logger.InfoGui("Picture on air is back to normal", "program", getProgramTitle(check.program))
+	c.publishFrameAnomaly(check, false)
+	check.kind = ""
+}
+
+// publishFrameAnomaly publishes an anomaly, or its end, for web clients.
+func (c *OBSClient) publishFrameAnomaly(check *frameCheck, active bool) {
+	eventbus.Publish(c.bus, eventbus.OBSFrameAnomaly{
+		ProgramID:      check.program.ID,
+		Title:          check.program.Title,
+		Kind:           check.kind,
+		Active:         active,
+		Detail:         check.detail,
+		Since:          check.since,
+		FallbackActive: check.fallbackShown,
+		Timestamp:      time.Now(),
+	})
+}
+
+// ============================================================================
+// FRAME ANALYSIS
+// ============================================================================
+
+// captureFrame takes a low resolution screenshot of a source and analyses
+// it. PNG is used so an unchanged picture yields identical pixels.
+func captureFrame(client *goobs.Client, sourceName string) (frameSample, error) {
+	data, err := screenshot(client, sourceName, "png", frameWidth)
+	if err != nil {
+		return frameSample{}, err
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return frameSample{}, fmt.Errorf("invalid screenshot image: %w", err)
+	}
+	return analyzeFrame(img), nil
+}
+
+// analyzeFrame measures the share of black pixels and hashes each region.
+func analyzeFrame(img image.Image) frameSample {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	var sample frameSample
+	if width == 0 || height == 0 {
+		sample.black = true
+		return sample
+	}
+
+	hashes := make([]uint64, len(sample.regions))
+	regionPixels := make([][]byte, len(sample.regions))
+	blackPixels := 0
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			luma := (299*r + 587*g + 114*b) / 1000 >> 8
+			if luma < blackLuma {
+				blackPixels++
+			}
+			region := (y*frameGrid/height)*frameGrid + x*frameGrid/width
+			regionPixels[region] = append(regionPixels[region], byte(r>>8), byte(g>>8), byte(b>>8))
+		}
+	}
+	for i, pixels := range regionPixels {
+		h := fnv.New64a()
+		h.Write(pixels)
+		hashes[i] = h.Sum64()
+	}
+	copy(sample.regions[:], hashes)
+	sample.black = float64(blackPixels) >= blackRatio*float64(width*height)
+	return sample
+}
+
+// detectAnomaly judges the most recent screenshots. A black frame is known
+// at once; a frozen picture or static region needs frozenSamples
+// screenshots, and known is false until there are that many.
+func detectAnomaly(samples []frameSample, frozenSamples int, staticRatio float64) (kind, detail string, known bool) {
+	if len(samples) == 0 {
+		return "", "", false
+	}
+	if samples[len(samples)-1].black {
+		return eventbus.FrameAnomalyBlack, "picture is black", true
+	}
+	if len(samples) < frozenSamples {
+		return "", "", false
+	}
+
+	static := 0
+	for region := range samples[0].regions {
+		unchanged := true
+		for _, s := range samples[1:] {
+			if s.regions[region] != samples[0].regions[region] {
+				unchanged = false
+				break
+			}
+		}
+		if unchanged {
+			static++
+		}
+	}
+
+	regions := len(samples[0].regions)
+	switch {
+	case static == regions:
+		return eventbus.FrameAnomalyFrozen, fmt.Sprintf("picture unchanged over %d screenshots", len(samples)), true
+	case staticRatio > 0 && float64(static) >= staticRatio*float64(regions):
+		return eventbus.FrameAnomalyStatic, fmt.Sprintf("%d of %d regions unchanged over %d screenshots", static, regions, len(samples)), true
+	}
+	return "", "", true
+}
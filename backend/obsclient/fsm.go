@@ -62,6 +62,10 @@ func (c *OBSClient) handleConnectedState() {
 
 	go c.monitorConnection()
 	go c.startOBSEventListener()
+	go c.monitorSourceHealth()
+	go c.monitorFrames()
+	go c.monitorSilence()
+	go c.monitorThumbnails()
 
 	// Block here until the session context is cancelled (e.g., by disconnection).
 	<-sessionCtx.Done()
@@ -0,0 +1,315 @@
+// backend/obsclient/health.go
+//
+// This file monitors the health of the live source on air. Live media inputs
+// (stream URLs such as RTMP or SRT) are checked through their OBS media
+// state, and browser sources by reaching their page. A source that stays
+// down longer than the configured stall time is covered with the program's
+// fallback source, or the default source, and uncovered once it has been
+// back up for the configured recovery time. Both are reported on the bus.
+//
+// Only the main track is monitored. Local media files are not: media that
+// ends is handled by the program's end condition.
+//
+// Contents:
+// - Health Monitor
+// - Outage Handling
+// - Source Probing
+
+package obsclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/andreykaipov/goobs"
+	"github.com/andreykaipov/goobs/api/requests/inputs"
+	"github.com/andreykaipov/goobs/api/requests/mediainputs"
+	"scenescheduler/backend/eventbus"
+)
+
+const (
+	// healthCheckInterval is how often the source on air is checked.
+	healthCheckInterval = time.Second
+
+	// pageProbeInterval is how often the page of a browser source is reached.
+	pageProbeInterval = 5 * time.Second
+
+	// mediaRestartInterval is how often a live media input that is down is
+	// restarted, so it can recover on its own once the stream is back.
+	mediaRestartInterval = 10 * time.Second
+)
+
+// OBS media states in which a live input is considered up.
+const (
+	mediaStatePlaying = "OBS_MEDIA_STATE_PLAYING"
+	mediaStatePaused  = "OBS_MEDIA_STATE_PAUSED"
+)
+
+// pageProbeClient reaches the pages of browser sources.
+var pageProbeClient = &http.Client{Timeout: 3 * time.Second}
+
+// sourceHealth is the health of the program on air. It is owned by the
+// monitor goroutine of the current session.
+type sourceHealth struct {
+	program   *eventbus.Program
+	inputName string
+	live      bool // Live media input; otherwise a browser source
+
+	downSince     time.Time // When the source went down (zero = up)
+	upSince       time.Time // When the source came back after an outage (zero = down or no outage)
+	reason        string
+	reported      bool // The outage has been reported on the bus
+	fallbackShown bool
+
+	lastProbe   time.Time
+	probeErr    error
+	lastRestart time.Time
+}
+
+// ============================================================================
+// HEALTH MONITOR
+// ============================================================================
+
+// monitorSourceHealth checks the source on air every second for the length
+// of the session. It does nothing unless health monitoring is enabled.
+func (c *OBSClient) monitorSourceHealth() {
+	if !c.config.Health.Enabled {
+		return
+	}
+	client, sessionCtx := c.getActiveClientAndContext()
+	if client == nil {
+		return
+	}
+
+	c.logger.Debug("Source health monitor started.")
+	defer c.logger.Debug("Source health monitor stopped.")
+
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	var health *sourceHealth
+	defer func() {
+		if health != nil {
+			c.endSourceHealth(client, health)
+		}
+	}()
+
+	for {
+		select {
+		case <-sessionCtx.Done():
+			return
+		case now := <-ticker.C:
+			c.stateMu.RLock()
+			active := c.activeProgram
+			c.stateMu.RUnlock()
+
+			if health == nil || !isProgramSame(health.program, active) {
+				if health != nil {
+					c.endSourceHealth(client, health)
+				}
+				health = c.newSourceHealth(active)
+			}
+			if health != nil {
+				c.checkSourceHealth(client, health, now)
+			}
+		}
+	}
+}
+
+// newSourceHealth starts following a program, or returns nil if its source
+// is not monitored.
+func (c *OBSClient) newSourceHealth(program *eventbus.Program) *sourceHealth {
+	if program == nil || program.SceneName != "" || !isRemoteURI(program.URI) {
+		return nil
+	}
+	h := &sourceHealth{
+		program:   program,
+		inputName: c.config.SourceNamePrefix + program.SourceName,
+	}
+	switch program.InputKind {
+	case "ffmpeg_source", "vlc_source":
+		h.live = true
+	case "browser_source":
+		if !strings.HasPrefix(program.URI, "http") {
+			return nil
+		}
+	default:
+		return nil
+	}
+	return h
+}
+
+// checkSourceHealth probes the source once and acts on outages that last
+// longer than the stall time and recoveries that last the recovery time.
+func (c *OBSClient) checkSourceHealth(client *goobs.Client, h *sourceHealth, now time.Time) {
+	stallTime := time.Duration(c.config.Health.StallSeconds) * time.Second
+	recoveryTime := time.Duration(c.config.Health.RecoverySeconds) * time.Second
+
+	if reason, up := c.probeSource(client, h, now); !up {
+		if h.downSince.IsZero() {
+			c.logger.Debug("Source on air is down", "input", h.inputName, "reason", reason)
+			h.downSince = now
+		}
+		h.upSince = time.Time{}
+		h.reason = reason
+		if !h.reported && now.Sub(h.downSince) >= stallTime {
+			c.reportOutage(client, h)
+		}
+		if h.reported {
+			c.restartLiveMedia(client, h, now)
+		}
+		return
+	}
+
+	h.downSince = time.Time{}
+	if !h.reported {
+		return
+	}
+	if h.upSince.IsZero() {
+		c.logger.Debug("Source on air is back up", "input", h.inputName)
+		h.upSince = now
+		if !h.live {
+			// The page may still show an error, reload it while covered
+			c.refreshBrowserSource(client, h)
+		}
+	}
+	if now.Sub(h.upSince) >= recoveryTime {
+		c.reportRecovery(client, h)
+	}
+}
+
+// endSourceHealth stops following a program that left the air or a session
+// that ended. Its fallback is released, and an outage still reported is
+// closed for web clients.
+func (c *OBSClient) endSourceHealth(client *goobs.Client, h *sourceHealth) {
+	if h.fallbackShown {
+		c.releaseFallback(client, h.program, fallbackForSourceHealth)
+	}
+	if h.reported {
+		c.publishSourceHealth(h, eventbus.SourceHealthy, "no longer monitored", false)
+	}
+}
+
+// ============================================================================
+// OUTAGE HANDLING
+// ============================================================================
+
+// reportOutage covers the program with its fallback, if it has one, and
+// reports the outage.
+func (c *OBSClient) reportOutage(client *goobs.Client, h *sourceHealth) {
+	h.reported = true
+	h.fallbackShown = c.showFallback(client, h.program, fallbackForSourceHealth)
+
+	c.logger.WarnGui("Source on air is down",
+		"program", getProgramTitle(h.program),
+		"input", h.inputName,
+		"reason", h.reason,
+		"fallback", h.fallbackShown)
+	c.publishSourceHealth(h, eventbus.SourceUnhealthy, h.reason, h.fallbackShown)
+}
+
+// reportRecovery uncovers the program and reports that its source is back.
+func (c *OBSClient) reportRecovery(client *goobs.Client, h *sourceHealth) {
+	if h.fallbackShown {
+		c.releaseFallback(client, h.program, fallbackForSourceHealth)
+		h.fallbackShown = false
+	}
+	h.reported = false
+	h.upSince = time.Time{}
+
+	c.logger.InfoGui("Source on air has recovered", "program", getProgramTitle(h.program), "input", h.inputName)
+	c.publishSourceHealth(h, eventbus.SourceHealthy, "", false)
+}
+
+// publishSourceHealth publishes the health of the source on air.
+func (c *OBSClient) publishSourceHealth(h *sourceHealth, status, reason string, fallbackActive bool) {
+	eventbus.Publish(c.bus, eventbus.OBSSourceHealthChanged{
+		ProgramID:      h.program.ID,
+		Title:          h.program.Title,
+		InputName:      h.inputName,
+		Status:         status,
+		Reason:         reason,
+		FallbackActive: fallbackActive,
+		Timestamp:      time.Now(),
+	})
+}
+
+// ============================================================================
+// SOURCE PROBING
+// ============================================================================
+
+// probeSource reports whether the source is up, and why not otherwise.
+func (c *OBSClient) probeSource(client *goobs.Client, h *sourceHealth, now time.Time) (string, bool) {
+	if !h.live {
+		if now.Sub(h.lastProbe) >= pageProbeInterval {
+			h.lastProbe = now
+			h.probeErr = probePage(h.program.URI)
+		}
+		if h.probeErr != nil {
+			return h.probeErr.Error(), false
+		}
+		return "", true
+	}
+
+	resp, err := client.MediaInputs.GetMediaInputStatus(&mediainputs.GetMediaInputStatusParams{
+		InputName: &h.inputName,
+	})
+	if err != nil {
+		return fmt.Sprintf("input unavailable: %v", err), false
+	}
+	switch resp.MediaState {
+	case mediaStatePlaying, mediaStatePaused:
+		return "", true
+	}
+	return "media " + strings.ToLower(strings.TrimPrefix(resp.MediaState, "OBS_MEDIA_STATE_")), false
+}
+
+// restartLiveMedia periodically restarts a live media input that is down, as
+// OBS does not always reopen a stream that ended or failed.
+func (c *OBSClient) restartLiveMedia(client *goobs.Client, h *sourceHealth, now time.Time) {
+	if !h.live || now.Sub(h.lastRestart) < mediaRestartInterval {
+		return
+	}
+	h.lastRestart = now
+	action := "OBS_WEBSOCKET_MEDIA_INPUT_ACTION_RESTART"
+	if _, err := client.MediaInputs.TriggerMediaInputAction(&mediainputs.TriggerMediaInputActionParams{
+		InputName:   &h.inputName,
+		MediaAction: &action,
+	}); err != nil {
+		c.logger.Debug("Could not restart live media input", "input", h.inputName, "error", err)
+	}
+}
+
+// refreshBrowserSource reloads the page of a browser source.
+func (c *OBSClient) refreshBrowserSource(client *goobs.Client, h *sourceHealth) {
+	button := "refreshnocache"
+	if _, err := client.Inputs.PressInputPropertiesButton(&inputs.PressInputPropertiesButtonParams{
+		InputName:    &h.inputName,
+		PropertyName: &button,
+	}); err != nil {
+		c.logger.Debug("Could not refresh browser source", "input", h.inputName, "error", err)
+	}
+}
+
+// probePage reaches the page of a browser source. Error responses count as
+// down, since the source would show the error page.
+func probePage(uri string) error {
+	resp, err := pageProbeClient.Get(uri)
+	if err != nil {
+		return fmt.Errorf("page unreachable: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("page returned %s", resp.Status)
+	}
+	return nil
+}
+
+// isRemoteURI reports whether a source URI is a URL rather than a local file.
+func isRemoteURI(uri string) bool {
+	parsed, err := url.Parse(uri)
+	return err == nil && parsed.Scheme != "" && parsed.Host != ""
+}
@@ -0,0 +1,186 @@
+// backend/obsclient/internal/mediaprobe/matroska.go
+package mediaprobe
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// ============================================================================
+// MATROSKA / WEBM
+// ============================================================================
+
+// EBML element IDs used to locate the duration. IDs keep their length marker.
+const (
+	ebmlIDHeader        = 0x1A45DFA3
+	ebmlIDSegment       = 0x18538067
+	ebmlIDInfo          = 0x1549A966
+	ebmlIDTimecodeScale = 0x2AD7B1
+	ebmlIDDuration      = 0x4489
+	ebmlIDCluster       = 0x1F43B675
+
+	defaultTimecodeScale = 1000000 // Nanoseconds per timecode tick
+	unknownSize          = -1
+)
+
+// matroskaDuration reads Segment > Info > Duration, scaled by TimecodeScale.
+// The Info element precedes the first Cluster in every muxer in common use,
+// so the search stops at the first Cluster.
+func matroskaDuration(r io.ReadSeeker) (time.Duration, error) {
+	// Skip the EBML header.
+	id, size, err := readElementHeader(r)
+	if err != nil || id != ebmlIDHeader || size == unknownSize {
+		return 0, fmt.Errorf("mediaprobe: invalid EBML header")
+	}
+	if _, err := r.Seek(size, io.SeekCurrent); err != nil {
+		return 0, fmt.Errorf("mediaprobe: %w", err)
+	}
+
+	id, _, err = readElementHeader(r)
+	if err != nil || id != ebmlIDSegment {
+		return 0, fmt.Errorf("mediaprobe: Segment element not found")
+	}
+
+	// Walk the children of the Segment until Info is found.
+	for {
+		id, size, err := readElementHeader(r)
+		if err != nil {
+			return 0, fmt.Errorf("mediaprobe: Info element not found: %w", err)
+		}
+		if id == ebmlIDCluster || size == unknownSize {
+			return 0, fmt.Errorf("mediaprobe: Info element not found before media data")
+		}
+		if id == ebmlIDInfo {
+			return readInfoDuration(r, size)
+		}
+		if _, err := r.Seek(size, io.SeekCurrent); err != nil {
+			return 0, fmt.Errorf("mediaprobe: %w", err)
+		}
+	}
+}
+
+// readInfoDuration reads the children of an Info element of the given size.
+func readInfoDuration(r io.ReadSeeker, infoSize int64) (time.Duration, error) {
+	if infoSize > 1<<20 {
+		return 0, fmt.Errorf("mediaprobe: Info element too large (%d bytes)", infoSize)
+	}
+	info, err := readFull(r, infoSize)
+	if err != nil {
+		return 0, fmt.Errorf("mediaprobe: could not read Info: %w", err)
+	}
+
+	timecodeScale := uint64(defaultTimecodeScale)
+	duration := -1.0
+
+	for pos := 0; pos < len(info); {
+		id, idLen, ok := parseVint(info[pos:], true)
+		if !ok {
+			break
+		}
+		size, sizeLen, ok := parseVint(info[pos+idLen:], false)
+		if !ok || size > uint64(len(info)) {
+			break
+		}
+		start := pos + idLen + sizeLen
+		end := start + int(size)
+		if end > len(info) {
+			break
+		}
+		payload := info[start:end]
+
+		switch id {
+		case ebmlIDTimecodeScale:
+			if v := readUint(payload); v > 0 {
+				timecodeScale = v
+			}
+		case ebmlIDDuration:
+			switch len(payload) {
+			case 4:
+				duration = float64(math.Float32frombits(be.Uint32(payload)))
+			case 8:
+				duration = math.Float64frombits(be.Uint64(payload))
+			}
+		}
+		pos = end
+	}
+
+	if duration <= 0 {
+		return 0, ErrNoDuration
+	}
+	return time.Duration(duration * float64(timecodeScale)), nil
+}
+
+// readElementHeader reads an element ID and its data size from r. The size
+// is unknownSize when all of its value bits are set.
+func readElementHeader(r io.Reader) (uint64, int64, error) {
+	id, err := readVint(r, true)
+	if err != nil {
+		return 0, 0, err
+	}
+	size, err := readVint(r, false)
+	if err != nil {
+		return 0, 0, err
+	}
+	return id, int64(size), nil
+}
+
+// readVint reads a variable-length integer from a stream.
+func readVint(r io.Reader, keepMarker bool) (uint64, error) {
+	first, err := readFull(r, 1)
+	if err != nil {
+		return 0, err
+	}
+	length := vintLength(first[0])
+	if length == 0 {
+		return 0, fmt.Errorf("mediaprobe: invalid EBML variable-length integer")
+	}
+	buf := first
+	if length > 1 {
+		rest, err := readFull(r, int64(length-1))
+		if err != nil {
+			return 0, err
+		}
+		buf = append(buf, rest...)
+	}
+	value, _, _ := parseVint(buf, keepMarker)
+	return value, nil
+}
+
+// parseVint decodes a variable-length integer from the start of b. Element IDs
+// keep their length marker; sizes have it removed and report an all-ones
+// value as unknownSize.
+func parseVint(b []byte, keepMarker bool) (uint64, int, bool) {
+	if len(b) == 0 {
+		return 0, 0, false
+	}
+	length := vintLength(b[0])
+	if length == 0 || len(b) < length {
+		return 0, 0, false
+	}
+
+	value := readUint(b[:length])
+	if keepMarker {
+		return value, length, true
+	}
+
+	valueBits := uint(7 * length)
+	mask := uint64(1)<<valueBits - 1
+	value &= mask
+	if value == mask {
+		return math.MaxUint64, length, true // Reported as unknownSize by the callers
+	}
+	return value, length, true
+}
+
+// vintLength returns the total length encoded by the leading zero bits of
+// the first byte, or 0 if the byte is invalid.
+func vintLength(first byte) int {
+	for i := 0; i < 8; i++ {
+		if first&(0x80>>i) != 0 {
+			return i + 1
+		}
+	}
+	return 0
+}
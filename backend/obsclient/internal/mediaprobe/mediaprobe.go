@@ -0,0 +1,86 @@
+// backend/obsclient/internal/mediaprobe/mediaprobe.go
+package mediaprobe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// This package reads the duration of a media file directly from its container
+// headers, without decoding any media. It is the fallback used when OBS cannot
+// report the duration of a staged input.
+//
+// Supported containers:
+//   - MP4 / MOV (ISO base media): duration and timescale of the 'mvhd' box
+//   - Matroska / WebM (EBML): Segment > Info > Duration scaled by TimecodeScale
+//
+// Order of sections:
+// 1) Public API
+// 2) Helpers
+
+// ============================================================================
+// PUBLIC API
+// ============================================================================
+
+// ErrUnsupportedContainer is returned when the file is neither MP4 nor Matroska.
+var ErrUnsupportedContainer = errors.New("mediaprobe: unsupported container format")
+
+// ErrNoDuration is returned when the container does not declare a duration,
+// for example a live recording that was never finalized.
+var ErrNoDuration = errors.New("mediaprobe: container does not declare a duration")
+
+// Duration returns the duration declared in the container of the file at path.
+func Duration(path string) (time.Duration, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("mediaprobe: %w", err)
+	}
+	defer f.Close()
+
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(f, header); err != nil {
+		return 0, fmt.Errorf("mediaprobe: could not read file header: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("mediaprobe: %w", err)
+	}
+
+	switch {
+	case bytes.Equal(header[4:8], []byte("ftyp")):
+		return mp4Duration(f)
+	case bytes.Equal(header[0:4], []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		return matroskaDuration(f)
+	default:
+		return 0, ErrUnsupportedContainer
+	}
+}
+
+// ============================================================================
+// HELPERS
+// ============================================================================
+
+// readUint reads a big-endian unsigned integer of 1 to 8 bytes.
+func readUint(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+// readFull reads exactly n bytes from r.
+func readFull(r io.Reader, n int64) ([]byte, error) {
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// be is a shorthand for the big-endian byte order used by both formats.
+var be = binary.BigEndian
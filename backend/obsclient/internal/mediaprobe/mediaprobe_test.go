@@ -0,0 +1,295 @@
+package mediaprobe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ============================================================================
+// FIXTURE BUILDERS
+// ============================================================================
+
+// box returns an MP4 box with a 32-bit size.
+func box(boxType string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	out := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	return append(append(out, boxType...), body...)
+}
+
+// largeBox returns an MP4 box with a 64-bit size.
+func largeBox(boxType string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	out := binary.BigEndian.AppendUint32(nil, 1)
+	out = append(out, boxType...)
+	out = binary.BigEndian.AppendUint64(out, uint64(16+len(body)))
+	return append(out, body...)
+}
+
+// mvhd returns the payload of a movie header box of the given version.
+func mvhd(version byte, timescale uint32, duration uint64) []byte {
+	out := []byte{version, 0, 0, 0}
+	if version == 1 {
+		out = append(out, make([]byte, 16)...) // Creation and modification times
+		out = binary.BigEndian.AppendUint32(out, timescale)
+		out = binary.BigEndian.AppendUint64(out, duration)
+	} else {
+		out = append(out, make([]byte, 8)...)
+		out = binary.BigEndian.AppendUint32(out, timescale)
+		out = binary.BigEndian.AppendUint32(out, uint32(duration))
+	}
+	return append(out, make([]byte, 80)...) // Rate, volume, matrix, ...
+}
+
+// element returns an EBML element. IDs are written with their length marker;
+// sizes use the shortest encoding up to 126 and eight bytes above.
+func element(id uint64, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	out := bytes.TrimLeft(binary.BigEndian.AppendUint64(nil, id), "\x00")
+	if len(body) < 0x7F {
+		out = append(out, 0x80|byte(len(body)))
+	} else {
+		out = binary.BigEndian.AppendUint64(out, 1<<56|uint64(len(body)))
+	}
+	return append(out, body...)
+}
+
+// unknownSizeElement returns the header of an element of unknown size.
+func unknownSizeElement(id uint64) []byte {
+	out := bytes.TrimLeft(binary.BigEndian.AppendUint64(nil, id), "\x00")
+	return append(out, 0x01, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF)
+}
+
+func float64Bytes(v float64) []byte { return binary.BigEndian.AppendUint64(nil, math.Float64bits(v)) }
+func float32Bytes(v float32) []byte { return binary.BigEndian.AppendUint32(nil, math.Float32bits(v)) }
+
+var ebmlHeader = element(ebmlIDHeader, element(0x4282, []byte("webm")))
+
+// ============================================================================
+// TESTS
+// ============================================================================
+
+func TestMP4Duration(t *testing.T) {
+	ftyp := box("ftyp", []byte("isom\x00\x00\x02\x00"))
+
+	tests := []struct {
+		name    string
+		file    []byte
+		want    time.Duration
+		wantErr string // Substring of the error, if any
+	}{
+		{
+			name: "version 0 after media data",
+			file: bytes.Join([][]byte{
+				ftyp,
+				box("free"),
+				box("mdat", make([]byte, 4096)),
+				box("moov", box("trak"), box("mvhd", mvhd(0, 1000, 90500))),
+			}, nil),
+			want: 90500 * time.Millisecond,
+		},
+		{
+			name: "version 1 with 64-bit box sizes",
+			file: bytes.Join([][]byte{
+				ftyp,
+				largeBox("moov", largeBox("mvhd", mvhd(1, 600, 600*3600))),
+			}, nil),
+			want: time.Hour,
+		},
+		{
+			name: "last box extends to the end of the file",
+			file: bytes.Join([][]byte{
+				ftyp,
+				box("moov", box("mvhd", mvhd(0, 90000, 90000*2))),
+				{0, 0, 0, 0}, []byte("mdat"), make([]byte, 64),
+			}, nil),
+			want: 2 * time.Second,
+		},
+		{
+			name:    "unset duration",
+			file:    append(ftyp, box("moov", box("mvhd", mvhd(0, 1000, 0xFFFFFFFF)))...),
+			wantErr: ErrNoDuration.Error(),
+		},
+		{
+			name:    "zero timescale",
+			file:    append(ftyp, box("moov", box("mvhd", mvhd(0, 0, 1000)))...),
+			wantErr: ErrNoDuration.Error(),
+		},
+		{
+			name:    "unsupported version",
+			file:    append(ftyp, box("moov", box("mvhd", mvhd(2, 1000, 1000)))...),
+			wantErr: "unsupported mvhd version 2",
+		},
+		{
+			name:    "truncated movie header",
+			file:    append(ftyp, box("moov", box("mvhd", make([]byte, 12)))...),
+			wantErr: "mvhd box too short",
+		},
+		{
+			name:    "no movie box",
+			file:    append(ftyp, box("mdat", make([]byte, 16))...),
+			wantErr: `box "moov" not found`,
+		},
+		{
+			name:    "box larger than the file",
+			file:    append(ftyp, 0, 0, 0x10, 0, 'm', 'o', 'o', 'v'),
+			wantErr: `corrupt box "moov"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mp4Duration(bytes.NewReader(tt.file))
+			checkDuration(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestMatroskaDuration(t *testing.T) {
+	seekHead := element(0x114D9B74, make([]byte, 200))
+
+	tests := []struct {
+		name    string
+		file    []byte
+		want    time.Duration
+		wantErr string // Substring of the error, if any
+	}{
+		{
+			name: "default timecode scale",
+			file: bytes.Join([][]byte{
+				ebmlHeader,
+				element(ebmlIDSegment, seekHead, element(ebmlIDInfo, element(ebmlIDDuration, float64Bytes(5000)))),
+			}, nil),
+			want: 5 * time.Second,
+		},
+		{
+			name: "custom timecode scale and 32-bit float",
+			file: bytes.Join([][]byte{
+				ebmlHeader,
+				element(ebmlIDSegment, element(ebmlIDInfo,
+					element(ebmlIDDuration, float32Bytes(2500000)),
+					element(ebmlIDTimecodeScale, []byte{0x03, 0xE8}),
+				)),
+			}, nil),
+			want: 2500 * time.Millisecond,
+		},
+		{
+			name: "segment of unknown size",
+			file: bytes.Join([][]byte{
+				ebmlHeader,
+				unknownSizeElement(ebmlIDSegment),
+				seekHead,
+				element(ebmlIDInfo, element(ebmlIDDuration, float64Bytes(1500))),
+			}, nil),
+			want: 1500 * time.Millisecond,
+		},
+		{
+			name: "info without a duration",
+			file: bytes.Join([][]byte{
+				ebmlHeader,
+				element(ebmlIDSegment, element(ebmlIDInfo, element(ebmlIDTimecodeScale, []byte{0x0F, 0x42, 0x40}))),
+			}, nil),
+			wantErr: ErrNoDuration.Error(),
+		},
+		{
+			name: "cluster before info",
+			file: bytes.Join([][]byte{
+				ebmlHeader,
+				element(ebmlIDSegment, element(ebmlIDCluster, make([]byte, 32))),
+			}, nil),
+			wantErr: "not found before media data",
+		},
+		{
+			name:    "truncated segment",
+			file:    append(ebmlHeader, element(ebmlIDSegment, seekHead)...),
+			wantErr: "Info element not found",
+		},
+		{
+			name:    "missing segment",
+			file:    append(ebmlHeader, element(ebmlIDInfo)...),
+			wantErr: "Segment element not found",
+		},
+		{
+			name:    "not an EBML file",
+			file:    element(ebmlIDSegment),
+			wantErr: "invalid EBML header",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := matroskaDuration(bytes.NewReader(tt.file))
+			checkDuration(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    []byte
+		want    time.Duration
+		wantErr string // Substring of the error, if any
+	}{
+		{
+			name: "mp4",
+			file: append(box("ftyp", []byte("isom")), box("moov", box("mvhd", mvhd(0, 1000, 3000)))...),
+			want: 3 * time.Second,
+		},
+		{
+			name: "matroska",
+			file: append(ebmlHeader, element(ebmlIDSegment, element(ebmlIDInfo, element(ebmlIDDuration, float64Bytes(4000))))...),
+			want: 4 * time.Second,
+		},
+		{
+			name:    "unsupported container",
+			file:    []byte("RIFF\x00\x00\x00\x00AVI LIST"),
+			wantErr: ErrUnsupportedContainer.Error(),
+		},
+		{
+			name:    "shorter than a header",
+			file:    []byte("ftyp"),
+			wantErr: "could not read file header",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "media")
+			if err := os.WriteFile(path, tt.file, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := Duration(path)
+			checkDuration(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := Duration(filepath.Join(t.TempDir(), "missing.mp4"))
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("err = %v, want a not-exist error", err)
+		}
+	})
+}
+
+func checkDuration(t *testing.T, got time.Duration, err error, want time.Duration, wantErr string) {
+	t.Helper()
+	if wantErr != "" {
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("err = %v, want it to mention %q", err, wantErr)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != want {
+		t.Errorf("duration = %v, want %v", got, want)
+	}
+}
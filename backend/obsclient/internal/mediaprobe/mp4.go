@@ -0,0 +1,104 @@
+// backend/obsclient/internal/mediaprobe/mp4.go
+package mediaprobe
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// ============================================================================
+// MP4 / ISO BASE MEDIA
+// ============================================================================
+
+// mp4Duration walks the top-level boxes until it finds 'moov', then reads the
+// movie header ('mvhd') inside it. Large boxes such as 'mdat' are skipped by
+// seeking, so only a few kilobytes of the file are ever read.
+func mp4Duration(r io.ReadSeeker) (time.Duration, error) {
+	fileSize, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, fmt.Errorf("mediaprobe: %w", err)
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("mediaprobe: %w", err)
+	}
+
+	moovStart, moovSize, err := findBox(r, 0, fileSize, "moov")
+	if err != nil {
+		return 0, err
+	}
+	mvhdStart, mvhdSize, err := findBox(r, moovStart, moovStart+moovSize, "mvhd")
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := r.Seek(mvhdStart, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("mediaprobe: %w", err)
+	}
+	payload, err := readFull(r, min(mvhdSize, 64))
+	if err != nil {
+		return 0, fmt.Errorf("mediaprobe: could not read mvhd: %w", err)
+	}
+
+	var timescale, duration uint64
+	switch version := payload[0]; version {
+	case 0:
+		if len(payload) < 20 {
+			return 0, fmt.Errorf("mediaprobe: mvhd box too short")
+		}
+		timescale = uint64(be.Uint32(payload[12:16]))
+		duration = uint64(be.Uint32(payload[16:20]))
+	case 1:
+		if len(payload) < 32 {
+			return 0, fmt.Errorf("mediaprobe: mvhd box too short")
+		}
+		timescale = uint64(be.Uint32(payload[20:24]))
+		duration = be.Uint64(payload[24:32])
+	default:
+		return 0, fmt.Errorf("mediaprobe: unsupported mvhd version %d", version)
+	}
+
+	if timescale == 0 || duration == 0 || duration == 0xFFFFFFFF || duration == 0xFFFFFFFFFFFFFFFF {
+		return 0, ErrNoDuration
+	}
+	seconds := float64(duration) / float64(timescale)
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// findBox scans the boxes in [start, end) and returns the payload offset and
+// payload size of the first box of the given type.
+func findBox(r io.ReadSeeker, start, end int64, boxType string) (int64, int64, error) {
+	offset := start
+	for offset+8 <= end {
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return 0, 0, fmt.Errorf("mediaprobe: %w", err)
+		}
+		header, err := readFull(r, 8)
+		if err != nil {
+			return 0, 0, fmt.Errorf("mediaprobe: could not read box header: %w", err)
+		}
+
+		size := int64(be.Uint32(header[0:4]))
+		headerSize := int64(8)
+		switch size {
+		case 0: // Box extends to the end of its container
+			size = end - offset
+		case 1: // 64-bit size follows the type
+			large, err := readFull(r, 8)
+			if err != nil {
+				return 0, 0, fmt.Errorf("mediaprobe: could not read box size: %w", err)
+			}
+			size = int64(be.Uint64(large))
+			headerSize = 16
+		}
+		if size < headerSize || offset+size > end {
+			return 0, 0, fmt.Errorf("mediaprobe: corrupt box %q at offset %d", header[4:8], offset)
+		}
+
+		if string(header[4:8]) == boxType {
+			return offset + headerSize, size - headerSize, nil
+		}
+		offset += size
+	}
+	return 0, 0, fmt.Errorf("mediaprobe: box %q not found", boxType)
+}
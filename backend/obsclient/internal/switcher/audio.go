@@ -0,0 +1,192 @@
+// backend/obsclient/internal/switcher/audio.go
+//
+// This file applies per-program audio settings and performs audio
+// crossfades. Settings are applied to every input of a program when it is
+// staged. A crossfade ramps the incoming program up from silence and the
+// outgoing one down to silence before the outgoing program is removed.
+//
+// Contents:
+// - Audio Settings
+// - Audio Crossfade
+// - Volume Helpers
+
+package switcher
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/andreykaipov/goobs"
+	"github.com/andreykaipov/goobs/api/requests/inputs"
+	"scenescheduler/backend/eventbus"
+)
+
+// Volume range accepted by OBS. The lowest volume is treated as silence.
+const (
+	silenceDb   = -100.0
+	maxVolumeDb = 26.0
+)
+
+// ============================================================================
+// AUDIO SETTINGS
+// ============================================================================
+
+// applyAudioSettings sets the volume, mute state, monitor type and balance of
+// a newly created input. Fields left empty keep the OBS defaults.
+func (s *Switcher) applyAudioSettings(client *goobs.Client, inputName string, audio *eventbus.Audio) error {
+	if audio == nil {
+		return nil
+	}
+
+	if audio.VolumeDb != nil {
+		volumeDb := min(max(*audio.VolumeDb, silenceDb), maxVolumeDb)
+		if _, err := client.Inputs.SetInputVolume(&inputs.SetInputVolumeParams{
+			InputName:     &inputName,
+			InputVolumeDb: &volumeDb,
+		}); err != nil {
+			return fmt.Errorf("failed to set volume: %w", err)
+		}
+	}
+	if audio.Muted {
+		if _, err := client.Inputs.SetInputMute(&inputs.SetInputMuteParams{
+			InputName:  &inputName,
+			InputMuted: &audio.Muted,
+		}); err != nil {
+			return fmt.Errorf("failed to mute input: %w", err)
+		}
+	}
+	if audio.MonitorType != "" {
+		if _, err := client.Inputs.SetInputAudioMonitorType(&inputs.SetInputAudioMonitorTypeParams{
+			InputName:   &inputName,
+			MonitorType: &audio.MonitorType,
+		}); err != nil {
+			return fmt.Errorf("failed to set monitor type: %w", err)
+		}
+	}
+	if audio.Balance != nil {
+		balance := min(max(*audio.Balance, 0), 1)
+		if _, err := client.Inputs.SetInputAudioBalance(&inputs.SetInputAudioBalanceParams{
+			InputName:         &inputName,
+			InputAudioBalance: &balance,
+		}); err != nil {
+			return fmt.Errorf("failed to set balance: %w", err)
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// AUDIO CROSSFADE
+// ============================================================================
+
+// silenceProgram mutes every input of a program ahead of a crossfade, so it
+// can be made visible without a burst of sound. Failures are logged only;
+// the program then simply starts at full volume.
+func (s *Switcher) silenceProgram(client *goobs.Client, program *eventbus.Program) {
+	for _, layer := range audioLayers(program) {
+		inputName := s.itemName(layer)
+		if err := setVolumeMul(client, inputName, 0); err != nil {
+			s.logger.Debug("Could not silence input for crossfade", "input", inputName, "error", err)
+		}
+	}
+}
+
+// crossfadeAudio ramps the outgoing program down to silence and the incoming
+// one up to its configured volume over the given duration. It stops early
+// if a newer switch starts; that switch takes over the programs involved.
+func (s *Switcher) crossfadeAudio(client *goobs.Client, gen uint64, current, target *eventbus.Program, duration time.Duration) {
+	type ramp struct {
+		inputName string
+		from, to  float64 // Volume multipliers
+	}
+
+	var ramps []ramp
+	for _, layer := range audioLayers(current) {
+		inputName := s.itemName(layer)
+		resp, err := client.Inputs.GetInputVolume(&inputs.GetInputVolumeParams{InputName: &inputName})
+		if err != nil {
+			s.logger.Debug("Could not read volume for crossfade", "input", inputName, "error", err)
+			continue
+		}
+		ramps = append(ramps, ramp{inputName: inputName, from: resp.InputVolumeMul, to: 0})
+	}
+	for _, layer := range audioLayers(target) {
+		ramps = append(ramps, ramp{inputName: s.itemName(layer), from: 0, to: targetVolumeMul(layer.Audio)})
+	}
+	if len(ramps) == 0 {
+		return
+	}
+
+	start := time.Now()
+	for s.transitionGen.Load() == gen {
+		progress := min(float64(time.Since(start))/float64(duration), 1)
+		for _, r := range ramps {
+			if err := setVolumeMul(client, r.inputName, r.from+(r.to-r.from)*progress); err != nil {
+				s.logger.Debug("Crossfade interrupted", "input", r.inputName, "error", err)
+				return
+			}
+		}
+		if progress >= 1 {
+			return
+		}
+		time.Sleep(transitionFrameInterval)
+	}
+}
+
+// ============================================================================
+// VOLUME HELPERS
+// ============================================================================
+
+// audioLayers returns the layers of a program that are inputs. Nested scenes
+// have no volume of their own.
+func audioLayers(program *eventbus.Program) []*eventbus.Program {
+	var result []*eventbus.Program
+	for _, layer := range programLayers(program) {
+		if !isSceneProgram(layer) {
+			result = append(result, layer)
+		}
+	}
+	return result
+}
+
+// targetVolumeMul is the volume multiplier an input is faded up to.
+func targetVolumeMul(audio *eventbus.Audio) float64 {
+	if audio == nil || audio.VolumeDb == nil {
+		return 1
+	}
+	return dbToMul(*audio.VolumeDb)
+}
+
+// setVolumeMul sets the volume of an input as a multiplier.
+func setVolumeMul(client *goobs.Client, inputName string, mul float64) error {
+	mul = max(mul, 0)
+	_, err := client.Inputs.SetInputVolume(&inputs.SetInputVolumeParams{
+		InputName:      &inputName,
+		InputVolumeMul: &mul,
+	})
+	return err
+}
+
+// dbToMul converts a volume in dB to a multiplier.
+func dbToMul(db float64) float64 {
+	if db <= silenceDb {
+		return 0
+	}
+	return math.Pow(10, db/20)
+}
+
+// AudioInputs returns the OBS input names of a program's inputs, mapped to
+// the volume in dB each one is configured to air at. It lets the parent
+// module follow and steer the audio of the program on air.
+func (s *Switcher) AudioInputs(program *eventbus.Program) map[string]float64 {
+	result := make(map[string]float64)
+	for _, layer := range audioLayers(program) {
+		volumeDb := 0.0
+		if layer.Audio != nil && layer.Audio.VolumeDb != nil {
+			volumeDb = min(max(*layer.Audio.VolumeDb, silenceDb), maxVolumeDb)
+		}
+		result[s.itemName(layer)] = volumeDb
+	}
+	return result
+}
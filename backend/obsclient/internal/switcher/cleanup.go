@@ -24,11 +24,22 @@ import (
 // SPECIFIC PROGRAM CLEANUP
 // ============================================================================
 
-// cleanupSpecificProgram removes a known program from the scene.
+// cleanupSpecificProgram removes a known program, with all its layers, from the scene.
 // This is used to clean up the previous program after a successful switch.
 // Returns error only if the operation fails unexpectedly (not if resource is already gone).
 func (s *Switcher) cleanupSpecificProgram(client *goobs.Client, sceneName string, program *eventbus.Program) error {
-	prefixedName := s.config.SourceNamePrefix + program.SourceName
+	for _, layer := range programLayers(program) {
+		if err := s.cleanupProgramSource(client, sceneName, layer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanupProgramSource removes the scene item and input of a single source.
+// For an existing OBS scene only the scene item is removed.
+func (s *Switcher) cleanupProgramSource(client *goobs.Client, sceneName string, program *eventbus.Program) error {
+	prefixedName := s.itemName(program)
 
 	s.logger.Debug("Cleaning up specific program", "name", prefixedName, "scene", sceneName)
 
@@ -58,6 +69,11 @@ func (s *Switcher) cleanupSpecificProgram(client *goobs.Client, sceneName string
 			"error", err)
 	}
 
+	// Operator-built scenes are never removed
+	if isSceneProgram(program) {
+		return nil
+	}
+
 	// Always try to remove the input source (idempotent)
 	// CLEANUP: Input removal is best-effort
 	if _, err := client.Inputs.RemoveInput(&inputs.RemoveInputParams{
@@ -87,11 +103,8 @@ func (s *Switcher) cleanupOrphanedManagedSources(
 
 	// Build set of protected source names
 	protectedSources := make(map[string]bool)
-	if current != nil {
-		protectedSources[prefix+current.SourceName] = true
-	}
-	if target != nil {
-		protectedSources[prefix+target.SourceName] = true
+	for _, layer := range append(programLayers(current), programLayers(target)...) {
+		protectedSources[s.itemName(layer)] = true
 	}
 
 	resp, err := client.SceneItems.GetSceneItemList(&sceneitems.GetSceneItemListParams{
@@ -134,8 +147,9 @@ func (s *Switcher) cleanupOrphanedManagedSources(
 
 // removeOBSInput handles the idempotent removal of a scene item and its underlying input source.
 // This is used for rollback scenarios and is best-effort (does not return errors).
+// For an existing OBS scene only the scene item is removed.
 func (s *Switcher) removeOBSInput(client *goobs.Client, sceneName string, program *eventbus.Program) error {
-	prefixedName := s.config.SourceNamePrefix + program.SourceName
+	prefixedName := s.itemName(program)
 
 	// CLEANUP: Best-effort removal
 	idResp, err := client.SceneItems.GetSceneItemId(&sceneitems.GetSceneItemIdParams{
@@ -149,6 +163,10 @@ func (s *Switcher) removeOBSInput(client *goobs.Client, sceneName string, progra
 		})
 	}
 
+	if isSceneProgram(program) {
+		return nil
+	}
+
 	_, _ = client.Inputs.RemoveInput(&inputs.RemoveInputParams{
 		InputName: &prefixedName,
 	})
@@ -59,6 +59,11 @@ func (s *Switcher) removeInputIfExists(client *goobs.Client, inputName string) e
 
 // createOBSInput prepares a new source and its scene item in the temporary scene.
 func (s *Switcher) createOBSInput(client *goobs.Client, program *eventbus.Program) (int, error) {
+	// Existing scenes are nested as they are, see scenes.go
+	if isSceneProgram(program) {
+		return s.stageScene(client, program)
+	}
+
 	tmpScene := s.config.ScheduleSceneAux
 	prefixedName := s.config.SourceNamePrefix + program.SourceName
 
@@ -88,6 +93,20 @@ func (s *Switcher) createOBSInput(client *goobs.Client, program *eventbus.Progra
 		return 0, fmt.Errorf("failed to create input in temp scene: %w", err)
 	}
 
+	// IMPORTANT: Audio settings failure is non-fatal, the input airs at OBS defaults
+	if err := s.applyAudioSettings(client, prefixedName, program.Audio); err != nil {
+		s.logger.Warn("Failed to apply audio settings, using defaults.",
+			"error", err,
+			"inputName", prefixedName)
+	}
+
+	// IMPORTANT: Filter failure is non-fatal, airing without a filter beats dead air
+	if err := s.applyFilters(client, prefixedName, program.Filters); err != nil {
+		s.logger.Warn("Failed to apply filters, airing without them.",
+			"error", err,
+			"inputName", prefixedName)
+	}
+
 	s.logger.Debug("Successfully created scene item in temp scene",
 		"sceneItemId", respCreate.SceneItemId,
 		"scene", tmpScene)
@@ -0,0 +1,90 @@
+// backend/obsclient/internal/switcher/fallback.go
+//
+// This file shows and hides the fallback source of a program. The fallback
+// is staged and promoted like a program, but on top of the program on air,
+// which stays in place underneath so it can be uncovered once it recovers.
+// The fallback input uses a dedicated name so it never collides with a
+// program input, even when the fallback is the default source.
+//
+// Contents:
+// - Fallback Overlay
+
+package switcher
+
+import (
+	"fmt"
+
+	"github.com/andreykaipov/goobs"
+	"github.com/andreykaipov/goobs/api/requests/sceneitems"
+	"scenescheduler/backend/eventbus"
+)
+
+// ============================================================================
+// FALLBACK OVERLAY
+// ============================================================================
+
+// ShowFallback creates the fallback source of a program and shows it on top
+// of the main scene. The next program switch removes it along with the
+// other managed sources, see cleanupOrphanedManagedSources.
+func (s *Switcher) ShowFallback(client *goobs.Client, fallback *eventbus.Program) error {
+	s.switchMu.Lock()
+	defer s.switchMu.Unlock()
+
+	mainScene := s.config.ScheduleScene
+	tmpScene := s.config.ScheduleSceneAux
+	overlay := fallbackSource(fallback)
+
+	// CRITICAL: Creation, promotion and activation failures roll back
+	tempItemID, err := s.createOBSInput(client, overlay)
+	if err != nil {
+		return fmt.Errorf("failed to create fallback input: %w", err)
+	}
+	mainItemID, err := s.duplicateSceneItem(client, tmpScene, mainScene, tempItemID)
+	if err != nil {
+		// CLEANUP: Rollback is best-effort
+		_ = s.removeOBSInput(client, tmpScene, overlay)
+		return fmt.Errorf("failed to duplicate fallback to main scene: %w", err)
+	}
+
+	// IMPORTANT: Transform failure is non-fatal, log and continue
+	if err := s.applyTransformsToSceneItem(client, mainScene, mainItemID, overlay.Transform); err != nil {
+		s.logger.Warn("Failed to apply transform to fallback, using defaults.", "error", err)
+	}
+	if err := s.setSceneItemEnabled(client, mainScene, mainItemID, true); err != nil {
+		// CLEANUP: Rollback is best-effort
+		_ = s.removeOBSInput(client, tmpScene, overlay)
+		_ = s.removeOBSInput(client, mainScene, overlay)
+		return fmt.Errorf("failed to make fallback visible: %w", err)
+	}
+
+	// CLEANUP: Temp item removal is best-effort
+	_, _ = client.SceneItems.RemoveSceneItem(&sceneitems.RemoveSceneItemParams{
+		SceneName:   &tmpScene,
+		SceneItemId: &tempItemID,
+	})
+
+	s.logger.InfoGui("Fallback source shown", "fallback", getTargetTitle(fallback))
+	return nil
+}
+
+// HideFallback removes the fallback source of a program from the main scene,
+// uncovering the program. Removal is idempotent.
+func (s *Switcher) HideFallback(client *goobs.Client, fallback *eventbus.Program) error {
+	s.switchMu.Lock()
+	defer s.switchMu.Unlock()
+
+	if err := s.cleanupProgramSource(client, s.config.ScheduleScene, fallbackSource(fallback)); err != nil {
+		return fmt.Errorf("failed to remove fallback: %w", err)
+	}
+	s.logger.InfoGui("Fallback source removed", "fallback", getTargetTitle(fallback))
+	return nil
+}
+
+// fallbackSource renames the fallback's input so it is distinct from the
+// inputs of programs. Existing scenes keep their name.
+func fallbackSource(fallback *eventbus.Program) *eventbus.Program {
+	overlay := *fallback
+	overlay.SourceName = "fallback-" + fallback.SourceName
+	overlay.Layers = nil
+	return &overlay
+}
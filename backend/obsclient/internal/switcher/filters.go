@@ -0,0 +1,74 @@
+// backend/obsclient/internal/switcher/filters.go
+//
+// This file adds the filters of a program to its input while the input is
+// staged, so the program is promoted with its filters already in place.
+// Inputs are recreated for every program, which removes their filters too.
+//
+// Contents:
+// - Filter Creation
+
+package switcher
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/andreykaipov/goobs"
+	"github.com/andreykaipov/goobs/api/requests/filters"
+	"scenescheduler/backend/eventbus"
+)
+
+// ============================================================================
+// FILTER CREATION
+// ============================================================================
+
+// applyFilters adds the filters of a program to a newly created input, in
+// list order. Filter kinds this instance of OBS does not support are skipped,
+// as are filters OBS rejects; the input then airs without them. The returned
+// error lists the filters that could not be added.
+func (s *Switcher) applyFilters(client *goobs.Client, inputName string, programFilters []eventbus.Filter) error {
+	if len(programFilters) == 0 {
+		return nil
+	}
+
+	// Validate the filter kinds against this instance of OBS, which depends on
+	// its version and plugins.
+	listResp, err := client.Filters.GetSourceFilterKindList()
+	if err != nil {
+		return fmt.Errorf("could not fetch supported filter kinds: %w", err)
+	}
+
+	var failed []string
+	for _, filter := range programFilters {
+		if !slices.Contains(listResp.SourceFilterKinds, filter.Kind) {
+			s.logger.Error("Filter kind not supported by OBS",
+				"filter", filter.Name,
+				"requested", filter.Kind,
+				"supported", listResp.SourceFilterKinds)
+			failed = append(failed, filter.Name)
+			continue
+		}
+
+		kind, name := filter.Kind, filter.Name
+		if _, err := client.Filters.CreateSourceFilter(&filters.CreateSourceFilterParams{
+			SourceName:     &inputName,
+			FilterName:     &name,
+			FilterKind:     &kind,
+			FilterSettings: filter.Settings,
+		}); err != nil {
+			s.logger.Error("Failed to create filter",
+				"filter", filter.Name,
+				"kind", filter.Kind,
+				"inputName", inputName,
+				"error", err)
+			failed = append(failed, filter.Name)
+			continue
+		}
+		s.logger.Debug("Created filter", "filter", filter.Name, "kind", filter.Kind, "inputName", inputName)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d filters could not be added: %v", len(failed), len(programFilters), failed)
+	}
+	return nil
+}
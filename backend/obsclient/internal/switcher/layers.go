@@ -0,0 +1,114 @@
+// backend/obsclient/internal/switcher/layers.go
+//
+// This file contains helpers for composite programs. Every layer of a program
+// is handled as a program of its own, so the staging, cleanup and rollback
+// helpers work on layers unchanged.
+//
+// Contents:
+// - Layer Expansion
+// - Layer Rollback
+
+package switcher
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/andreykaipov/goobs"
+	"scenescheduler/backend/eventbus"
+)
+
+// ============================================================================
+// LAYER EXPANSION
+// ============================================================================
+
+// stagedLayer tracks the scene items created for one layer during a switch.
+type stagedLayer struct {
+	program    *eventbus.Program
+	tempItemID int // Item in the temp scene (0 = not created yet)
+	mainItemID int // Item in the main scene (0 = not created yet)
+}
+
+// programLayers returns the sources of a program ordered bottom to top. The
+// main source is the program itself; layers share its ID and title so log
+// messages still identify the program. Returns nil for a nil program.
+func programLayers(p *eventbus.Program) []*eventbus.Program {
+	if p == nil {
+		return nil
+	}
+	if isProgramSceneMode(p) {
+		// The scene replaces the whole output, layers do not apply
+		return []*eventbus.Program{p}
+	}
+
+	type ordered struct {
+		program *eventbus.Program
+		zIndex  int
+	}
+	all := []ordered{{program: p}}
+	for _, l := range p.Layers {
+		all = append(all, ordered{
+			program: &eventbus.Program{
+				ID:            p.ID,
+				Title:         fmt.Sprintf("%s [%s%s]", p.Title, l.SourceName, l.SceneName),
+				Kind:          p.Kind,
+				ParentID:      p.ParentID,
+				SourceName:    l.SourceName,
+				SceneName:     l.SceneName,
+				InputKind:     l.InputKind,
+				URI:           l.URI,
+				InputSettings: l.InputSettings,
+				Audio:         l.Audio,
+				Filters:       l.Filters,
+				Transform:     l.Transform,
+			},
+			zIndex: l.ZIndex,
+		})
+	}
+	// The main source is listed first, so it stays below layers at ZIndex 0
+	sort.SliceStable(all, func(i, j int) bool { return all[i].zIndex < all[j].zIndex })
+
+	result := make([]*eventbus.Program, len(all))
+	for i, o := range all {
+		result[i] = o.program
+	}
+	return result
+}
+
+// stagedLayersFor prepares the bookkeeping for switching to target. Source
+// names must be unique within the program, since inputs are created and
+// removed by name.
+func stagedLayersFor(target *eventbus.Program) ([]*stagedLayer, error) {
+	layers := programLayers(target)
+	staged := make([]*stagedLayer, len(layers))
+	seen := make(map[string]bool, len(layers))
+	for i, layer := range layers {
+		key := layer.SourceName
+		if isSceneProgram(layer) {
+			key = layer.SceneName
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("source %q is used by more than one layer", key)
+		}
+		seen[key] = true
+		staged[i] = &stagedLayer{program: layer}
+	}
+	return staged, nil
+}
+
+// ============================================================================
+// LAYER ROLLBACK
+// ============================================================================
+
+// rollbackLayers removes every layer staged so far from both scenes. Removal
+// is idempotent, so layers that were never created are skipped silently.
+func (s *Switcher) rollbackLayers(client *goobs.Client, layers []*stagedLayer) {
+	for _, layer := range layers {
+		if layer.tempItemID == 0 && layer.mainItemID == 0 {
+			continue
+		}
+		// CLEANUP: Rollback is best-effort
+		_ = s.removeOBSInput(client, s.config.ScheduleSceneAux, layer.program)
+		_ = s.removeOBSInput(client, s.config.ScheduleScene, layer.program)
+	}
+}
@@ -0,0 +1,82 @@
+// backend/obsclient/internal/switcher/preview.go
+//
+// This file creates and removes the inputs of source previews. They live in
+// a dedicated preview scene that is never put on air, so OBS renders them
+// for screenshots without anything reaching the output. Preview inputs use
+// a dedicated name so they never collide with a program input.
+//
+// Contents:
+// - Preview Inputs
+
+package switcher
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/andreykaipov/goobs"
+	"github.com/andreykaipov/goobs/api/requests/scenes"
+	"scenescheduler/backend/eventbus"
+)
+
+// ============================================================================
+// PREVIEW INPUTS
+// ============================================================================
+
+// CreatePreviewInput creates the input of a source preview in the preview
+// scene, creating the scene if needed, and returns the input name.
+func (s *Switcher) CreatePreviewInput(client *goobs.Client, preview *eventbus.Program) (string, error) {
+	previewScene := s.config.PreviewScene
+	inputName := s.itemName(preview)
+
+	listResp, err := client.Inputs.GetInputKindList()
+	if err != nil {
+		return "", fmt.Errorf("could not fetch supported input kinds: %w", err)
+	}
+	if !slices.Contains(listResp.InputKinds, preview.InputKind) {
+		return "", fmt.Errorf("input kind %q is not supported by OBS", preview.InputKind)
+	}
+
+	if err := s.ensurePreviewScene(client); err != nil {
+		return "", err
+	}
+
+	// A preview input may be left over from a previous run
+	if err := s.removeInputIfExists(client, inputName); err != nil {
+		s.logger.Warn("Failed to remove existing preview input, will attempt to continue",
+			"error", err,
+			"inputName", inputName)
+	}
+
+	if _, err := s.createInputInScene(client, previewScene, inputName, preview, true); err != nil {
+		return "", fmt.Errorf("failed to create preview input: %w", err)
+	}
+	return inputName, nil
+}
+
+// RemovePreviewInput removes the input of a source preview. Removal is
+// best-effort and idempotent.
+func (s *Switcher) RemovePreviewInput(client *goobs.Client, preview *eventbus.Program) error {
+	return s.removeOBSInput(client, s.config.PreviewScene, preview)
+}
+
+// ensurePreviewScene creates the preview scene if it does not exist.
+func (s *Switcher) ensurePreviewScene(client *goobs.Client) error {
+	previewScene := s.config.PreviewScene
+
+	resp, err := client.Scenes.GetSceneList()
+	if err != nil {
+		return fmt.Errorf("could not get scene list: %w", err)
+	}
+	for _, scene := range resp.Scenes {
+		if scene.SceneName == previewScene {
+			return nil
+		}
+	}
+
+	s.logger.Debug("Preview scene not found, creating it.", "sceneName", previewScene)
+	if _, err := client.Scenes.CreateScene(&scenes.CreateSceneParams{SceneName: &previewScene}); err != nil {
+		return fmt.Errorf("failed to create preview scene %q: %w", previewScene, err)
+	}
+	return nil
+}
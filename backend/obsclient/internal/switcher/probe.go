@@ -0,0 +1,126 @@
+// backend/obsclient/internal/switcher/probe.go
+//
+// This file contains the media probing helpers. They reuse the staging flow
+// to create an input hidden in the temporary scene so OBS can open the media,
+// without ever showing it in the main scene.
+//
+// Contents:
+// - Media Duration Probing
+// - Trial Inputs
+
+package switcher
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/andreykaipov/goobs"
+	"github.com/andreykaipov/goobs/api/requests/mediainputs"
+	"scenescheduler/backend/eventbus"
+)
+
+// probePollInterval is how often OBS is asked for the media status while
+// waiting for the duration to become available.
+const probePollInterval = 200 * time.Millisecond
+
+// mediaStateError is the OBS media state of an input that failed to open.
+const mediaStateError = "OBS_MEDIA_STATE_ERROR"
+
+// ============================================================================
+// MEDIA DURATION PROBING
+// ============================================================================
+
+// ProbeMediaDuration stages the program's input hidden in the temporary scene,
+// waits up to `timeout` for OBS to report the media duration, and removes the
+// input again. The input uses a dedicated name so it never collides with the
+// program on air.
+func (s *Switcher) ProbeMediaDuration(client *goobs.Client, program *eventbus.Program, timeout time.Duration) (time.Duration, error) {
+	tmpScene := s.config.ScheduleSceneAux
+
+	probe := *program
+	probe.SourceName = fmt.Sprintf("probe-%d", time.Now().UnixNano())
+	inputName := s.config.SourceNamePrefix + probe.SourceName
+
+	if _, err := s.createInputInScene(client, tmpScene, inputName, &probe, false); err != nil {
+		return 0, fmt.Errorf("failed to stage probe input: %w", err)
+	}
+	// CLEANUP: The probe input is always removed, best-effort
+	defer func() { _ = s.removeOBSInput(client, tmpScene, &probe) }()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		resp, err := client.MediaInputs.GetMediaInputStatus(&mediainputs.GetMediaInputStatusParams{
+			InputName: &inputName,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to query media status: %w", err)
+		}
+		if resp.MediaDuration > 0 {
+			s.logger.Debug("Probed media duration through OBS",
+				"uri", program.URI,
+				"durationMs", resp.MediaDuration,
+				"state", resp.MediaState)
+			return time.Duration(resp.MediaDuration * float64(time.Millisecond)), nil
+		}
+		if time.Now().After(deadline) {
+			return 0, fmt.Errorf("OBS did not report a duration within %s (state %q)", timeout, resp.MediaState)
+		}
+		time.Sleep(probePollInterval)
+	}
+}
+
+// ============================================================================
+// TRIAL INPUTS
+// ============================================================================
+
+// TrialInputs creates each input of a program hidden in the temporary scene,
+// as a pre-flight check that OBS accepts it, and removes it again. Media
+// inputs are watched for up to `timeout` and fail if OBS cannot open them;
+// an input still opening by then passes. Existing scenes are not staged.
+func (s *Switcher) TrialInputs(client *goobs.Client, program *eventbus.Program, timeout time.Duration) error {
+	for _, layer := range programLayers(program) {
+		if isSceneProgram(layer) {
+			continue
+		}
+		if err := s.trialInput(client, layer, timeout); err != nil {
+			return fmt.Errorf("%s: %w", layer.Title, err)
+		}
+	}
+	return nil
+}
+
+// trialInput creates one trial input and removes it again.
+func (s *Switcher) trialInput(client *goobs.Client, program *eventbus.Program, timeout time.Duration) error {
+	tmpScene := s.config.ScheduleSceneAux
+
+	trial := *program
+	trial.SourceName = fmt.Sprintf("preflight-%d", time.Now().UnixNano())
+	inputName := s.config.SourceNamePrefix + trial.SourceName
+
+	if _, err := s.createInputInScene(client, tmpScene, inputName, &trial, false); err != nil {
+		return fmt.Errorf("OBS could not create the input: %w", err)
+	}
+	// CLEANUP: The trial input is always removed, best-effort
+	defer func() { _ = s.removeOBSInput(client, tmpScene, &trial) }()
+
+	if trial.InputKind != "ffmpeg_source" && trial.InputKind != "vlc_source" {
+		return nil
+	}
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		resp, err := client.MediaInputs.GetMediaInputStatus(&mediainputs.GetMediaInputStatusParams{
+			InputName: &inputName,
+		})
+		if err != nil {
+			return nil // No media status to judge by
+		}
+		if resp.MediaState == mediaStateError {
+			return fmt.Errorf("OBS could not open the media")
+		}
+		if resp.MediaDuration > 0 || resp.MediaState == "OBS_MEDIA_STATE_PLAYING" {
+			return nil
+		}
+		time.Sleep(probePollInterval)
+	}
+	return nil
+}
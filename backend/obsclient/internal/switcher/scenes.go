@@ -0,0 +1,114 @@
+// backend/obsclient/internal/switcher/scenes.go
+//
+// This file contains the helpers for programs that air an existing,
+// operator-built OBS scene. A nested scene is staged and promoted like any
+// input, but only its scene items are ever removed, never the scene itself.
+// A scene in program mode bypasses staging and becomes the program scene.
+//
+// Contents:
+// - Scene Program Helpers
+// - Nested Scenes
+// - Program Scene Mode
+
+package switcher
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/andreykaipov/goobs"
+	"github.com/andreykaipov/goobs/api/requests/sceneitems"
+	"github.com/andreykaipov/goobs/api/requests/scenes"
+	"scenescheduler/backend/eventbus"
+)
+
+// ============================================================================
+// SCENE PROGRAM HELPERS
+// ============================================================================
+
+// isSceneProgram reports whether a program airs an existing OBS scene.
+func isSceneProgram(p *eventbus.Program) bool {
+	return p != nil && p.SceneName != ""
+}
+
+// isProgramSceneMode reports whether a program's scene replaces the program
+// scene in OBS instead of being nested in the schedule scene.
+func isProgramSceneMode(p *eventbus.Program) bool {
+	return isSceneProgram(p) && p.SceneMode == eventbus.SceneModeProgram
+}
+
+// itemName returns the source name a program appears under in a scene: the
+// operator's scene for scene programs, or the managed input name otherwise.
+func (s *Switcher) itemName(p *eventbus.Program) string {
+	if isSceneProgram(p) {
+		return p.SceneName
+	}
+	return s.config.SourceNamePrefix + p.SourceName
+}
+
+// ============================================================================
+// NESTED SCENES
+// ============================================================================
+
+// stageScene nests an existing scene, hidden, in the temporary scene. From
+// here on the regular promotion and activation steps apply.
+func (s *Switcher) stageScene(client *goobs.Client, program *eventbus.Program) (int, error) {
+	tmpScene := s.config.ScheduleSceneAux
+
+	if program.SceneName == s.config.ScheduleScene || program.SceneName == tmpScene {
+		return 0, fmt.Errorf("scene '%s' is managed by the scheduler and cannot be scheduled", program.SceneName)
+	}
+
+	resp, err := client.SceneItems.CreateSceneItem(&sceneitems.CreateSceneItemParams{
+		SceneName:        &tmpScene,
+		SourceName:       &program.SceneName,
+		SceneItemEnabled: &[]bool{false}[0],
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to nest scene '%s' in temp scene: %w", program.SceneName, err)
+	}
+
+	s.logger.Debug("Staged existing scene in temp scene",
+		"sceneName", program.SceneName,
+		"sceneItemId", resp.SceneItemId)
+	return resp.SceneItemId, nil
+}
+
+// ============================================================================
+// PROGRAM SCENE MODE
+// ============================================================================
+
+// switchToProgramScene makes the target's scene the program scene in OBS.
+// The schedule scene is off air afterwards, so the previous program is
+// cleaned up right away. OBS applies its own scene transition.
+func (s *Switcher) switchToProgramScene(client *goobs.Client, current, target *eventbus.Program, offset time.Duration) (*SwitchResult, error) {
+	// CRITICAL: If the scene cannot be put on air, nothing has changed
+	if _, err := client.Scenes.SetCurrentProgramScene(&scenes.SetCurrentProgramSceneParams{
+		SceneName: &target.SceneName,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to set program scene to '%s': %w", target.SceneName, err)
+	}
+
+	s.cleanupAfterSwitch(client, current, target)
+
+	s.logger.InfoGui("Program switch completed successfully", "target", getTargetTitle(target), "programScene", target.SceneName)
+
+	return &SwitchResult{
+		PreviousProgram: current,
+		CurrentProgram:  target,
+		SeekOffsetMs:    offset.Milliseconds(),
+		Timestamp:       time.Now(),
+	}, nil
+}
+
+// restoreScheduleScene makes the schedule scene the program scene again
+// after a program in program scene mode.
+func (s *Switcher) restoreScheduleScene(client *goobs.Client) error {
+	mainScene := s.config.ScheduleScene
+	if _, err := client.Scenes.SetCurrentProgramScene(&scenes.SetCurrentProgramSceneParams{
+		SceneName: &mainScene,
+	}); err != nil {
+		return fmt.Errorf("failed to restore program scene '%s': %w", mainScene, err)
+	}
+	return nil
+}
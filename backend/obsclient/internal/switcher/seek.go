@@ -0,0 +1,83 @@
+// backend/obsclient/internal/switcher/seek.go
+//
+// This file applies the seek offset to media sources after they go on air, so
+// a program joined late, or resumed after a break, continues where the
+// schedule says it should be instead of starting from the beginning.
+//
+// Contents:
+// - Media Seeking
+
+package switcher
+
+import (
+	"time"
+
+	"github.com/andreykaipov/goobs"
+	"github.com/andreykaipov/goobs/api/requests/mediainputs"
+	"scenescheduler/backend/eventbus"
+)
+
+const (
+	// minSeekOffset is the smallest offset worth seeking to. Below it the
+	// program is treated as starting on time.
+	minSeekOffset = time.Second
+
+	// seekWaitTimeout bounds how long the media is given to open before the
+	// seek is attempted anyway.
+	seekWaitTimeout = 3 * time.Second
+)
+
+// ============================================================================
+// MEDIA SEEKING
+// ============================================================================
+
+// isSeekable reports whether the input kind supports the media cursor.
+func isSeekable(inputKind string) bool {
+	return inputKind == "ffmpeg_source" || inputKind == "vlc_source"
+}
+
+// seekMedia moves the media cursor of the program's input to `offset`. It
+// waits for the media to open first, since OBS ignores cursor changes on media
+// that has not started. Seeking is best-effort: a failure leaves the program
+// playing from the beginning and is only logged.
+func (s *Switcher) seekMedia(client *goobs.Client, program *eventbus.Program, offset time.Duration) {
+	inputName := s.config.SourceNamePrefix + program.SourceName
+
+	deadline := time.Now().Add(seekWaitTimeout)
+	for {
+		resp, err := client.MediaInputs.GetMediaInputStatus(&mediainputs.GetMediaInputStatusParams{
+			InputName: &inputName,
+		})
+		if err != nil {
+			s.logger.Debug("Could not query media status before seeking", "input", inputName, "error", err)
+			return
+		}
+		if resp.MediaDuration > 0 {
+			if offset.Milliseconds() >= int64(resp.MediaDuration) {
+				s.logger.Warn("Seek offset is past the end of the media, playing from the beginning",
+					"program", getTargetTitle(program),
+					"offsetMs", offset.Milliseconds(),
+					"durationMs", resp.MediaDuration)
+				return
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(probePollInterval)
+	}
+
+	cursor := float64(offset.Milliseconds())
+	if _, err := client.MediaInputs.SetMediaInputCursor(&mediainputs.SetMediaInputCursorParams{
+		InputName:   &inputName,
+		MediaCursor: &cursor,
+	}); err != nil {
+		s.logger.Warn("Failed to seek media, playing from the beginning",
+			"program", getTargetTitle(program),
+			"offsetMs", offset.Milliseconds(),
+			"error", err)
+		return
+	}
+	s.logger.Debug("Seeked media", "program", getTargetTitle(program), "offsetMs", offset.Milliseconds())
+}
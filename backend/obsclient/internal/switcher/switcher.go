@@ -4,6 +4,7 @@ package switcher
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"scenescheduler/backend/config"
@@ -61,7 +62,8 @@ type Switcher struct {
 	config *config.OBSConfig
 
 	// --- Synchronization ---
-	switchMu sync.Mutex // Serializes all switching operations
+	switchMu      sync.Mutex    // Serializes all switching operations
+	transitionGen atomic.Uint64 // Incremented by every switch; lets a running transition detect it was superseded
 }
 
 // SwitchResult contains the outcome of a successful program switch operation.
@@ -91,18 +93,23 @@ func New(log *logger.Logger, cfg *config.OBSConfig) *Switcher {
 
 // PerformSwitch handles the transactional logic of switching from one program to another.
 // It performs a 6-step staging process to ensure glitch-free transitions:
-// 1. Stage new source in temp scene
+// 1. Stage new sources (one per layer) in temp scene
 // 2. Duplicate to main scene
-// 3. Activate new source
+// 3. Activate new sources with their transition (and seek media to the offset)
 // 4. Cleanup temp scene
 // 5. Cleanup previous program
 // 6. Cleanup any orphaned managed sources
 //
+// For a cut, steps 5 and 6 run before returning. For a fade, stinger or audio
+// crossfade they are deferred until the transition completes; see
+// transition.go. Programs
+// that make an existing scene the program scene skip steps 1-4; see scenes.go.
+//
 // Parameters:
 //   - client: Active OBS websocket client
 //   - current: Currently active program (nil if none)
 //   - target: Program to switch to (nil to clear all)
-//   - offset: Time offset for media sources (currently unused)
+//   - offset: Time offset into the target, applied to seekable media sources
 //
 // Returns:
 //   - *SwitchResult: Information about the completed switch for event publishing
@@ -118,79 +125,166 @@ func (s *Switcher) PerformSwitch(
 
 	mainScene := s.config.ScheduleScene
 	tmpScene := s.config.ScheduleSceneAux
-	var newTempSceneItemID int
-	var newMainSceneItemID int
-	var err error
+
+	gen := s.transitionGen.Add(1)
+	transition := s.resolveTransition(current, target)
 
 	s.logger.Debug("Starting program switch",
 		"current", getTargetTitle(current),
 		"target", getTargetTitle(target),
+		"transition", transition.Type,
 		"mainScene", mainScene,
 		"tmpScene", tmpScene)
 
-	// --- 1. STAGING: Create and prepare the new source in the temp scene ---
-	if target != nil {
-		// CRITICAL: Input creation failure is fatal
-		newTempSceneItemID, err = s.createOBSInput(client, target)
+	// Programs that make an existing scene the program scene bypass staging
+	if isProgramSceneMode(target) {
+		return s.switchToProgramScene(client, current, target, offset)
+	}
+
+	// A composite program is switched as a unit: every layer is staged and
+	// promoted before any of them is shown, and a failure in any layer rolls
+	// back all of them.
+	layers, err := stagedLayersFor(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid layers for '%s': %w", target.Title, err)
+	}
+
+	// --- 1. STAGING: Create and prepare the new sources in the temp scene ---
+	for _, layer := range layers {
+		// CRITICAL: Input creation failure is fatal, requires rollback
+		layer.tempItemID, err = s.createOBSInput(client, layer.program)
 		if err != nil {
-			s.logger.Error("Failed to create OBS input", "error", err)
+			s.logger.Error("Failed to create OBS input, rolling back.", "error", err, "source", layer.program.SourceName)
+			// CLEANUP: Rollback is best-effort
+			s.rollbackLayers(client, layers)
 			return nil, fmt.Errorf("failed to create OBS input for '%s': %w", target.Title, err)
 		}
 
 		// IMPORTANT: Transform failure is non-fatal, log and continue
-		if err := s.applyTransformsToSceneItem(client, tmpScene, newTempSceneItemID, target.Transform); err != nil {
+		if err := s.applyTransformsToSceneItem(client, tmpScene, layer.tempItemID, layer.program.Transform); err != nil {
 			s.logger.Warn("Failed to apply transform to temp scene item, using defaults.",
 				"error", err,
 				"scene", tmpScene)
 		}
-	} else {
+	}
+	if target == nil {
 		s.logger.InfoGui("Target program is nil, will cleanup all managed sources")
 	}
 
-	// --- 2. PROMOTION: Duplicate the prepared item to the main scene ---
-	if target != nil {
+	// --- 2. PROMOTION: Duplicate the prepared items to the main scene ---
+	// Layers are duplicated bottom to top, so each lands above the previous one.
+	for _, layer := range layers {
 		// CRITICAL: Duplication failure is fatal, requires rollback
-		newMainSceneItemID, err = s.duplicateSceneItem(client, tmpScene, mainScene, newTempSceneItemID)
+		layer.mainItemID, err = s.duplicateSceneItem(client, tmpScene, mainScene, layer.tempItemID)
 		if err != nil {
 			s.logger.Error("Failed to duplicate item to main scene, rolling back.", "error", err)
 			// CLEANUP: Rollback is best-effort
-			_ = s.removeOBSInput(client, tmpScene, target)
+			s.rollbackLayers(client, layers)
 			return nil, fmt.Errorf("failed to duplicate item for '%s': %w", target.Title, err)
 		}
 	}
 
-	// --- 3. ACTIVATION: Make the new source visible in the main scene ---
+	// --- 3. ACTIVATION: Make the new sources visible in the main scene ---
 	if target != nil {
-		// IMPORTANT: Transform failure is non-fatal, log and continue
-		if err := s.applyTransformsToSceneItem(client, mainScene, newMainSceneItemID, target.Transform); err != nil {
-			s.logger.Warn("Failed to apply transform to main scene item, using defaults.",
-				"error", err,
-				"scene", mainScene)
+		for _, layer := range layers {
+			// IMPORTANT: Transform failure is non-fatal, log and continue
+			if err := s.applyTransformsToSceneItem(client, mainScene, layer.mainItemID, layer.program.Transform); err != nil {
+				s.logger.Warn("Failed to apply transform to main scene item, using defaults.",
+					"error", err,
+					"scene", mainScene)
+			}
 		}
 
-		// CRITICAL: Activation failure is fatal, requires rollback
-		if err := s.setSceneItemEnabled(client, mainScene, newMainSceneItemID, true); err != nil {
-			s.logger.Error("Failed to make new scene item visible, rolling back.", "error", err)
-			// CLEANUP: Rollback is best-effort
-			_ = s.removeOBSInput(client, tmpScene, target)
-			_ = s.removeOBSInput(client, mainScene, target)
-			return nil, fmt.Errorf("failed to make new item visible for '%s': %w", target.Title, err)
+		// IMPORTANT: A transition that cannot be prepared falls back to a cut
+		if err := s.prepareTransition(client, transition, current, target); err != nil {
+			s.logger.Warn("Failed to prepare transition, cutting instead.", "error", err, "transition", transition.Type)
+			s.abortTransition(client, transition)
+			transition = eventbus.Transition{Type: eventbus.TransitionCut, AudioFadeMs: transition.AudioFadeMs}
+		}
+
+		// IMPORTANT: Under a stinger the new items are shown once the clip
+		// covers the screen, by the transition goroutine (see runTransition)
+		if transition.Type != eventbus.TransitionStinger {
+			for _, layer := range layers {
+				// CRITICAL: Activation failure is fatal, requires rollback
+				if err := s.setSceneItemEnabled(client, mainScene, layer.mainItemID, true); err != nil {
+					s.logger.Error("Failed to make new scene item visible, rolling back.", "error", err)
+					// CLEANUP: Rollback is best-effort
+					s.abortTransition(client, transition)
+					s.rollbackLayers(client, layers)
+					return nil, fmt.Errorf("failed to make new item visible for '%s': %w", target.Title, err)
+				}
+			}
+		}
+	} else if current != nil {
+		// IMPORTANT: Taking the last program off air can also use a transition
+		if err := s.prepareTransition(client, transition, current, nil); err != nil {
+			s.logger.Warn("Failed to prepare transition, cutting instead.", "error", err, "transition", transition.Type)
+			s.abortTransition(client, transition)
+			transition = eventbus.Transition{Type: eventbus.TransitionCut, AudioFadeMs: transition.AudioFadeMs}
 		}
 	}
 
-	// --- 4. CLEANUP (Staging): Remove the temporary item for the new program ---
-	if target != nil {
+	// IMPORTANT: If the previous program replaced the program scene, put the
+	// schedule scene back on air
+	if isProgramSceneMode(current) {
+		if err := s.restoreScheduleScene(client); err != nil {
+			s.logger.Warn("Failed to restore the schedule scene as program scene.", "error", err)
+		}
+	}
+
+	// IMPORTANT: Seeking is best-effort and runs in the background so the
+	// switch is not held up while the media opens. Only the main source is
+	// seeked; layers are usually loops or graphics.
+	// Under a stinger the program appears at the stinger point, so the media
+	// is seeked that much further.
+	if target != nil && offset >= minSeekOffset && isSeekable(target.InputKind) {
+		if transition.Type == eventbus.TransitionStinger {
+			offset += stingerPoint(transition)
+		}
+		go s.seekMedia(client, target, offset)
+	}
+
+	// --- 4. CLEANUP (Staging): Remove the temporary items for the new program ---
+	for _, layer := range layers {
 		// CLEANUP: Temp item removal is best-effort, failure is silent
 		if _, remErr := client.SceneItems.RemoveSceneItem(&sceneitems.RemoveSceneItemParams{
 			SceneName:   &tmpScene,
-			SceneItemId: &newTempSceneItemID,
+			SceneItemId: &layer.tempItemID,
 		}); remErr != nil {
 			s.logger.Debug("Could not remove temp scene item (may already be gone)",
-				"sceneItemId", newTempSceneItemID,
+				"sceneItemId", layer.tempItemID,
 				"error", remErr)
 		}
 	}
 
+	// --- 5 & 6. CLEANUP: Right away for a cut, after the transition otherwise ---
+	if transition.Type == eventbus.TransitionCut && transition.AudioFadeMs == 0 {
+		s.cleanupAfterSwitch(client, current, target)
+	} else {
+		go s.runTransition(client, gen, transition, current, target, layers)
+	}
+
+	s.logger.InfoGui("Program switch completed successfully", "target", getTargetTitle(target))
+	
+	// Return the result for the parent to publish as an event
+	return &SwitchResult{
+		PreviousProgram: current,
+		CurrentProgram:  target,
+		SeekOffsetMs:    offset.Milliseconds(),
+		Timestamp:       time.Now(),
+	}, nil
+}
+
+// ============================================================================
+// HELPERS
+// ============================================================================
+
+// cleanupAfterSwitch removes the previous program and any other managed
+// sources once the target is on air. Must be called with switchMu held.
+func (s *Switcher) cleanupAfterSwitch(client *goobs.Client, current, target *eventbus.Program) {
+	mainScene := s.config.ScheduleScene
+
 	// --- 5. CLEANUP (Previous): Remove the previous program if known ---
 	if current != nil {
 		s.logger.InfoGui("Cleaning up previous program", "program", getTargetTitle(current))
@@ -207,22 +301,8 @@ func (s *Switcher) PerformSwitch(
 	if err := s.cleanupOrphanedManagedSources(client, mainScene, current, target); err != nil {
 		s.logger.Warn("Failed to cleanup orphaned managed sources.", "error", err)
 	}
-
-	s.logger.InfoGui("Program switch completed successfully", "target", getTargetTitle(target))
-	
-	// Return the result for the parent to publish as an event
-	return &SwitchResult{
-		PreviousProgram: current,
-		CurrentProgram:  target,
-		SeekOffsetMs:    offset.Milliseconds(),
-		Timestamp:       time.Now(),
-	}, nil
 }
 
-// ============================================================================
-// HELPERS
-// ============================================================================
-
 // getTargetTitle returns a display-friendly name for a program object,
 // primarily used for logging.
 func getTargetTitle(p *eventbus.Program) string {
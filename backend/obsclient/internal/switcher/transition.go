@@ -0,0 +1,344 @@
+// backend/obsclient/internal/switcher/transition.go
+//
+// This file implements program transitions. A cut enables the new item and
+// cleans up the previous one straight away. A fade animates the opacity of a
+// color correction filter on the new item, which sits on top of the previous
+// one in the main scene; composite programs fade all their layers together. A
+// stinger plays a media clip on top of both and cuts underneath it. Any of
+// them can crossfade the audio as well (see audio.go). For fades, stingers
+// and crossfades, cleanup of the previous program is deferred until the
+// transition has completed.
+//
+// Contents:
+// - Transition Resolution
+// - Transition Preparation
+// - Transition Completion
+// - Transition Helpers
+
+package switcher
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/andreykaipov/goobs"
+	"github.com/andreykaipov/goobs/api/requests/filters"
+	"scenescheduler/backend/eventbus"
+)
+
+const (
+	// fadeFilterKind is the OBS color correction filter, whose opacity setting
+	// is animated during a fade.
+	fadeFilterKind = "color_filter_v2"
+
+	// transitionFrameInterval is how often the fade opacity is updated.
+	transitionFrameInterval = 40 * time.Millisecond
+
+	// maxStingerPoint bounds how long the new program stays hidden under the
+	// stinger before it is shown.
+	maxStingerPoint = 5 * time.Second
+)
+
+// ============================================================================
+// TRANSITION RESOLUTION
+// ============================================================================
+
+// resolveTransition returns the transition used to bring target on air, or to
+// take current off air when target is nil. Fields the program leaves empty
+// come from the configured default. Anything that cannot be performed falls
+// back to a cut. The audio crossfade applies to every transition type,
+// including a cut.
+func (s *Switcher) resolveTransition(current, target *eventbus.Program) eventbus.Transition {
+	t := s.resolveVisualTransition(current, target)
+	if isProgramSceneMode(current) || isProgramSceneMode(target) {
+		return t
+	}
+	t.AudioFadeMs = s.config.Transition.AudioFadeMs
+	if target != nil && target.Transition != nil && target.Transition.AudioFadeMs > 0 {
+		t.AudioFadeMs = target.Transition.AudioFadeMs
+	}
+	return t
+}
+
+// resolveVisualTransition resolves the type and timing of the transition,
+// without its audio crossfade.
+func (s *Switcher) resolveVisualTransition(current, target *eventbus.Program) eventbus.Transition {
+	def := s.config.Transition
+	t := eventbus.Transition{
+		Type:           def.Type,
+		DurationMs:     def.DurationMs,
+		StingerURI:     def.StingerURI,
+		StingerPointMs: def.StingerPointMs,
+	}
+	if target != nil && target.Transition != nil {
+		own := target.Transition
+		t.Type = own.Type
+		if own.DurationMs > 0 {
+			t.DurationMs = own.DurationMs
+		}
+		if own.StingerURI != "" {
+			t.StingerURI = own.StingerURI
+		}
+		if own.StingerPointMs > 0 {
+			t.StingerPointMs = own.StingerPointMs
+		}
+	}
+
+	cut := eventbus.Transition{Type: eventbus.TransitionCut}
+	if isProgramSceneMode(current) || isProgramSceneMode(target) {
+		// OBS applies its own scene transition when the program scene changes
+		return cut
+	}
+	switch t.Type {
+	case eventbus.TransitionFade:
+		if t.DurationMs <= 0 || (current == nil && target == nil) {
+			return cut
+		}
+	case eventbus.TransitionStinger:
+		if t.StingerURI == "" || t.DurationMs <= 0 {
+			s.logger.Warn("Stinger transition has no clip or duration, cutting instead", "target", getTargetTitle(target))
+			return cut
+		}
+		t.StingerPointMs = min(t.StingerPointMs, t.DurationMs)
+	case eventbus.TransitionCut, "":
+		return cut
+	default:
+		s.logger.Warn("Unknown transition type, cutting instead", "type", t.Type, "target", getTargetTitle(target))
+		return cut
+	}
+	return t
+}
+
+// ============================================================================
+// TRANSITION PREPARATION
+// ============================================================================
+
+// prepareTransition runs just before the new item is made visible. For a fade
+// it adds the filter at its starting opacity; for a stinger it puts the clip
+// on air, and the new item is shown later by runTransition. With an audio
+// crossfade the new program is silenced so it can fade in.
+func (s *Switcher) prepareTransition(client *goobs.Client, t eventbus.Transition, current, target *eventbus.Program) error {
+	if t.AudioFadeMs > 0 && target != nil {
+		s.silenceProgram(client, target)
+	}
+
+	switch t.Type {
+	case eventbus.TransitionFade:
+		if target != nil {
+			return s.addFadeFilters(client, target, 0)
+		}
+		return s.addFadeFilters(client, current, 1)
+
+	case eventbus.TransitionStinger:
+		return s.startStinger(client, t.StingerURI)
+	}
+	return nil
+}
+
+// stingerPoint is how long after the stinger starts the new program is shown.
+func stingerPoint(t eventbus.Transition) time.Duration {
+	return min(time.Duration(t.StingerPointMs)*time.Millisecond, maxStingerPoint)
+}
+
+// startStinger creates the stinger input directly in the main scene, on top
+// of everything else, stretched to the canvas.
+func (s *Switcher) startStinger(client *goobs.Client, uri string) error {
+	mainScene := s.config.ScheduleScene
+	stinger := s.stingerProgram(uri)
+	inputName := s.config.SourceNamePrefix + stinger.SourceName
+
+	if err := s.removeInputIfExists(client, inputName); err != nil {
+		s.logger.Debug("Could not remove previous stinger input", "error", err)
+	}
+	resp, err := s.createInputInScene(client, mainScene, inputName, stinger, false)
+	if err != nil {
+		return fmt.Errorf("failed to create stinger input: %w", err)
+	}
+	if err := s.applyDefaultTransform(client, mainScene, resp.SceneItemId); err != nil {
+		s.logger.Debug("Could not stretch stinger to the canvas", "error", err)
+	}
+	if err := s.setSceneItemEnabled(client, mainScene, resp.SceneItemId, true); err != nil {
+		_ = s.removeOBSInput(client, mainScene, stinger)
+		return fmt.Errorf("failed to show stinger: %w", err)
+	}
+	return nil
+}
+
+// abortTransition undoes prepareTransition after a failed switch.
+func (s *Switcher) abortTransition(client *goobs.Client, t eventbus.Transition) {
+	if t.Type == eventbus.TransitionStinger {
+		// CLEANUP: Best-effort
+		_ = s.removeOBSInput(client, s.config.ScheduleScene, s.stingerProgram(t.StingerURI))
+	}
+}
+
+// ============================================================================
+// TRANSITION COMPLETION
+// ============================================================================
+
+// runTransition plays out the rest of a fade or stinger, then performs the
+// deferred cleanup. Under a stinger it also shows the staged layers of the
+// new program once the clip reaches its cut point. If another switch started
+// in the meantime, that switch has already cleaned up the programs involved
+// and nothing is left to do.
+func (s *Switcher) runTransition(client *goobs.Client, gen uint64, t eventbus.Transition, current, target *eventbus.Program, layers []*stagedLayer) {
+	duration := time.Duration(t.DurationMs) * time.Millisecond
+
+	// The audio crossfade runs alongside the picture and may outlast it
+	var audio sync.WaitGroup
+	if t.AudioFadeMs > 0 {
+		audio.Add(1)
+		go func() {
+			defer audio.Done()
+			s.crossfadeAudio(client, gen, current, target, time.Duration(t.AudioFadeMs)*time.Millisecond)
+		}()
+	}
+
+	switch t.Type {
+	case eventbus.TransitionFade:
+		if target != nil {
+			s.animateOpacity(client, gen, target, 0, 1, duration)
+		} else {
+			s.animateOpacity(client, gen, current, 1, 0, duration)
+		}
+	case eventbus.TransitionStinger:
+		point := stingerPoint(t)
+		time.Sleep(point)
+		s.revealLayers(client, gen, target, layers)
+		time.Sleep(duration - point)
+	}
+	audio.Wait()
+
+	s.switchMu.Lock()
+	defer s.switchMu.Unlock()
+
+	// Fade filters are removed even if superseded: on an operator-built scene
+	// they would otherwise outlive the program
+	if t.Type == eventbus.TransitionFade {
+		s.removeFadeFilters(client, current)
+		s.removeFadeFilters(client, target)
+	}
+
+	if s.transitionGen.Load() != gen {
+		s.logger.Debug("Transition superseded by a newer switch", "target", getTargetTitle(target))
+		return
+	}
+
+	switch t.Type {
+	case eventbus.TransitionStinger:
+		// CLEANUP: Best-effort
+		_ = s.removeOBSInput(client, s.config.ScheduleScene, s.stingerProgram(t.StingerURI))
+	}
+
+	s.cleanupAfterSwitch(client, current, target)
+	s.logger.Debug("Transition completed", "type", t.Type, "target", getTargetTitle(target))
+}
+
+// revealLayers shows the staged layers of a program that came on air under a
+// stinger, unless a newer switch has started since.
+func (s *Switcher) revealLayers(client *goobs.Client, gen uint64, target *eventbus.Program, layers []*stagedLayer) {
+	if target == nil {
+		return
+	}
+
+	s.switchMu.Lock()
+	defer s.switchMu.Unlock()
+
+	if s.transitionGen.Load() != gen {
+		return
+	}
+	for _, layer := range layers {
+		if err := s.setSceneItemEnabled(client, s.config.ScheduleScene, layer.mainItemID, true); err != nil {
+			s.logger.Error("Failed to show new scene item under the stinger", "error", err, "target", getTargetTitle(target))
+		}
+	}
+}
+
+// animateOpacity moves the fade filters of a program, on every layer, from
+// one opacity to another over the given duration. It stops early if a newer
+// switch starts or a source disappears.
+func (s *Switcher) animateOpacity(client *goobs.Client, gen uint64, program *eventbus.Program, from, to float64, duration time.Duration) {
+	filterName := s.fadeFilterName()
+	layers := programLayers(program)
+
+	start := time.Now()
+	for s.transitionGen.Load() == gen {
+		progress := min(float64(time.Since(start))/float64(duration), 1)
+		for _, layer := range layers {
+			sourceName := s.itemName(layer)
+			_, err := client.Filters.SetSourceFilterSettings(&filters.SetSourceFilterSettingsParams{
+				SourceName:     &sourceName,
+				FilterName:     &filterName,
+				FilterSettings: map[string]any{"opacity": from + (to-from)*progress},
+			})
+			if err != nil {
+				s.logger.Debug("Fade interrupted", "source", sourceName, "error", err)
+				return
+			}
+		}
+		if progress >= 1 {
+			return
+		}
+		time.Sleep(transitionFrameInterval)
+	}
+}
+
+// ============================================================================
+// TRANSITION HELPERS
+// ============================================================================
+
+// addFadeFilters adds the fade filter to every layer of a program at the
+// given opacity.
+func (s *Switcher) addFadeFilters(client *goobs.Client, program *eventbus.Program, opacity float64) error {
+	filterName := s.fadeFilterName()
+	filterKind := fadeFilterKind
+
+	// A filter left behind by an interrupted fade would make creation fail
+	s.removeFadeFilters(client, program)
+
+	for _, layer := range programLayers(program) {
+		sourceName := s.itemName(layer)
+		if _, err := client.Filters.CreateSourceFilter(&filters.CreateSourceFilterParams{
+			SourceName:     &sourceName,
+			FilterName:     &filterName,
+			FilterKind:     &filterKind,
+			FilterSettings: map[string]any{"opacity": opacity},
+		}); err != nil {
+			s.removeFadeFilters(client, program)
+			return fmt.Errorf("failed to add fade filter to '%s': %w", sourceName, err)
+		}
+	}
+	return nil
+}
+
+// removeFadeFilters removes the fade filter from every layer of a program,
+// where present.
+func (s *Switcher) removeFadeFilters(client *goobs.Client, program *eventbus.Program) {
+	filterName := s.fadeFilterName()
+	for _, layer := range programLayers(program) {
+		sourceName := s.itemName(layer)
+		// CLEANUP: Best-effort, the filter may not exist
+		_, _ = client.Filters.RemoveSourceFilter(&filters.RemoveSourceFilterParams{
+			SourceName: &sourceName,
+			FilterName: &filterName,
+		})
+	}
+}
+
+// fadeFilterName is the name of the filter used for fades.
+func (s *Switcher) fadeFilterName() string {
+	return s.config.SourceNamePrefix + "fade"
+}
+
+// stingerProgram describes the stinger clip as a program, so the regular
+// input creation and removal helpers can be used for it.
+func (s *Switcher) stingerProgram(uri string) *eventbus.Program {
+	return &eventbus.Program{
+		ID:         "stinger",
+		Title:      "Stinger",
+		SourceName: "stinger",
+		InputKind:  "ffmpeg_source",
+		URI:        uri,
+	}
+}
@@ -0,0 +1,351 @@
+// backend/obsclient/loudness.go
+//
+// This file implements loudness normalization. OBS reports the levels of all
+// active inputs every 50 ms through the InputVolumeMeters event; the levels
+// of the inputs on air are combined into a short-term loudness estimate per
+// program, and the volume of those inputs is nudged toward the configured
+// target within limits. When a program leaves the air its measurements are
+// logged and written to the as-run log, so editors can fix badly mastered
+// media at the source.
+//
+// The estimate follows the EBU R128 short-term loudness (3 s window, channel
+// powers summed, -70 LUFS absolute gate) without the K-weighting filter, as
+// the meters only report levels. It is close to the true loudness for
+// typical program material.
+//
+// Contents:
+// - Loudness State
+// - Meter Processing
+// - Program Tracking
+// - Loudness Helpers
+
+package obsclient
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/andreykaipov/goobs/api/requests/inputs"
+	"github.com/andreykaipov/goobs/api/typedefs"
+	"scenescheduler/backend/config"
+	"scenescheduler/backend/eventbus"
+)
+
+const (
+	// loudnessWindowBlocks is the short-term window in meter blocks (3 s).
+	loudnessWindowBlocks = 60
+
+	// loudnessAdjustInterval is the least time between volume corrections.
+	loudnessAdjustInterval = time.Second
+
+	// loudnessSettleTime lets transitions and crossfades finish before a new
+	// program is measured for correction.
+	loudnessSettleTime = 5 * time.Second
+
+	// loudnessToleranceLU is how far from the target a program may be before
+	// its volume is corrected.
+	loudnessToleranceLU = 0.5
+
+	// loudnessReportLU is how far from the target a program's own loudness
+	// may be before it is flagged to the operator.
+	loudnessReportLU = 3.0
+
+	// absoluteGateLUFS excludes silence from the measurements.
+	absoluteGateLUFS = -70.0
+)
+
+// ============================================================================
+// LOUDNESS STATE
+// ============================================================================
+
+// loudnessNormalizer follows the programs on air, one per track.
+type loudnessNormalizer struct {
+	mu       sync.Mutex
+	cfg      config.LoudnessConfig
+	programs map[string]*programLoudness // Keyed by track ID ("" = main track)
+	inputs   map[string]*programLoudness // Keyed by OBS input name
+}
+
+// programLoudness holds the measurements of one program while it is on air.
+type programLoudness struct {
+	track   string
+	program *eventbus.Program
+	baseDb  map[string]float64 // Configured volume of each input, by input name
+	gainDb  float64            // Correction applied on top of the configured volumes
+
+	window [loudnessWindowBlocks]float64 // Recent block powers, as measured on air
+	next   int
+	filled int
+
+	sourcePower  float64 // Sum of gated block powers before any volume change
+	sourceBlocks int
+	maxShortTerm float64 // Loudest short-term estimate on air, in LUFS
+
+	started    time.Time
+	lastAdjust time.Time
+}
+
+// volumeChange is a correction to apply to the inputs of a program.
+type volumeChange struct {
+	inputs map[string]float64 // Input name to volume in dB
+}
+
+// newLoudnessNormalizer creates a normalizer with the given configuration.
+func newLoudnessNormalizer(cfg config.LoudnessConfig) *loudnessNormalizer {
+	return &loudnessNormalizer{
+		cfg:      cfg,
+		programs: make(map[string]*programLoudness),
+		inputs:   make(map[string]*programLoudness),
+	}
+}
+
+// ============================================================================
+// METER PROCESSING
+// ============================================================================
+
+// handleVolumeMeters feeds one InputVolumeMeters event to the normalizer and
+// applies the resulting corrections in the background, so the OBS event
+// loop is never held up by requests.
+func (c *OBSClient) handleVolumeMeters(meters []*typedefs.InputVolumeMeter) {
+	if c.loudness == nil {
+		return
+	}
+	changes := c.loudness.process(meters, time.Now())
+	if len(changes) == 0 {
+		return
+	}
+
+	go func() {
+		client, _ := c.getActiveClientAndContext()
+		if client == nil {
+			return
+		}
+		for _, change := range changes {
+			for inputName, volumeDb := range change.inputs {
+				if _, err := client.Inputs.SetInputVolume(&inputs.SetInputVolumeParams{
+					InputName:     &inputName,
+					InputVolumeDb: &volumeDb,
+				}); err != nil {
+					c.logger.Debug("Could not apply loudness correction", "input", inputName, "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// process adds one block of meter levels to the programs on air and returns
+// the volume corrections that are due.
+func (n *loudnessNormalizer) process(meters []*typedefs.InputVolumeMeter, now time.Time) []volumeChange {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	// Channel powers of every input of a program are summed, like channels
+	// of a single program in R128
+	measured := make(map[*programLoudness]float64)
+	source := make(map[*programLoudness]float64)
+	for _, meter := range meters {
+		p := n.inputs[meter.Name]
+		if p == nil {
+			continue
+		}
+		var power float64
+		for _, channel := range meter.Levels {
+			power += channel[0] * channel[0] // Magnitude, post-fader
+		}
+		measured[p] += power
+		source[p] += power / dbToPower(p.baseDb[meter.Name]+p.gainDb)
+	}
+
+	var changes []volumeChange
+	for p, power := range measured {
+		p.window[p.next] = power
+		p.next = (p.next + 1) % loudnessWindowBlocks
+		p.filled = min(p.filled+1, loudnessWindowBlocks)
+
+		if powerToLUFS(source[p]) > absoluteGateLUFS {
+			p.sourcePower += source[p]
+			p.sourceBlocks++
+		}
+
+		shortTerm := p.shortTerm()
+		if p.filled == loudnessWindowBlocks && shortTerm > p.maxShortTerm {
+			p.maxShortTerm = shortTerm
+		}
+
+		if change, ok := n.correction(p, shortTerm, now); ok {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// correction decides whether the volume of a program should change, and
+// moves its gain by at most one step toward the target.
+func (n *loudnessNormalizer) correction(p *programLoudness, shortTerm float64, now time.Time) (volumeChange, bool) {
+	if p.filled < loudnessWindowBlocks ||
+		now.Sub(p.started) < loudnessSettleTime || now.Sub(p.lastAdjust) < loudnessAdjustInterval {
+		return volumeChange{}, false
+	}
+	if shortTerm <= absoluteGateLUFS {
+		return volumeChange{}, false // Silence is not corrected
+	}
+
+	diff := n.cfg.TargetLUFS - shortTerm
+	if math.Abs(diff) < loudnessToleranceLU {
+		return volumeChange{}, false
+	}
+	step := min(max(diff, -n.cfg.StepDb), n.cfg.StepDb)
+	gain := min(max(p.gainDb+step, -n.cfg.MaxCutDb), n.cfg.MaxBoostDb)
+	if gain == p.gainDb {
+		return volumeChange{}, false
+	}
+
+	// The blocks in the window were measured at the old gain; rescale them
+	// so the next decision sees the effect of this one
+	factor := dbToPower(gain - p.gainDb)
+	for i := range p.window {
+		p.window[i] *= factor
+	}
+	p.gainDb = gain
+	p.lastAdjust = now
+
+	change := volumeChange{inputs: make(map[string]float64, len(p.baseDb))}
+	for inputName, baseDb := range p.baseDb {
+		change.inputs[inputName] = baseDb + gain
+	}
+	return change, true
+}
+
+// ============================================================================
+// PROGRAM TRACKING
+// ============================================================================
+
+// trackLoudness starts measuring the program now on air on a track, and
+// reports the measurements of the program it replaced. inputVolumes maps the
+// program's input names to their configured volume in dB.
+func (c *OBSClient) trackLoudness(track string, program *eventbus.Program, inputVolumes map[string]float64) {
+	if c.loudness == nil {
+		return
+	}
+	if previous := c.loudness.track(track, program, inputVolumes, time.Now()); previous != nil {
+		c.reportLoudness(previous)
+	}
+}
+
+// track replaces the program followed on a track and returns the previous
+// one. A nil program stops following the track.
+func (n *loudnessNormalizer) track(track string, program *eventbus.Program, inputVolumes map[string]float64, now time.Time) *programLoudness {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	previous := n.programs[track]
+	if previous != nil {
+		for inputName := range previous.baseDb {
+			delete(n.inputs, inputName)
+		}
+		delete(n.programs, track)
+	}
+
+	if program != nil && len(inputVolumes) > 0 {
+		p := &programLoudness{
+			track:        track,
+			program:      program,
+			baseDb:       inputVolumes,
+			maxShortTerm: math.Inf(-1),
+			started:      now,
+		}
+		n.programs[track] = p
+		for inputName := range inputVolumes {
+			n.inputs[inputName] = p
+		}
+	}
+	return previous
+}
+
+// reset stops following every program without reporting, e.g. after the
+// connection to OBS was lost and the measurements are incomplete.
+func (n *loudnessNormalizer) reset() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.programs = make(map[string]*programLoudness)
+	n.inputs = make(map[string]*programLoudness)
+}
+
+// reportLoudness logs the measurements of a program that left the air and
+// records them in the as-run log. Programs that were silent throughout are
+// not reported.
+func (c *OBSClient) reportLoudness(p *programLoudness) {
+	if p.sourceBlocks == 0 {
+		return
+	}
+	loudness := powerToLUFS(p.sourcePower / float64(p.sourceBlocks))
+
+	fields := []interface{}{
+		"program", getProgramTitle(p.program),
+		"uri", p.program.URI,
+		"loudnessLufs", round1(loudness),
+		"gainDb", round1(p.gainDb),
+	}
+	if p.track != "" {
+		fields = append(fields, "track", p.track)
+	}
+	if !math.IsInf(p.maxShortTerm, -1) {
+		fields = append(fields, "maxShortTermLufs", round1(p.maxShortTerm))
+	}
+	if math.Abs(loudness-c.loudness.cfg.TargetLUFS) > loudnessReportLU {
+		c.logger.WarnGui("Program loudness far from target", fields...)
+	} else {
+		c.logger.Info("Program loudness measured", fields...)
+	}
+
+	entry := asRunEntry{
+		Timestamp:    time.Now(),
+		Event:        asRunEventLoudness,
+		Track:        p.track,
+		ProgramID:    p.program.ID,
+		ParentID:     p.program.ParentID,
+		Title:        p.program.Title,
+		Kind:         p.program.Kind,
+		URI:          p.program.URI,
+		LoudnessLUFS: round1(loudness),
+		GainDb:       round1(p.gainDb),
+	}
+	if err := c.asRun.append(entry); err != nil {
+		c.logger.Warn("Failed to record as-run entry", "error", err)
+	}
+}
+
+// ============================================================================
+// LOUDNESS HELPERS
+// ============================================================================
+
+// shortTerm returns the loudness over the filled part of the window.
+func (p *programLoudness) shortTerm() float64 {
+	if p.filled == 0 {
+		return math.Inf(-1)
+	}
+	var sum float64
+	for i := 0; i < p.filled; i++ {
+		sum += p.window[i]
+	}
+	return powerToLUFS(sum / float64(p.filled))
+}
+
+// powerToLUFS converts a mean square power to a loudness in LUFS.
+func powerToLUFS(power float64) float64 {
+	if power <= 0 {
+		return math.Inf(-1)
+	}
+	return -0.691 + 10*math.Log10(power)
+}
+
+// dbToPower converts a gain in dB to a power factor.
+func dbToPower(db float64) float64 {
+	return math.Pow(10, db/10)
+}
+
+// round1 rounds to one decimal, which is as precise as the estimate is.
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
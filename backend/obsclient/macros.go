@@ -0,0 +1,227 @@
+// backend/obsclient/macros.go
+//
+// This file executes scheduled macros: ordered obs-websocket requests such as
+// triggering a hotkey, switching the scene collection or profile, toggling a
+// filter or calling a plugin's vendor request. Requests go through the typed
+// goobs client, so each supported request type is bound once in a registry
+// that decodes the step's fields into the matching goobs params.
+//
+// Contents:
+// - Request Registry
+// - Macro Execution
+
+package obsclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andreykaipov/goobs"
+	obsconfig "github.com/andreykaipov/goobs/api/requests/config"
+	"github.com/andreykaipov/goobs/api/requests/filters"
+	"github.com/andreykaipov/goobs/api/requests/general"
+	"github.com/andreykaipov/goobs/api/requests/inputs"
+	"github.com/andreykaipov/goobs/api/requests/mediainputs"
+	"github.com/andreykaipov/goobs/api/requests/outputs"
+	"github.com/andreykaipov/goobs/api/requests/record"
+	"github.com/andreykaipov/goobs/api/requests/sceneitems"
+	"github.com/andreykaipov/goobs/api/requests/scenes"
+	"github.com/andreykaipov/goobs/api/requests/stream"
+	"github.com/andreykaipov/goobs/api/requests/transitions"
+	"github.com/andreykaipov/goobs/api/requests/ui"
+	"scenescheduler/backend/eventbus"
+)
+
+// maxMacroStepDelay bounds the wait before a single macro step, so a typo in
+// delayMs cannot hold the action queue for hours.
+const maxMacroStepDelay = 10 * time.Minute
+
+// ============================================================================
+// REQUEST REGISTRY
+// ============================================================================
+
+// macroRequest sends one request with the step's fields and returns the
+// decoded response.
+type macroRequest func(client *goobs.Client, data map[string]interface{}) (interface{}, error)
+
+// bind adapts a goobs request method, taken as a method expression, to a
+// macroRequest. The category accessor selects the goobs sub-client.
+func bind[C, P, R any](category func(*goobs.Client) *C, call func(*C, *P) (R, error)) macroRequest {
+	return func(client *goobs.Client, data map[string]interface{}) (interface{}, error) {
+		params, err := decodeParams[P](data)
+		if err != nil {
+			return nil, err
+		}
+		return call(category(client), params)
+	}
+}
+
+// bindOptional is bind for requests whose params are optional in goobs.
+func bindOptional[C, P, R any](category func(*goobs.Client) *C, call func(*C, ...*P) (R, error)) macroRequest {
+	return bind(category, func(c *C, params *P) (R, error) { return call(c, params) })
+}
+
+// decodeParams converts the step's fields into goobs params. goobs params
+// carry the obs-websocket field names as JSON tags.
+func decodeParams[P any](data map[string]interface{}) (*P, error) {
+	params := new(P)
+	if len(data) == 0 {
+		return params, nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid requestData: %w", err)
+	}
+	if err := json.Unmarshal(raw, params); err != nil {
+		return nil, fmt.Errorf("invalid requestData: %w", err)
+	}
+	return params, nil
+}
+
+// Category accessors used by the registry.
+func configClient(c *goobs.Client) *obsconfig.Client        { return c.Config }
+func filtersClient(c *goobs.Client) *filters.Client         { return c.Filters }
+func generalClient(c *goobs.Client) *general.Client         { return c.General }
+func inputsClient(c *goobs.Client) *inputs.Client           { return c.Inputs }
+func mediaInputsClient(c *goobs.Client) *mediainputs.Client { return c.MediaInputs }
+func outputsClient(c *goobs.Client) *outputs.Client         { return c.Outputs }
+func recordClient(c *goobs.Client) *record.Client           { return c.Record }
+func sceneItemsClient(c *goobs.Client) *sceneitems.Client   { return c.SceneItems }
+func scenesClient(c *goobs.Client) *scenes.Client           { return c.Scenes }
+func streamClient(c *goobs.Client) *stream.Client           { return c.Stream }
+func transitionsClient(c *goobs.Client) *transitions.Client { return c.Transitions }
+func uiClient(c *goobs.Client) *ui.Client                   { return c.Ui }
+
+// macroRequests lists the obs-websocket requests a macro may send, keyed by
+// their obs-websocket name. Requests that only read state are left out, as
+// their results would go nowhere.
+var macroRequests = map[string]macroRequest{
+	// General
+	"TriggerHotkeyByName":        bind(generalClient, (*general.Client).TriggerHotkeyByName),
+	"TriggerHotkeyByKeySequence": bindOptional(generalClient, (*general.Client).TriggerHotkeyByKeySequence),
+	"CallVendorRequest":          bind(generalClient, (*general.Client).CallVendorRequest),
+	"BroadcastCustomEvent":       bind(generalClient, (*general.Client).BroadcastCustomEvent),
+
+	// Config
+	"SetCurrentSceneCollection": bind(configClient, (*obsconfig.Client).SetCurrentSceneCollection),
+	"SetCurrentProfile":         bind(configClient, (*obsconfig.Client).SetCurrentProfile),
+	"SetProfileParameter":       bind(configClient, (*obsconfig.Client).SetProfileParameter),
+
+	// Scenes and scene items
+	"SetCurrentProgramScene": bindOptional(scenesClient, (*scenes.Client).SetCurrentProgramScene),
+	"SetCurrentPreviewScene": bindOptional(scenesClient, (*scenes.Client).SetCurrentPreviewScene),
+	"SetSceneItemEnabled":    bind(sceneItemsClient, (*sceneitems.Client).SetSceneItemEnabled),
+
+	// Inputs and filters
+	"SetInputSettings":        bind(inputsClient, (*inputs.Client).SetInputSettings),
+	"SetInputMute":            bind(inputsClient, (*inputs.Client).SetInputMute),
+	"ToggleInputMute":         bindOptional(inputsClient, (*inputs.Client).ToggleInputMute),
+	"SetInputVolume":          bindOptional(inputsClient, (*inputs.Client).SetInputVolume),
+	"TriggerMediaInputAction": bind(mediaInputsClient, (*mediainputs.Client).TriggerMediaInputAction),
+	"SetSourceFilterEnabled":  bind(filtersClient, (*filters.Client).SetSourceFilterEnabled),
+	"SetSourceFilterSettings": bind(filtersClient, (*filters.Client).SetSourceFilterSettings),
+
+	// Transitions and studio mode
+	"SetCurrentSceneTransition":         bind(transitionsClient, (*transitions.Client).SetCurrentSceneTransition),
+	"SetCurrentSceneTransitionDuration": bind(transitionsClient, (*transitions.Client).SetCurrentSceneTransitionDuration),
+	"TriggerStudioModeTransition":       bindOptional(transitionsClient, (*transitions.Client).TriggerStudioModeTransition),
+	"SetStudioModeEnabled":              bind(uiClient, (*ui.Client).SetStudioModeEnabled),
+
+	// Outputs
+	"StartStream":         bindOptional(streamClient, (*stream.Client).StartStream),
+	"StopStream":          bindOptional(streamClient, (*stream.Client).StopStream),
+	"StartRecord":         bindOptional(recordClient, (*record.Client).StartRecord),
+	"StopRecord":          bindOptional(recordClient, (*record.Client).StopRecord),
+	"PauseRecord":         bindOptional(recordClient, (*record.Client).PauseRecord),
+	"ResumeRecord":        bindOptional(recordClient, (*record.Client).ResumeRecord),
+	"SplitRecordFile":     bindOptional(recordClient, (*record.Client).SplitRecordFile),
+	"CreateRecordChapter": bindOptional(recordClient, (*record.Client).CreateRecordChapter),
+	"StartReplayBuffer":   bindOptional(outputsClient, (*outputs.Client).StartReplayBuffer),
+	"StopReplayBuffer":    bindOptional(outputsClient, (*outputs.Client).StopReplayBuffer),
+	"SaveReplayBuffer":    bindOptional(outputsClient, (*outputs.Client).SaveReplayBuffer),
+	"StartVirtualCam":     bindOptional(outputsClient, (*outputs.Client).StartVirtualCam),
+	"StopVirtualCam":      bindOptional(outputsClient, (*outputs.Client).StopVirtualCam),
+}
+
+// supportedMacroRequests returns the registry keys, sorted, for error messages.
+func supportedMacroRequests() string {
+	names := make([]string, 0, len(macroRequests))
+	for name := range macroRequests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// ============================================================================
+// MACRO EXECUTION
+// ============================================================================
+
+// runMacro sends the steps of a macro in order and publishes the outcome of
+// each step. A failed step stops the macro unless its policy is "continue";
+// the remaining steps are then reported as skipped. The returned error
+// summarizes the failures for the as-run log.
+func (c *OBSClient) runMacro(client *goobs.Client, event eventbus.OBSActionRequested) error {
+	results := make([]eventbus.MacroStepResult, len(event.Steps))
+	var failures []string
+	stopped := false
+
+	for i, step := range event.Steps {
+		results[i] = eventbus.MacroStepResult{RequestType: step.RequestType}
+		if stopped {
+			results[i].Status = eventbus.MacroStepSkipped
+			continue
+		}
+
+		if step.DelayMs > 0 {
+			time.Sleep(min(time.Duration(step.DelayMs)*time.Millisecond, maxMacroStepDelay))
+		}
+
+		started := time.Now()
+		response, err := c.sendMacroStep(client, step)
+		results[i].DurationMs = time.Since(started).Milliseconds()
+
+		if err != nil {
+			results[i].Status = eventbus.MacroStepFailed
+			results[i].Error = err.Error()
+			failures = append(failures, fmt.Sprintf("step %d (%s): %v", i, step.RequestType, err))
+			c.logger.Warn("Macro step failed",
+				"event", event.SourceID,
+				"step", i,
+				"request", step.RequestType,
+				"error", err)
+			stopped = step.OnError != eventbus.MacroOnErrorContinue
+			continue
+		}
+
+		results[i].Status = eventbus.MacroStepOK
+		if data, err := json.Marshal(response); err == nil && string(data) != "{}" {
+			results[i].ResponseData = data
+		}
+	}
+
+	eventbus.Publish(c.bus, eventbus.OBSMacroExecuted{
+		EventID:   event.SourceID,
+		Title:     event.Title,
+		Success:   len(failures) == 0,
+		Steps:     results,
+		Timestamp: time.Now(),
+	})
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%d of %d macro steps failed: %s", len(failures), len(event.Steps), strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// sendMacroStep sends a single step through the registry.
+func (c *OBSClient) sendMacroStep(client *goobs.Client, step eventbus.MacroStep) (interface{}, error) {
+	request, ok := macroRequests[step.RequestType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported request type %q (supported: %s)", step.RequestType, supportedMacroRequests())
+	}
+	return request(client, step.RequestData)
+}
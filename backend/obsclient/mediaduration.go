@@ -0,0 +1,157 @@
+// backend/obsclient/mediaduration.go
+//
+// This file implements media duration discovery for VOD programs. OBS is
+// asked first, by staging the input hidden in the aux scene; if that is not
+// possible, the container headers of local files are parsed directly.
+//
+// Contents:
+// - Request and Response Types
+// - Duration Discovery
+// - Client Communication
+
+package obsclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"scenescheduler/backend/eventbus"
+	"scenescheduler/backend/obsclient/internal/mediaprobe"
+)
+
+// probeTimeout bounds how long OBS is given to open the media and report
+// its duration before falling back to the container parser.
+const probeTimeout = 5 * time.Second
+
+// Methods reported in a mediaDuration response.
+const (
+	probeMethodOBS       = "obs"
+	probeMethodContainer = "container"
+)
+
+// ============================================================================
+// REQUEST AND RESPONSE TYPES
+// ============================================================================
+
+// mediaDurationRequest is the payload of a probeMediaDuration request.
+type mediaDurationRequest struct {
+	RequestID     string                 `json:"requestId"`
+	ProgramID     string                 `json:"programId"`
+	InputKind     string                 `json:"inputKind"`
+	URI           string                 `json:"uri"`
+	InputSettings map[string]interface{} `json:"inputSettings"`
+	Start         time.Time              `json:"start"` // Optional, used to propose an end time
+}
+
+// mediaDurationResponse is the payload of a mediaDuration response.
+type mediaDurationResponse struct {
+	RequestID   string     `json:"requestId,omitempty"`
+	ProgramID   string     `json:"programId,omitempty"`
+	URI         string     `json:"uri"`
+	DurationMs  int64      `json:"durationMs"`
+	Method      string     `json:"method"`
+	ProposedEnd *time.Time `json:"proposedEnd,omitempty"`
+}
+
+// ============================================================================
+// DURATION DISCOVERY
+// ============================================================================
+
+// probeMediaDuration parses the request and runs the discovery in the
+// background so the event bus is not blocked while OBS opens the media.
+func (c *OBSClient) probeMediaDuration(clientID string, payload json.RawMessage) {
+	var req mediaDurationRequest
+	if err := json.Unmarshal(payload, &req); err != nil || req.URI == "" {
+		c.logger.Warn("Invalid media duration request", "clientID", clientID, "error", err)
+		c.sendMediaDurationError(clientID, req, "A media 'uri' is required")
+		return
+	}
+	if req.InputKind == "" {
+		req.InputKind = "ffmpeg_source"
+	}
+
+	go func() {
+		duration, method, err := c.discoverDuration(req)
+		if err != nil {
+			c.logger.Warn("Could not determine media duration", "uri", req.URI, "error", err)
+			c.sendMediaDurationError(clientID, req, err.Error())
+			return
+		}
+
+		c.logger.Info("Determined media duration",
+			"uri", req.URI,
+			"duration", duration.Round(time.Millisecond),
+			"method", method)
+
+		resp := mediaDurationResponse{
+			RequestID:  req.RequestID,
+			ProgramID:  req.ProgramID,
+			URI:        req.URI,
+			DurationMs: duration.Milliseconds(),
+			Method:     method,
+		}
+		if !req.Start.IsZero() {
+			end := req.Start.Add(duration)
+			resp.ProposedEnd = &end
+		}
+		eventbus.Publish(c.bus, eventbus.WebSocketSendMessageToClient{
+			ClientID:    clientID,
+			MessageType: "mediaDuration",
+			Payload:     resp,
+		})
+	}()
+}
+
+// discoverDuration asks OBS first and falls back to the container parser for
+// local files. The OBS error is kept when both fail, since it is usually the
+// more informative one.
+func (c *OBSClient) discoverDuration(req mediaDurationRequest) (time.Duration, string, error) {
+	var obsErr error
+	if client, _ := c.getActiveClientAndContext(); client != nil {
+		program := &eventbus.Program{
+			ID:            req.ProgramID,
+			Title:         "Media duration probe",
+			InputKind:     req.InputKind,
+			URI:           req.URI,
+			InputSettings: req.InputSettings,
+		}
+		duration, err := c.switcher.ProbeMediaDuration(client, program, probeTimeout)
+		if err == nil {
+			return duration, probeMethodOBS, nil
+		}
+		obsErr = err
+		c.logger.Debug("OBS media probe failed, trying container parser", "uri", req.URI, "error", err)
+	} else {
+		obsErr = ErrNotConnected
+	}
+
+	if parsed, err := url.Parse(req.URI); err == nil && parsed.Scheme != "" && parsed.Host != "" {
+		return 0, "", fmt.Errorf("OBS probe failed and remote media cannot be parsed locally: %w", obsErr)
+	}
+
+	duration, err := mediaprobe.Duration(req.URI)
+	if err != nil {
+		return 0, "", fmt.Errorf("OBS probe failed (%v) and container parsing failed: %w", obsErr, err)
+	}
+	return duration, probeMethodContainer, nil
+}
+
+// ============================================================================
+// CLIENT COMMUNICATION
+// ============================================================================
+
+// sendMediaDurationError reports a failed discovery to the requesting client.
+func (c *OBSClient) sendMediaDurationError(clientID string, req mediaDurationRequest, message string) {
+	eventbus.Publish(c.bus, eventbus.WebSocketSendMessageToClient{
+		ClientID:    clientID,
+		MessageType: "mediaDurationError",
+		Payload: map[string]interface{}{
+			"requestId": req.RequestID,
+			"programId": req.ProgramID,
+			"uri":       req.URI,
+			"message":   message,
+		},
+	})
+}
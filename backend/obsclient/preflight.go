@@ -0,0 +1,418 @@
+// backend/obsclient/preflight.go
+//
+// This file checks that upcoming programs can air, a few minutes before they
+// start: local files must exist and be readable, URLs must resolve and
+// respond, and OBS must support the input kinds and have the scenes. If
+// configured, the inputs are also created hidden in the aux scene as a
+// trial. Problems are reported on the bus with time to react, and checked
+// again until the program starts. If configured, a program that still fails
+// at start time airs its fallback source instead, and is checked again
+// during its slot so it can take over once the problems are fixed.
+//
+// Contents:
+// - Pre-flight Scheduling
+// - Pre-flight Checks
+// - Fallback Replacement
+
+package obsclient
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andreykaipov/goobs"
+	"scenescheduler/backend/config"
+	"scenescheduler/backend/eventbus"
+)
+
+const (
+	// preflightRecheckInterval is how often a failing program is checked
+	// again before it starts.
+	preflightRecheckInterval = 30 * time.Second
+
+	// preflightKindsTTL is how long the input kinds supported by OBS are
+	// cached.
+	preflightKindsTTL = 5 * time.Minute
+
+	// preflightTimeout bounds each network check and trial input.
+	preflightTimeout = 5 * time.Second
+
+	// preflightIDSuffix marks a fallback that airs in place of a program.
+	preflightIDSuffix = "!preflight"
+)
+
+// preflightHTTPClient reaches the URLs of upcoming programs.
+var preflightHTTPClient = &http.Client{Timeout: preflightTimeout}
+
+// defaultStreamPorts are the ports of stream URLs that are checked by
+// opening a connection; other schemes, such as SRT over UDP, are only
+// resolved.
+var defaultStreamPorts = map[string]string{
+	"rtmp":  "1935",
+	"rtmps": "443",
+	"rtsp":  "554",
+	"tcp":   "",
+}
+
+// preflightCheck is the state of the checks of one program occurrence.
+type preflightCheck struct {
+	program  *eventbus.Program
+	lastRun  time.Time // Zero = never checked
+	running  bool
+	problems []string // Problems found by the last check
+	reported bool     // Problems were published and not cleared since
+	replaced bool     // The fallback was aired instead
+}
+
+// preflightChecker holds the checks of upcoming programs. It is fed by the
+// scheduler events and runs the checks in the background.
+type preflightChecker struct {
+	mu      sync.Mutex
+	cfg     config.PreflightConfig
+	checks  map[string]*preflightCheck // By occurrence, see occurrenceKey
+	kinds   []string                   // Input kinds supported by OBS
+	kindsAt time.Time                  // When kinds was fetched
+}
+
+// newPreflightChecker creates a checker with the given configuration.
+func newPreflightChecker(cfg config.PreflightConfig) *preflightChecker {
+	return &preflightChecker{cfg: cfg, checks: make(map[string]*preflightCheck)}
+}
+
+// occurrenceKey identifies one occurrence of a program.
+func occurrenceKey(p *eventbus.Program) string {
+	return p.ID + "@" + p.Start.Format(time.RFC3339)
+}
+
+// ============================================================================
+// PRE-FLIGHT SCHEDULING
+// ============================================================================
+
+// schedulePreflight starts the checks of an upcoming program once it is
+// within the lead time, and again while it fails. A program whose fallback
+// airs in its place keeps being checked until its occurrence ends.
+// Occurrences that have ended are forgotten.
+func (c *OBSClient) schedulePreflight(program *eventbus.Program) {
+	p := c.preflight
+	if p == nil || program == nil || program.Start.IsZero() {
+		return
+	}
+	now := time.Now()
+	lead := time.Duration(p.cfg.LeadMinutes) * time.Minute
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, check := range p.checks {
+		end := check.program.End
+		if end.IsZero() {
+			end = check.program.Start
+		}
+		if end.Before(now) {
+			delete(p.checks, key)
+		}
+	}
+
+	key := occurrenceKey(program)
+	check, ok := p.checks[key]
+	started := !now.Before(program.Start)
+	if now.Before(program.Start.Add(-lead)) || (started && !(ok && check.replaced)) {
+		return
+	}
+	if !ok {
+		check = &preflightCheck{}
+		p.checks[key] = check
+	}
+	check.program = program // Latest version from the scheduler
+	if check.running {
+		return
+	}
+	if !check.lastRun.IsZero() && (len(check.problems) == 0 || now.Sub(check.lastRun) < preflightRecheckInterval) {
+		return
+	}
+	check.running = true
+	go c.runPreflight(check, program)
+}
+
+// runPreflight checks a program and publishes the result if it changed.
+func (c *OBSClient) runPreflight(check *preflightCheck, program *eventbus.Program) {
+	p := c.preflight
+	client, _ := c.getActiveClientAndContext()
+	if client == nil {
+		p.mu.Lock()
+		check.running = false // Checked again once OBS is back
+		p.mu.Unlock()
+		return
+	}
+
+	c.logger.Debug("Running pre-flight checks", "program", getProgramTitle(program), "start", program.Start)
+	problems := c.preflightProblems(client, program)
+
+	p.mu.Lock()
+	check.running = false
+	check.lastRun = time.Now()
+	changed := !slices.Equal(problems, check.problems)
+	check.problems = problems
+	publish := changed && (len(problems) > 0 || check.reported)
+	check.reported = len(problems) > 0
+	p.mu.Unlock()
+
+	if len(problems) > 0 {
+		c.logger.Warn("Upcoming program failed its pre-flight checks",
+			"program", getProgramTitle(program),
+			"start", program.Start,
+			"problems", problems)
+	} else if publish {
+		c.logger.Info("Upcoming program passed its pre-flight checks", "program", getProgramTitle(program))
+	}
+	if !publish {
+		return
+	}
+
+	eventbus.Publish(c.bus, eventbus.OBSPreflightResult{
+		ProgramID:   program.ID,
+		Title:       getProgramTitle(program),
+		Start:       program.Start,
+		Passed:      len(problems) == 0,
+		Problems:    problems,
+		WillReplace: len(problems) > 0 && p.cfg.ReplaceOnFailure && program.Fallback != nil,
+		Timestamp:   time.Now(),
+	})
+}
+
+// ============================================================================
+// PRE-FLIGHT CHECKS
+// ============================================================================
+
+// preflightSource is one source of a program to check.
+type preflightSource struct {
+	label     string
+	sceneName string
+	inputKind string
+	uri       string
+}
+
+// preflightSources lists the main source and layers of a program. Layers do
+// not apply to a scene that replaces the program scene.
+func preflightSources(p *eventbus.Program) []preflightSource {
+	sources := []preflightSource{{label: "source", sceneName: p.SceneName, inputKind: p.InputKind, uri: p.URI}}
+	if p.SceneName != "" && p.SceneMode == eventbus.SceneModeProgram {
+		return sources
+	}
+	for _, l := range p.Layers {
+		sources = append(sources, preflightSource{
+			label:     fmt.Sprintf("layer %s%s", l.SourceName, l.SceneName),
+			sceneName: l.SceneName,
+			inputKind: l.InputKind,
+			uri:       l.URI,
+		})
+	}
+	return sources
+}
+
+// preflightProblems runs all checks of a program and returns the problems
+// found, if any.
+func (c *OBSClient) preflightProblems(client *goobs.Client, program *eventbus.Program) []string {
+	var problems []string
+	var scenes []string // Fetched once, if a source needs it
+
+	for _, src := range preflightSources(program) {
+		if src.sceneName != "" {
+			if scenes == nil {
+				resp, err := client.Scenes.GetSceneList()
+				if err != nil {
+					problems = append(problems, fmt.Sprintf("could not list OBS scenes: %v", err))
+					continue
+				}
+				scenes = make([]string, 0, len(resp.Scenes))
+				for _, scene := range resp.Scenes {
+					scenes = append(scenes, scene.SceneName)
+				}
+			}
+			if !slices.Contains(scenes, src.sceneName) {
+				problems = append(problems, fmt.Sprintf("%s: OBS scene %q does not exist", src.label, src.sceneName))
+			}
+			continue
+		}
+
+		if err := c.checkInputKind(client, src.inputKind); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", src.label, err))
+		}
+		if err := checkSourceURI(src.uri); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", src.label, err))
+		}
+	}
+
+	// A trial only makes sense once the simple checks pass
+	if len(problems) == 0 && c.preflight.cfg.TrialInput {
+		if err := c.switcher.TrialInputs(client, program, preflightTimeout); err != nil {
+			problems = append(problems, fmt.Sprintf("trial input failed: %v", err))
+		}
+	}
+	return problems
+}
+
+// checkInputKind checks that OBS supports an input kind, using a cached
+// list of the supported kinds.
+func (c *OBSClient) checkInputKind(client *goobs.Client, kind string) error {
+	p := c.preflight
+
+	p.mu.Lock()
+	kinds, fresh := p.kinds, time.Since(p.kindsAt) < preflightKindsTTL
+	p.mu.Unlock()
+
+	if !fresh {
+		resp, err := client.Inputs.GetInputKindList()
+		if err != nil {
+			return fmt.Errorf("could not fetch supported input kinds: %w", err)
+		}
+		kinds = resp.InputKinds
+
+		p.mu.Lock()
+		p.kinds, p.kindsAt = kinds, time.Now()
+		p.mu.Unlock()
+	}
+
+	if !slices.Contains(kinds, kind) {
+		return fmt.Errorf("input kind %q is not supported by OBS", kind)
+	}
+	return nil
+}
+
+// checkSourceURI checks that a local file exists and is readable, or that a
+// URL resolves and responds. Sources without a URI pass.
+func checkSourceURI(uri string) error {
+	if uri == "" {
+		return nil
+	}
+	if !isRemoteURI(uri) {
+		return checkLocalFile(strings.TrimPrefix(uri, "file://"))
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", uri, err)
+	}
+	switch parsed.Scheme {
+	case "http", "https":
+		return checkHTTP(uri)
+	default:
+		return checkStreamHost(parsed)
+	}
+}
+
+// checkLocalFile checks that a file exists and can be opened for reading.
+func checkLocalFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("file %q does not exist", path)
+		}
+		return fmt.Errorf("file %q is not readable: %w", path, err)
+	}
+	file.Close()
+	return nil
+}
+
+// checkHTTP checks that a URL responds without an error status. Servers
+// that do not allow HEAD are asked with GET.
+func checkHTTP(uri string) error {
+	resp, err := preflightHTTPClient.Head(uri)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		resp.Body.Close()
+		resp, err = preflightHTTPClient.Get(uri)
+	}
+	if err != nil {
+		return fmt.Errorf("%s does not respond: %w", uri, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%s returned %s", uri, resp.Status)
+	}
+	return nil
+}
+
+// checkStreamHost checks that the host of a stream URL resolves and, for
+// TCP based schemes, accepts connections.
+func checkStreamHost(parsed *url.URL) error {
+	ctx, cancel := context.WithTimeout(context.Background(), preflightTimeout)
+	defer cancel()
+
+	host := parsed.Hostname()
+	if _, err := net.DefaultResolver.LookupHost(ctx, host); err != nil {
+		return fmt.Errorf("host %q does not resolve: %w", host, err)
+	}
+
+	defaultPort, tcp := defaultStreamPorts[parsed.Scheme]
+	port := parsed.Port()
+	if port == "" {
+		port = defaultPort
+	}
+	if !tcp || port == "" {
+		return nil
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return fmt.Errorf("%s does not respond: %w", parsed.Redacted(), err)
+	}
+	conn.Close()
+	return nil
+}
+
+// ============================================================================
+// FALLBACK REPLACEMENT
+// ============================================================================
+
+// preflightTarget returns the program to air for a target program. If
+// configured, a program whose last pre-flight check failed airs its
+// fallback source instead. The replacement keeps the program's title but
+// has an ID of its own, so the program is switched back on air once a
+// recheck passes.
+func (c *OBSClient) preflightTarget(program *eventbus.Program) *eventbus.Program {
+	p := c.preflight
+	if p == nil || !p.cfg.ReplaceOnFailure || program == nil || program.Fallback == nil {
+		return program
+	}
+
+	p.mu.Lock()
+	check, ok := p.checks[occurrenceKey(program)]
+	failed := ok && len(check.problems) > 0
+	firstTime := failed && !check.replaced
+	recovered := ok && !failed && check.replaced
+	if ok {
+		check.replaced = failed
+	}
+	p.mu.Unlock()
+
+	if recovered {
+		c.logger.Info("Program passed its pre-flight checks, airing it instead of its fallback",
+			"program", getProgramTitle(program))
+	}
+	if !failed {
+		return program
+	}
+	if firstTime {
+		c.logger.Warn("Airing fallback instead of program that failed its pre-flight checks",
+			"program", getProgramTitle(program),
+			"fallback", getProgramTitle(program.Fallback))
+	}
+
+	replacement := *program.Fallback
+	replacement.ID = program.ID + preflightIDSuffix
+	replacement.Title = program.Title
+	replacement.Kind = program.Kind
+	replacement.ParentID = program.ParentID
+	replacement.Start = program.Start
+	replacement.End = program.End
+	replacement.Fallback = nil
+	return &replacement
+}
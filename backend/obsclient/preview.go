@@ -0,0 +1,170 @@
+// backend/obsclient/preview.go
+//
+// This file renders source previews through OBS itself, for any input kind
+// OBS supports. The requested input is created in the preview scene, which
+// is never put on air, and screenshots of it are sent to the requesting web
+// client a few times per second. The input is removed when the web server
+// stops the preview, when it stops producing frames, or when the session
+// ends.
+//
+// Contents:
+// - Preview Lifecycle
+// - Frame Streaming
+
+package obsclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andreykaipov/goobs"
+	"scenescheduler/backend/eventbus"
+)
+
+const (
+	// previewFrameInterval is the time between preview frames (4 fps).
+	previewFrameInterval = 250 * time.Millisecond
+
+	// previewFrameWidth is the width of preview frames in pixels.
+	previewFrameWidth = 480
+
+	// previewMaxMissedFrames is how many frames in a row may fail before the
+	// preview is given up, e.g. because the input was removed in OBS.
+	previewMaxMissedFrames = 20
+)
+
+// sourcePreview is a preview running in OBS.
+type sourcePreview struct {
+	id       uint64
+	clientID string
+	program  *eventbus.Program // The preview input, staged like a program
+	cancel   context.CancelFunc
+}
+
+// previewFrame is the payload of a previewFrame message.
+type previewFrame struct {
+	PreviewID uint64 `json:"previewId"`
+	Image     string `json:"image"` // Data URI, usable as an image source
+}
+
+// ============================================================================
+// PREVIEW LIFECYCLE
+// ============================================================================
+
+// startSourcePreview creates the preview input in OBS and starts sending
+// frames. It runs in the background so the event bus is not blocked while
+// OBS creates the input.
+func (c *OBSClient) startSourcePreview(event eventbus.SourcePreviewRequested) {
+	client, sessionCtx := c.getActiveClientAndContext()
+	if client == nil {
+		c.publishPreviewFailed(event.PreviewID, "OBS is not connected")
+		return
+	}
+
+	name := fmt.Sprintf("preview-%d", event.PreviewID)
+	program := &eventbus.Program{
+		ID:            name,
+		Title:         "Source preview",
+		SourceName:    name,
+		InputKind:     event.InputKind,
+		URI:           event.URI,
+		InputSettings: event.InputSettings,
+	}
+	ctx, cancel := context.WithCancel(sessionCtx)
+	preview := &sourcePreview{id: event.PreviewID, clientID: event.ClientID, program: program, cancel: cancel}
+
+	c.previewsMu.Lock()
+	c.previews[preview.id] = preview
+	c.previewsMu.Unlock()
+
+	go func() {
+		defer c.endSourcePreview(client, preview)
+
+		inputName, err := c.switcher.CreatePreviewInput(client, program)
+		if err != nil {
+			c.logger.Warn("Could not create source preview in OBS", "uri", event.URI, "error", err)
+			c.publishPreviewFailed(preview.id, err.Error())
+			return
+		}
+
+		c.logger.Info("Source preview started in OBS", "previewID", preview.id, "input", inputName, "uri", event.URI)
+		eventbus.Publish(c.bus, eventbus.OBSSourcePreviewReady{PreviewID: preview.id, Timestamp: time.Now()})
+		c.streamPreviewFrames(ctx, sessionCtx, client, preview, inputName)
+	}()
+}
+
+// stopSourcePreview stops a preview; its input is removed in the background.
+func (c *OBSClient) stopSourcePreview(previewID uint64) {
+	c.previewsMu.Lock()
+	preview := c.previews[previewID]
+	c.previewsMu.Unlock()
+
+	if preview != nil {
+		preview.cancel()
+	}
+}
+
+// endSourcePreview removes the input of a preview that ended. Removal is
+// best-effort: inputs left by a lost connection are replaced on next use.
+func (c *OBSClient) endSourcePreview(client *goobs.Client, preview *sourcePreview) {
+	preview.cancel()
+
+	c.previewsMu.Lock()
+	delete(c.previews, preview.id)
+	c.previewsMu.Unlock()
+
+	// CLEANUP: Best-effort, OBS may be gone
+	_ = c.switcher.RemovePreviewInput(client, preview.program)
+	c.logger.Debug("Source preview ended", "previewID", preview.id)
+}
+
+// publishPreviewFailed reports a preview that could not run, so the web
+// server can tell the client and forget the preview.
+func (c *OBSClient) publishPreviewFailed(previewID uint64, errMsg string) {
+	eventbus.Publish(c.bus, eventbus.OBSSourcePreviewFailed{
+		PreviewID: previewID,
+		Error:     errMsg,
+		Timestamp: time.Now(),
+	})
+}
+
+// ============================================================================
+// FRAME STREAMING
+// ============================================================================
+
+// streamPreviewFrames sends screenshots of the preview input to the client
+// until the preview is stopped or the session ends.
+func (c *OBSClient) streamPreviewFrames(ctx, sessionCtx context.Context, client *goobs.Client, preview *sourcePreview, inputName string) {
+	ticker := time.NewTicker(previewFrameInterval)
+	defer ticker.Stop()
+
+	missed := 0
+	for {
+		select {
+		case <-ctx.Done():
+			if sessionCtx.Err() != nil {
+				c.publishPreviewFailed(preview.id, "OBS disconnected")
+			}
+			return
+		case <-ticker.C:
+			image, err := screenshotDataURI(client, inputName, "jpg", previewFrameWidth)
+			if err != nil {
+				// Inputs such as media still opening have no frame yet
+				if missed++; missed >= previewMaxMissedFrames {
+					c.logger.Warn("Source preview stopped producing frames", "previewID", preview.id, "error", err)
+					c.publishPreviewFailed(preview.id, fmt.Sprintf("OBS could not render the source: %v", err))
+					return
+				}
+				continue
+			}
+			missed = 0
+
+			eventbus.Publish(c.bus, eventbus.WebSocketSendMessageToClient{
+				ClientID:    preview.clientID,
+				MessageType: "previewFrame",
+				Payload:     previewFrame{PreviewID: preview.id, Image: image},
+			})
+		}
+	}
+}
@@ -69,6 +69,7 @@ func (c *OBSClient) cleanup() {
 	c.cleanupOnce.Do(func() {
 		c.logger.Debug("Cleaning up OBS client resources.")
 		c.unsubscribeAllEvents()
+		c.stopCaptions()
 		// Ensure a final disconnect call to clean up any active session.
 		c.disconnect("client shutdown")
 	})
@@ -0,0 +1,254 @@
+// backend/obsclient/silence.go
+//
+// This file detects dead air on the main track. The peak levels of the
+// program's inputs are read from the OBS volume meters; a program that stays
+// below the configured threshold for the configured time is reported on the
+// bus and, if configured, covered with its fallback source. Programs that
+// are expected to be silent, by input kind or by their expectSilence flag,
+// are not checked.
+//
+// Contents:
+// - Silence Detector
+// - Silence Monitor
+// - Alert Handling
+
+package obsclient
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/andreykaipov/goobs"
+	"github.com/andreykaipov/goobs/api/typedefs"
+	"scenescheduler/backend/config"
+	"scenescheduler/backend/eventbus"
+)
+
+const (
+	// silenceCheckInterval is how often the program on air is checked.
+	silenceCheckInterval = time.Second
+
+	// silenceGap is the longest pause between meter blocks above the
+	// threshold that still counts as continuous audio.
+	silenceGap = time.Second
+
+	// silenceRecoveryTime is how long audio must be back before the alert
+	// clears, so a single click does not end it.
+	silenceRecoveryTime = 3 * time.Second
+)
+
+// ============================================================================
+// SILENCE DETECTOR
+// ============================================================================
+
+// silenceDetector records when the inputs of the program on air were last
+// heard. It is fed by the OBS event loop and read by the silence monitor.
+type silenceDetector struct {
+	mu         sync.Mutex
+	threshold  float64         // Peak level as a multiplier, from the configured dBFS
+	inputs     map[string]bool // Inputs of the program on air, by OBS input name
+	lastSound  time.Time       // When audio was last above the threshold
+	soundSince time.Time       // When the current stretch of audio started (zero = silent)
+}
+
+// newSilenceDetector creates a detector with the given configuration.
+func newSilenceDetector(cfg config.SilenceConfig) *silenceDetector {
+	return &silenceDetector{threshold: math.Pow(10, cfg.ThresholdDb/20)}
+}
+
+// watch starts following the given inputs. The program is given the full
+// duration before it can be silent, counted from now.
+func (d *silenceDetector) watch(inputNames []string, now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.inputs = make(map[string]bool, len(inputNames))
+	for _, name := range inputNames {
+		d.inputs[name] = true
+	}
+	d.lastSound = now
+	d.soundSince = time.Time{}
+}
+
+// hear processes one block of meter levels. Any channel of any input above
+// the threshold counts as audio.
+func (d *silenceDetector) hear(meters []*typedefs.InputVolumeMeter, now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, meter := range meters {
+		if !d.inputs[meter.Name] {
+			continue
+		}
+		for _, channel := range meter.Levels {
+			if len(channel) > 1 && channel[1] >= d.threshold { // Peak, post-fader
+				if now.Sub(d.lastSound) > silenceGap {
+					d.soundSince = now
+				}
+				d.lastSound = now
+				return
+			}
+		}
+	}
+}
+
+// heard returns when audio was last heard and since when it has been
+// continuous.
+func (d *silenceDetector) heard() (lastSound, soundSince time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.lastSound, d.soundSince
+}
+
+// ============================================================================
+// SILENCE MONITOR
+// ============================================================================
+
+// silenceWatch is the silence state of the program on air. It is owned by
+// the monitor goroutine of the current session.
+type silenceWatch struct {
+	program       *eventbus.Program
+	reported      bool
+	since         time.Time // When the program was last heard before the alert
+	fallbackShown bool
+}
+
+// monitorSilence checks the program on air every second for the length of
+// the session. It does nothing unless silence detection is enabled.
+func (c *OBSClient) monitorSilence() {
+	if c.silence == nil {
+		return
+	}
+	client, sessionCtx := c.getActiveClientAndContext()
+	if client == nil {
+		return
+	}
+
+	c.logger.Debug("Silence monitor started.")
+	defer c.logger.Debug("Silence monitor stopped.")
+
+	ticker := time.NewTicker(silenceCheckInterval)
+	defer ticker.Stop()
+
+	var watched *eventbus.Program
+	var watch *silenceWatch
+	defer func() {
+		if watch != nil {
+			c.endSilenceWatch(client, watch)
+		}
+		c.silence.watch(nil, time.Now())
+	}()
+
+	for {
+		select {
+		case <-sessionCtx.Done():
+			return
+		case now := <-ticker.C:
+			c.stateMu.RLock()
+			active := c.activeProgram
+			c.stateMu.RUnlock()
+
+			if !isProgramSame(watched, active) {
+				if watch != nil {
+					c.endSilenceWatch(client, watch)
+				}
+				watched, watch = active, c.newSilenceWatch(active, now)
+			}
+			if watch != nil {
+				c.checkSilence(client, watch, now)
+			}
+		}
+	}
+}
+
+// newSilenceWatch starts following a program, or returns nil if it is not
+// checked: it has no audio inputs or is expected to be silent.
+func (c *OBSClient) newSilenceWatch(program *eventbus.Program, now time.Time) *silenceWatch {
+	var inputNames []string
+	if program != nil && !program.ExpectSilence && !slices.Contains(c.config.Silence.ExemptInputKinds, program.InputKind) {
+		for name := range c.switcher.AudioInputs(program) {
+			inputNames = append(inputNames, name)
+		}
+	}
+	c.silence.watch(inputNames, now)
+	if len(inputNames) == 0 {
+		return nil
+	}
+	return &silenceWatch{program: program}
+}
+
+// checkSilence acts on a program silent for the configured time, and on
+// its audio coming back.
+func (c *OBSClient) checkSilence(client *goobs.Client, w *silenceWatch, now time.Time) {
+	lastSound, soundSince := c.silence.heard()
+	silentFor := now.Sub(lastSound)
+
+	if !w.reported {
+		if silentFor >= time.Duration(c.config.Silence.DurationSeconds)*time.Second {
+			w.since = lastSound
+			c.reportSilence(client, w, silentFor)
+		}
+		return
+	}
+	if silentFor < silenceGap && now.Sub(soundSince) >= silenceRecoveryTime {
+		c.clearSilence(client, w)
+	}
+}
+
+// endSilenceWatch stops following a program that left the air or a session
+// that ended, releasing its fallback and closing an alert still reported.
+func (c *OBSClient) endSilenceWatch(client *goobs.Client, w *silenceWatch) {
+	if w.fallbackShown {
+		c.releaseFallback(client, w.program, fallbackForSilence)
+		w.fallbackShown = false
+	}
+	if w.reported {
+		c.publishSilence(w, false)
+	}
+}
+
+// ============================================================================
+// ALERT HANDLING
+// ============================================================================
+
+// reportSilence reports dead air and, if configured, covers the program
+// with its fallback. The program's inputs stay on air underneath, so its
+// audio coming back is still heard.
+func (c *OBSClient) reportSilence(client *goobs.Client, w *silenceWatch, silentFor time.Duration) {
+	w.reported = true
+	if c.config.Silence.TriggerFallback {
+		w.fallbackShown = c.showFallback(client, w.program, fallbackForSilence)
+	}
+
+	c.logger.WarnGui("Dead air on the program on air",
+		"program", getProgramTitle(w.program),
+		"silentFor", silentFor.Round(time.Second),
+		"fallback", w.fallbackShown)
+	c.publishSilence(w, true)
+}
+
+// clearSilence uncovers the program and reports that its audio is back.
+func (c *OBSClient) clearSilence(client *goobs.Client, w *silenceWatch) {
+	if w.fallbackShown {
+		c.releaseFallback(client, w.program, fallbackForSilence)
+		w.fallbackShown = false
+	}
+	w.reported = false
+
+	c.logger.InfoGui("Audio is back on the program on air", "program", getProgramTitle(w.program))
+	c.publishSilence(w, false)
+}
+
+// publishSilence publishes dead air, or its end, for web clients.
+func (c *OBSClient) publishSilence(w *silenceWatch, silent bool) {
+	eventbus.Publish(c.bus, eventbus.OBSSilenceChanged{
+		ProgramID:      w.program.ID,
+		Title:          w.program.Title,
+		Silent:         silent,
+		Since:          w.since,
+		FallbackActive: w.fallbackShown,
+		Timestamp:      time.Now(),
+	})
+}
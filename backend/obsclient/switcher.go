@@ -119,11 +119,23 @@ func (c *OBSClient) performProgramSwitch(target *eventbus.Program, offset time.D
             SeekOffsetMs:    result.SeekOffsetMs,
         }
         eventbus.Publish(c.bus, event)
+        if err := c.asRun.append(newAsRunEntry(event)); err != nil {
+            c.logger.Warn("Failed to record as-run entry", "error", err)
+        }
         c.logger.Debug("Published OBSProgramChanged event",
             "previous", getProgramTitle(result.PreviousProgram),
             "current", getProgramTitle(result.CurrentProgram))
+
+        // Follow the loudness of the new program and report the previous one
+        c.trackLoudness("", result.CurrentProgram, c.switcher.AudioInputs(result.CurrentProgram))
+
+        // Captions follow the media, so a program joined late starts mid-file
+        c.startCaptions(result.CurrentProgram, result.Timestamp, time.Duration(result.SeekOffsetMs)*time.Millisecond)
     }
 
+    // New items are added on top of the scene, so restore the track stacking
+    c.arrangeTrackScenes(c.connection.client)
+
     return nil
 }
 
@@ -168,6 +180,9 @@ func (c *OBSClient) setupScene() error {
 		return fmt.Errorf("failed to cleanup main scene %q: %w", mainScene, err)
 	}
 
+	// Track sub-scenes were nested in the main scene, so they are set up again
+	c.resetTracks()
+
 	c.logger.Debug("Scene setup completed. Both scenes have been cleared.")
 
 	return nil
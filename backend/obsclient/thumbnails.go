@@ -0,0 +1,210 @@
+// backend/obsclient/thumbnails.go
+//
+// This file captures the confidence thumbnails shown in the web monitor
+// view: the program on air, and the next program's source. The output is
+// captured on a fixed cadence. The next program's source is captured
+// whenever it exists in OBS, e.g. an existing scene or an input already
+// staged; otherwise its last known image is used, taken the last time it
+// was on air. Changed images are published on the bus and served by the web
+// server.
+//
+// Contents:
+// - Thumbnail Service
+// - Screenshots
+
+package obsclient
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andreykaipov/goobs"
+	"github.com/andreykaipov/goobs/api/requests/sources"
+	"scenescheduler/backend/eventbus"
+)
+
+// thumbnailCacheSize is how many last known images of sources are kept.
+const thumbnailCacheSize = 32
+
+// thumbnail is a captured image.
+type thumbnail struct {
+	image      []byte
+	capturedAt time.Time
+}
+
+// thumbnailSlot is what was last published for a slot.
+type thumbnailSlot struct {
+	programID string
+	image     []byte
+}
+
+// thumbnailService holds the images of one session. It is owned by the
+// thumbnail goroutine of the session.
+type thumbnailService struct {
+	cache map[string]thumbnail // Last known image of each source, by OBS source name
+	slots map[string]thumbnailSlot
+}
+
+// ============================================================================
+// THUMBNAIL SERVICE
+// ============================================================================
+
+// monitorThumbnails captures thumbnails for the length of the session. It
+// does nothing unless thumbnails are enabled.
+func (c *OBSClient) monitorThumbnails() {
+	cfg := c.config.Thumbnails
+	if !cfg.Enabled {
+		return
+	}
+	client, sessionCtx := c.getActiveClientAndContext()
+	if client == nil {
+		return
+	}
+
+	c.logger.Debug("Thumbnail service started.")
+	defer c.logger.Debug("Thumbnail service stopped.")
+
+	ticker := time.NewTicker(time.Duration(cfg.IntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	service := &thumbnailService{
+		cache: make(map[string]thumbnail),
+		slots: make(map[string]thumbnailSlot),
+	}
+	for {
+		select {
+		case <-sessionCtx.Done():
+			return
+		case now := <-ticker.C:
+			c.stateMu.RLock()
+			active, next := c.activeProgram, c.nextProgram
+			c.stateMu.RUnlock()
+
+			c.captureProgramThumbnail(client, service, active, now)
+			c.captureNextThumbnail(client, service, next, now)
+		}
+	}
+}
+
+// captureProgramThumbnail captures the output for the program on air. The
+// image is also kept as the last known image of the program's source.
+func (c *OBSClient) captureProgramThumbnail(client *goobs.Client, service *thumbnailService, program *eventbus.Program, now time.Time) {
+	if program == nil {
+		c.publishThumbnail(service, eventbus.ThumbnailSlotProgram, nil, thumbnail{})
+		return
+	}
+
+	source := c.outputSource(program)
+	image, err := c.thumbnailScreenshot(client, source)
+	if err != nil {
+		c.logger.Debug("Could not capture program thumbnail", "source", source, "error", err)
+		return
+	}
+	current := thumbnail{image: image, capturedAt: now}
+	service.remember(c.programSource(program), current)
+	c.publishThumbnail(service, eventbus.ThumbnailSlotProgram, program, current)
+}
+
+// captureNextThumbnail captures the next program's source if it exists in
+// OBS, and falls back to its last known image otherwise.
+func (c *OBSClient) captureNextThumbnail(client *goobs.Client, service *thumbnailService, program *eventbus.Program, now time.Time) {
+	if program == nil {
+		c.publishThumbnail(service, eventbus.ThumbnailSlotNext, nil, thumbnail{})
+		return
+	}
+
+	source := c.programSource(program)
+	if image, err := c.thumbnailScreenshot(client, source); err == nil {
+		service.remember(source, thumbnail{image: image, capturedAt: now})
+	}
+	// A source that does not exist yet is expected, it has no image then
+	c.publishThumbnail(service, eventbus.ThumbnailSlotNext, program, service.cache[source])
+}
+
+// remember keeps the image of a source, dropping the oldest image once the
+// cache is full.
+func (s *thumbnailService) remember(source string, image thumbnail) {
+	if _, ok := s.cache[source]; !ok && len(s.cache) >= thumbnailCacheSize {
+		var oldest string
+		for name, cached := range s.cache {
+			if oldest == "" || cached.capturedAt.Before(s.cache[oldest].capturedAt) {
+				oldest = name
+			}
+		}
+		delete(s.cache, oldest)
+	}
+	s.cache[source] = image
+}
+
+// publishThumbnail publishes the image of a slot if it changed. A nil
+// program or an empty image clears the slot.
+func (c *OBSClient) publishThumbnail(service *thumbnailService, slot string, program *eventbus.Program, image thumbnail) {
+	event := eventbus.OBSThumbnailUpdated{
+		Slot:       slot,
+		Format:     c.config.Thumbnails.Format,
+		CapturedAt: image.capturedAt,
+		Timestamp:  time.Now(),
+	}
+	if program != nil && len(image.image) > 0 {
+		event.ProgramID = program.ID
+		event.Title = program.Title
+		event.Image = image.image
+	}
+
+	last, published := service.slots[slot]
+	if published && last.programID == event.ProgramID && bytes.Equal(last.image, event.Image) {
+		return
+	}
+	if !published && event.Image == nil {
+		return // Nothing to clear
+	}
+	service.slots[slot] = thumbnailSlot{programID: event.ProgramID, image: event.Image}
+	eventbus.Publish(c.bus, event)
+}
+
+// ============================================================================
+// SCREENSHOTS
+// ============================================================================
+
+// thumbnailScreenshot captures a source in the configured thumbnail format
+// and width.
+func (c *OBSClient) thumbnailScreenshot(client *goobs.Client, sourceName string) ([]byte, error) {
+	return screenshot(client, sourceName, c.config.Thumbnails.Format, c.config.Thumbnails.Width)
+}
+
+// screenshot captures a source as an encoded image of the given format and
+// width; the height follows the aspect ratio of the source.
+func screenshot(client *goobs.Client, sourceName, format string, width int) ([]byte, error) {
+	dataURI, err := screenshotDataURI(client, sourceName, format, width)
+	if err != nil {
+		return nil, err
+	}
+
+	// The image comes as a data URI: "data:image/png;base64,..."
+	_, encoded, ok := strings.Cut(dataURI, ",")
+	if !ok {
+		return nil, fmt.Errorf("unexpected screenshot data")
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid screenshot data: %w", err)
+	}
+	return data, nil
+}
+
+// screenshotDataURI captures a source as a data URI, as OBS returns it.
+func screenshotDataURI(client *goobs.Client, sourceName, format string, width int) (string, error) {
+	imageWidth := float64(width)
+	resp, err := client.Sources.GetSourceScreenshot(&sources.GetSourceScreenshotParams{
+		SourceName:  &sourceName,
+		ImageFormat: &format,
+		ImageWidth:  &imageWidth,
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.ImageData, nil
+}
@@ -0,0 +1,327 @@
+// backend/obsclient/tracks.go
+//
+// This file converges the parallel tracks of the schedule. Each track owns a
+// sub-scene nested in the schedule scene and its own internal switcher, with
+// a track-specific source name prefix, so a switch on one track never tears
+// down the sources of another.
+//
+// Contents:
+// - Track State
+// - Track Convergence
+// - Track Scene Management
+
+package obsclient
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/andreykaipov/goobs"
+	"github.com/andreykaipov/goobs/api/requests/sceneitems"
+	"scenescheduler/backend/eventbus"
+	"scenescheduler/backend/obsclient/internal/switcher"
+)
+
+// ============================================================================
+// TRACK STATE
+// ============================================================================
+
+// trackState holds the convergence state of one parallel track.
+type trackState struct {
+	mu        sync.Mutex // Serializes convergence of this track
+	id        string
+	sceneName string
+	zIndex    int
+	switcher  *switcher.Switcher
+	active    *eventbus.Program // Program currently on air on this track
+	ready     bool              // Sub-scene exists and is nested in the schedule scene
+}
+
+// getTrack returns the state of a track, creating it on first use. A track
+// whose sub-scene changed is replaced, and the old one is returned so the
+// caller can tear it down.
+func (c *OBSClient) getTrack(event eventbus.TrackTargetState) (track, replaced *trackState) {
+	sceneName := event.SceneName
+	if sceneName == "" {
+		sceneName = fmt.Sprintf("%s [%s]", c.config.ScheduleScene, event.TrackID)
+	}
+
+	c.tracksMu.Lock()
+	defer c.tracksMu.Unlock()
+
+	if existing, ok := c.tracks[event.TrackID]; ok {
+		if existing.sceneName == sceneName {
+			existing.zIndex = event.ZIndex
+			return existing, nil
+		}
+		replaced = existing
+	}
+
+	// Each track gets its own switcher working on the sub-scene, with a prefix
+	// that keeps its inputs apart from the main track and other tracks.
+	cfg := *c.config
+	cfg.ScheduleScene = sceneName
+	cfg.SourceNamePrefix = fmt.Sprintf("%s%s_", c.config.SourceNamePrefix, event.TrackID)
+
+	track = &trackState{
+		id:        event.TrackID,
+		sceneName: sceneName,
+		zIndex:    event.ZIndex,
+		switcher:  switcher.New(c.baseLogger.WithFields("track", event.TrackID), &cfg),
+	}
+	c.tracks[event.TrackID] = track
+	return track, replaced
+}
+
+// resetTracks marks every track as not set up and off air. It is called
+// after the scenes are cleared on connection, so each track re-creates its
+// sub-scene and program on the next evaluation.
+func (c *OBSClient) resetTracks() {
+	c.tracksMu.Lock()
+	tracks := make([]*trackState, 0, len(c.tracks))
+	for _, track := range c.tracks {
+		tracks = append(tracks, track)
+	}
+	c.tracksMu.Unlock()
+
+	// Track locks are taken without tracksMu held, since convergeTrack
+	// takes tracksMu while holding a track lock.
+	for _, track := range tracks {
+		track.mu.Lock()
+		track.ready = false
+		track.active = nil
+		track.mu.Unlock()
+	}
+}
+
+// ============================================================================
+// TRACK CONVERGENCE
+// ============================================================================
+
+// convergeTrack is the per-track counterpart of convergeToState. It switches
+// the track's sub-scene only if its target differs from what is on air.
+func (c *OBSClient) convergeTrack(state eventbus.TrackTargetState) {
+	client, _ := c.getActiveClientAndContext()
+	if client == nil {
+		return
+	}
+
+	if strings.HasPrefix(state.SceneName, c.config.SourceNamePrefix) {
+		// The main track's orphan cleanup would remove the nested sub-scene
+		c.logger.Error("Track scene name cannot start with the source name prefix",
+			"track", state.TrackID,
+			"sceneName", state.SceneName,
+			"prefix", c.config.SourceNamePrefix)
+		return
+	}
+
+	// A track cannot take over the program scene, so such scenes are nested
+	if target := state.TargetProgram; target != nil && target.SceneMode == eventbus.SceneModeProgram {
+		nested := *target
+		nested.SceneMode = eventbus.SceneModeNested
+		state.TargetProgram = &nested
+	}
+
+	track, replaced := c.getTrack(state)
+	if replaced != nil {
+		c.teardownTrack(client, replaced)
+	}
+
+	track.mu.Lock()
+	defer track.mu.Unlock()
+
+	if !track.ready {
+		if err := c.setupTrackScene(client, track); err != nil {
+			c.logger.Error("Failed to set up track scene", "track", track.id, "error", err)
+			return
+		}
+		track.ready = true
+		c.arrangeTrackScenes(client)
+	}
+
+	if isProgramSame(track.active, state.TargetProgram) {
+		return
+	}
+
+	c.logger.Debug("Track divergence detected, initiating program switch.",
+		"track", track.id,
+		"current", getProgramTitle(track.active),
+		"target", getProgramTitle(state.TargetProgram))
+
+	result, err := track.switcher.PerformSwitch(client, track.active, state.TargetProgram, state.SeekOffset)
+	if err != nil {
+		c.logger.Error("Track program switch failed", "track", track.id, "error", err)
+		return
+	}
+	track.active = state.TargetProgram
+	c.trackLoudness(track.id, state.TargetProgram, track.switcher.AudioInputs(state.TargetProgram))
+
+	entry := newAsRunEntry(eventbus.OBSProgramChanged{
+		Timestamp:       result.Timestamp,
+		PreviousProgram: result.PreviousProgram,
+		CurrentProgram:  result.CurrentProgram,
+		SeekOffsetMs:    result.SeekOffsetMs,
+	})
+	entry.Track = track.id
+	if err := c.asRun.append(entry); err != nil {
+		c.logger.Warn("Failed to record as-run entry", "error", err)
+	}
+
+	c.logger.Info("Successfully switched track program.", "track", track.id, "newActiveProgram", getProgramTitle(state.TargetProgram))
+}
+
+// pruneTracks tears down tracks that are no longer in the schedule.
+func (c *OBSClient) pruneTracks(trackIDs []string) {
+	keep := make(map[string]bool, len(trackIDs))
+	for _, id := range trackIDs {
+		keep[id] = true
+	}
+
+	var removed []*trackState
+	c.tracksMu.Lock()
+	for id, track := range c.tracks {
+		if !keep[id] {
+			removed = append(removed, track)
+			delete(c.tracks, id)
+		}
+	}
+	c.tracksMu.Unlock()
+
+	if len(removed) == 0 {
+		return
+	}
+	client, _ := c.getActiveClientAndContext()
+	if client == nil {
+		return
+	}
+	for _, track := range removed {
+		c.teardownTrack(client, track)
+	}
+}
+
+// ============================================================================
+// TRACK SCENE MANAGEMENT
+// ============================================================================
+
+// setupTrackScene ensures the track's sub-scene exists, starts empty and is
+// nested, visible, in the schedule scene.
+func (c *OBSClient) setupTrackScene(client *goobs.Client, track *trackState) error {
+	mainScene := c.config.ScheduleScene
+
+	if err := c.ensureSceneExists(client, track.sceneName); err != nil {
+		return fmt.Errorf("failed to ensure track scene '%s' exists: %w", track.sceneName, err)
+	}
+	if err := c.clearAllSceneItems(client, track.sceneName); err != nil {
+		return fmt.Errorf("failed to cleanup track scene %q: %w", track.sceneName, err)
+	}
+
+	if _, err := client.SceneItems.GetSceneItemId(&sceneitems.GetSceneItemIdParams{
+		SceneName:  &mainScene,
+		SourceName: &track.sceneName,
+	}); err == nil {
+		return nil // Already nested
+	}
+	if _, err := client.SceneItems.CreateSceneItem(&sceneitems.CreateSceneItemParams{
+		SceneName:        &mainScene,
+		SourceName:       &track.sceneName,
+		SceneItemEnabled: &[]bool{true}[0],
+	}); err != nil {
+		return fmt.Errorf("failed to nest track scene '%s' in '%s': %w", track.sceneName, mainScene, err)
+	}
+
+	c.logger.Debug("Track scene ready", "track", track.id, "sceneName", track.sceneName)
+	return nil
+}
+
+// teardownTrack takes a track's program off air and removes its sub-scene
+// from the schedule scene. The sub-scene itself is left in OBS, empty.
+func (c *OBSClient) teardownTrack(client *goobs.Client, track *trackState) {
+	track.mu.Lock()
+	defer track.mu.Unlock()
+
+	c.logger.InfoGui("Removing track", "track", track.id)
+
+	if track.active != nil {
+		if _, err := track.switcher.PerformSwitch(client, track.active, nil, 0); err != nil {
+			c.logger.Warn("Failed to take track program off air", "track", track.id, "error", err)
+		}
+		track.active = nil
+	}
+	c.trackLoudness(track.id, nil, nil)
+
+	mainScene := c.config.ScheduleScene
+	if idResp, err := client.SceneItems.GetSceneItemId(&sceneitems.GetSceneItemIdParams{
+		SceneName:  &mainScene,
+		SourceName: &track.sceneName,
+	}); err == nil {
+		// CLEANUP: Best-effort
+		_, _ = client.SceneItems.RemoveSceneItem(&sceneitems.RemoveSceneItemParams{
+			SceneName:   &mainScene,
+			SceneItemId: &idResp.SceneItemId,
+		})
+	}
+	track.ready = false
+}
+
+// arrangeTrackScenes restores the stacking of the nested track scenes in the
+// schedule scene. Tracks with a negative ZIndex go below the main track and
+// the rest above it. It must run after every main-track switch, since new
+// items are always added on top of the scene.
+func (c *OBSClient) arrangeTrackScenes(client *goobs.Client) {
+	type placement struct {
+		sceneName string
+		zIndex    int
+	}
+
+	c.tracksMu.Lock()
+	var placements []placement
+	for _, track := range c.tracks {
+		placements = append(placements, placement{sceneName: track.sceneName, zIndex: track.zIndex})
+	}
+	c.tracksMu.Unlock()
+
+	if len(placements) == 0 {
+		return
+	}
+	sort.Slice(placements, func(i, j int) bool { return placements[i].zIndex < placements[j].zIndex })
+
+	mainScene := c.config.ScheduleScene
+	resp, err := client.SceneItems.GetSceneItemList(&sceneitems.GetSceneItemListParams{
+		SceneName: &mainScene,
+	})
+	if err != nil {
+		c.logger.Warn("Could not arrange track scenes", "error", err)
+		return
+	}
+	itemIDs := make(map[string]int, len(resp.SceneItems))
+	for _, item := range resp.SceneItems {
+		itemIDs[item.SourceName] = item.SceneItemID
+	}
+	top := len(resp.SceneItems) - 1
+
+	// Moving to the top in ascending order leaves the highest ZIndex on top;
+	// moving to the bottom in descending order leaves the lowest at the bottom.
+	for _, p := range placements {
+		if id, ok := itemIDs[p.sceneName]; ok && p.zIndex >= 0 {
+			c.setSceneItemIndex(client, mainScene, id, top)
+		}
+	}
+	for i := len(placements) - 1; i >= 0; i-- {
+		if id, ok := itemIDs[placements[i].sceneName]; ok && placements[i].zIndex < 0 {
+			c.setSceneItemIndex(client, mainScene, id, 0)
+		}
+	}
+}
+
+// setSceneItemIndex moves a scene item in the stacking order (0 = bottom).
+func (c *OBSClient) setSceneItemIndex(client *goobs.Client, sceneName string, sceneItemID, index int) {
+	if _, err := client.SceneItems.SetSceneItemIndex(&sceneitems.SetSceneItemIndexParams{
+		SceneName:      &sceneName,
+		SceneItemId:    &sceneItemID,
+		SceneItemIndex: &index,
+	}); err != nil {
+		c.logger.Debug("Could not move scene item", "scene", sceneName, "sceneItemId", sceneItemID, "error", err)
+	}
+}
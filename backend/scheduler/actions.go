@@ -0,0 +1,192 @@
+// backend/scheduler/actions.go
+//
+// Scheduled OBS actions: streaming, recording and replay buffer control tied
+// to programs or to standalone timeline events, and macros of obs-websocket
+// requests on timeline events.
+//
+// Contents:
+// - Action Clock
+// - Action Firing
+// - Due Action Collection
+
+package scheduler
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"scenescheduler/backend/eventbus"
+)
+
+const (
+	// maxActionOffset bounds ProgramAction.OffsetSeconds, and so how far
+	// around the scanned window program occurrences are expanded.
+	maxActionOffset = time.Hour
+
+	// maxActionCatchUp bounds how much missed time is scanned after a stall,
+	// so a suspended host does not replay hours of actions when it resumes.
+	maxActionCatchUp = time.Minute
+)
+
+// Where a ProgramAction is anchored within its program.
+const (
+	actionAtStart = "start"
+	actionAtEnd   = "end"
+)
+
+// ============================================================================
+// ACTION CLOCK
+// ============================================================================
+
+// actionClock remembers the end of the window already scanned for actions.
+// Unlike the target state, actions are edges: each one must fire exactly
+// once, when the evaluation crosses its time.
+type actionClock struct {
+	mu   sync.Mutex
+	last time.Time // End of the last scanned window (zero = not started)
+}
+
+// newActionClock creates a clock that has not scanned anything yet.
+func newActionClock() *actionClock {
+	return &actionClock{}
+}
+
+// advance returns the window (from, to] to scan and moves the clock to `to`.
+// The first call only starts the clock, so actions that were due before the
+// scheduler started are not fired late.
+func (c *actionClock) advance(to time.Time) (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	from := c.last
+	if !from.IsZero() && !to.After(from) {
+		return time.Time{}, false
+	}
+	c.last = to
+	if from.IsZero() {
+		return time.Time{}, false
+	}
+	if to.Sub(from) > maxActionCatchUp {
+		from = to.Add(-maxActionCatchUp)
+	}
+	return from, true
+}
+
+// ============================================================================
+// ACTION FIRING
+// ============================================================================
+
+// fireDueActions publishes every OBS action whose time falls between the
+// previous evaluation and now.
+func (s *Scheduler) fireDueActions(schedule *Schedule, now time.Time) {
+	from, ok := s.actions.advance(now)
+	if !ok || schedule == nil {
+		return
+	}
+
+	for _, action := range dueActions(schedule, from, now) {
+		s.logger.Info("Scheduled OBS action due",
+			"action", action.Action,
+			"source", action.SourceID,
+			"scheduledAt", action.ScheduledAt.Format(time.RFC3339))
+		action.Timestamp = now
+		eventbus.Publish(s.bus, action)
+	}
+}
+
+// ============================================================================
+// DUE ACTION COLLECTION
+// ============================================================================
+
+// dueActions returns the actions of programs, track programs and timeline
+// events scheduled in (from, to], in time order. Actions anchored to the end
+// of a program use the end of its slot, even if its media ends earlier.
+func dueActions(schedule *Schedule, from, to time.Time) []eventbus.OBSActionRequested {
+	var due []eventbus.OBSActionRequested
+
+	programLists := [][]ScheduledProgram{schedule.Programs}
+	for _, track := range schedule.Tracks {
+		programLists = append(programLists, track.Programs)
+	}
+	for _, programs := range programLists {
+		for _, occ := range expandOccurrences(programs, from.Add(-maxActionOffset), to.Add(maxActionOffset)) {
+			for _, a := range occ.Program.Behavior.Actions {
+				at := occ.Start
+				if a.At == actionAtEnd {
+					at = occ.End
+				}
+				at = at.Add(time.Duration(a.OffsetSeconds) * time.Second)
+				if !at.After(from) || at.After(to) {
+					continue
+				}
+				filename := a.Filename
+				if filename == "" {
+					filename = occ.Program.Title
+				}
+				due = append(due, eventbus.OBSActionRequested{
+					Action:      a.Action,
+					SourceID:    occ.Program.ID,
+					Title:       occ.Program.Title,
+					Filename:    filename,
+					ScheduledAt: at,
+				})
+			}
+		}
+	}
+
+	for _, event := range schedule.Events {
+		proxy := []ScheduledProgram{timelineEventProgram(&event)}
+		for _, occ := range expandOccurrences(proxy, from, to.Add(time.Second)) {
+			if !occ.Start.After(from) || occ.Start.After(to) {
+				continue
+			}
+			due = append(due, eventbus.OBSActionRequested{
+				Action:      event.Action,
+				SourceID:    event.ID,
+				Title:       event.Title,
+				Filename:    event.Filename,
+				Steps:       toExecutableMacro(event.Steps),
+				ScheduledAt: occ.Start,
+			})
+		}
+	}
+
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].ScheduledAt.Before(due[j].ScheduledAt)
+	})
+	return due
+}
+
+// toExecutableMacro translates the steps of a macro event into their
+// eventbus form.
+func toExecutableMacro(steps []MacroStep) []eventbus.MacroStep {
+	if len(steps) == 0 {
+		return nil
+	}
+	result := make([]eventbus.MacroStep, len(steps))
+	for i, step := range steps {
+		result[i] = eventbus.MacroStep{
+			RequestType: step.RequestType,
+			RequestData: step.RequestData,
+			OnError:     step.OnError,
+			DelayMs:     step.DelayMs,
+		}
+	}
+	return result
+}
+
+// timelineEventProgram wraps a timeline event in a one second program so it
+// shares the occurrence expansion, including recurrence, with programs.
+func timelineEventProgram(event *TimelineEvent) ScheduledProgram {
+	timing := event.Timing
+	if !timing.Start.IsZero() {
+		timing.End = timing.Start.Add(time.Second)
+	}
+	return ScheduledProgram{
+		ID:      event.ID,
+		Title:   event.Title,
+		Enabled: event.Enabled,
+		Timing:  timing,
+	}
+}
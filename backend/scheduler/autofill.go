@@ -0,0 +1,410 @@
+// backend/scheduler/autofill.go
+//
+// Automatic programming assistant that fills schedule gaps from a tagged
+// content pool. The result is a draft returned to the requesting client for
+// review; nothing is written to schedule.json.
+//
+// Contents:
+// - Request Types
+// - Draft Generation
+// - Candidate Selection
+// - Rule Helpers
+// - Client Communication
+
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"scenescheduler/backend/eventbus"
+)
+
+// ============================================================================
+// REQUEST TYPES
+// ============================================================================
+
+// maxAutoFillRange bounds the range a client may fill in one request.
+const maxAutoFillRange = 31 * 24 * time.Hour
+
+// AutoFillRequest is the payload of an autoFillSchedule request.
+type AutoFillRequest struct {
+	From  time.Time     `json:"from"`  // Start of the range to fill
+	To    time.Time     `json:"to"`    // End of the range to fill
+	Pool  []ContentItem `json:"pool"`  // Content available for filling
+	Rules AutoFillRules `json:"rules"` // Programming rules
+}
+
+// ContentItem is a piece of content that can be placed into a gap.
+type ContentItem struct {
+	ID              string   `json:"id"`
+	Title           string   `json:"title"`
+	DurationSeconds int      `json:"durationSeconds"`
+	Tags            []string `json:"tags"`
+	Source          Source   `json:"source"`
+}
+
+// AutoFillRules constrains how content is placed.
+type AutoFillRules struct {
+	NoRepeatWithinMinutes int             `json:"noRepeatWithinMinutes"` // Minimum spacing between two airings of the same item
+	MaxPerHour            []TagLimit      `json:"maxPerHour"`            // Per-tag limits over any rolling hour
+	Preferences           []TagPreference `json:"preferences"`           // Tags to favor during a daily time window
+}
+
+// TagLimit caps how many items carrying a tag may start within one hour.
+type TagLimit struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// TagPreference favors items carrying a tag between two local times of day.
+type TagPreference struct {
+	Tag  string `json:"tag"`
+	From string `json:"from"` // HH:MM, local time
+	To   string `json:"to"`   // HH:MM, local time (may be earlier than From for overnight windows)
+}
+
+// AutoFillDraft is the result returned to the client for review.
+type AutoFillDraft struct {
+	Programs []ScheduledProgram `json:"schedule"` // Generated programs, not yet committed
+	Unfilled []timeRange        `json:"unfilled"` // Gap portions no content could fill
+}
+
+// ============================================================================
+// DRAFT GENERATION
+// ============================================================================
+
+// autoFill parses the request, generates a draft for the current schedule
+// and sends it to the client.
+func (s *Scheduler) autoFill(clientID string, payload json.RawMessage) {
+	var req AutoFillRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		s.logger.Error("Failed to parse auto-fill payload", "error", err, "clientID", clientID)
+		s.sendAutoFillError(clientID, "Invalid auto-fill request format")
+		return
+	}
+	if err := req.validate(); err != nil {
+		s.sendAutoFillError(clientID, err.Error())
+		return
+	}
+
+	s.mu.RLock()
+	currentSchedule := s.resolved
+	s.mu.RUnlock()
+
+	var programs []ScheduledProgram
+	if currentSchedule != nil {
+		programs = currentSchedule.Programs
+	}
+
+	draft := generateAutoFillDraft(programs, req)
+	s.logger.Info("Generated auto-fill draft",
+		"clientID", clientID,
+		"programs", len(draft.Programs),
+		"unfilledGaps", len(draft.Unfilled))
+
+	eventbus.Publish(s.bus, eventbus.WebSocketSendMessageToClient{
+		ClientID:    clientID,
+		MessageType: "autoFillDraft",
+		Payload:     draft,
+	})
+}
+
+// validate checks the request for obviously unusable input.
+func (r *AutoFillRequest) validate() error {
+	if r.From.IsZero() || r.To.IsZero() || !r.To.After(r.From) {
+		return fmt.Errorf("a valid 'from' and 'to' range is required")
+	}
+	if r.To.Sub(r.From) > maxAutoFillRange {
+		return fmt.Errorf("the range cannot exceed %d days", int(maxAutoFillRange.Hours()/24))
+	}
+	if len(r.Pool) == 0 {
+		return fmt.Errorf("the content pool is empty")
+	}
+	for i, item := range r.Pool {
+		if item.ID == "" || item.DurationSeconds <= 0 {
+			return fmt.Errorf("pool[%d]: id and a positive durationSeconds are required", i)
+		}
+	}
+	for _, pref := range r.Rules.Preferences {
+		if _, _, err := parseWindow(pref.From, pref.To); err != nil {
+			return fmt.Errorf("preference for tag %q: %w", pref.Tag, err)
+		}
+	}
+	return nil
+}
+
+// generateAutoFillDraft fills every gap in [req.From, req.To) greedily,
+// placing one item after another. When the rules block every item for a
+// while, the cursor skips ahead to the time the first item is allowed again,
+// and only the skipped stretch is left unfilled.
+func generateAutoFillDraft(programs []ScheduledProgram, req AutoFillRequest) AutoFillDraft {
+	occurrences := expandOccurrences(programs, req.From, req.To)
+	gaps := findGaps(occurrences, req.From, req.To)
+
+	state := &autoFillState{
+		rules:     req.Rules,
+		lastStart: make(map[string]time.Time),
+	}
+	draft := AutoFillDraft{Programs: make([]ScheduledProgram, 0)}
+
+	for _, gap := range gaps {
+		cursor := gap.Start
+		for cursor.Before(gap.End) {
+			item := state.bestCandidate(req.Pool, cursor, gap.End)
+			if item == nil {
+				next, ok := state.nextEligible(req.Pool, cursor, gap.End)
+				if !ok {
+					draft.Unfilled = append(draft.Unfilled, timeRange{Start: cursor, End: gap.End})
+					break
+				}
+				draft.Unfilled = append(draft.Unfilled, timeRange{Start: cursor, End: next})
+				cursor = next
+				continue
+			}
+			end := cursor.Add(time.Duration(item.DurationSeconds) * time.Second)
+			draft.Programs = append(draft.Programs, state.toProgram(item, cursor, end))
+			state.record(item, cursor)
+			cursor = end
+		}
+	}
+	return draft
+}
+
+// ============================================================================
+// CANDIDATE SELECTION
+// ============================================================================
+
+// autoFillState remembers what has been placed so far in the draft.
+type autoFillState struct {
+	rules     AutoFillRules
+	lastStart map[string]time.Time // Item ID -> start of its last placement
+	placed    []placement          // All placements, in order
+}
+
+// placement records a placed item for rolling tag limits.
+type placement struct {
+	start time.Time
+	tags  []string
+}
+
+// bestCandidate returns the eligible item with the highest score at `at`, or
+// nil if nothing fits before `limit`. Items matching an active preference
+// win over those that do not; among equals, longer items win to minimize
+// fragmentation, then the least recently placed item.
+func (st *autoFillState) bestCandidate(pool []ContentItem, at, limit time.Time) *ContentItem {
+	var best *ContentItem
+	bestPreferred := false
+
+	for i := range pool {
+		item := &pool[i]
+		if at.Add(time.Duration(item.DurationSeconds) * time.Second).After(limit) {
+			continue
+		}
+		if !st.repeatAllowed(item, at) || !st.tagLimitsAllow(item, at) {
+			continue
+		}
+
+		preferred := st.preferred(item, at)
+		if best == nil || st.better(item, preferred, best, bestPreferred) {
+			best = item
+			bestPreferred = preferred
+		}
+	}
+	return best
+}
+
+// nextEligible returns the earliest time after `at` when an item becomes
+// allowed again by the repeat and tag rules and still fits before `limit`.
+// Nothing is placed in between, so the rules only relax as time passes.
+func (st *autoFillState) nextEligible(pool []ContentItem, at, limit time.Time) (time.Time, bool) {
+	var next time.Time
+	found := false
+
+	for i := range pool {
+		item := &pool[i]
+		free, ok := st.freeAt(item, at)
+		if !ok || free.Add(time.Duration(item.DurationSeconds)*time.Second).After(limit) {
+			continue
+		}
+		if !found || free.Before(next) {
+			next = free
+			found = true
+		}
+	}
+	return next, found
+}
+
+// freeAt returns the earliest time from `at` on when the repeat and tag
+// rules allow the item, assuming nothing else is placed. Returns false if a
+// tag limit never allows it.
+func (st *autoFillState) freeAt(item *ContentItem, at time.Time) (time.Time, bool) {
+	free := at
+	if last, placed := st.lastStart[item.ID]; placed && st.rules.NoRepeatWithinMinutes > 0 {
+		if t := last.Add(time.Duration(st.rules.NoRepeatWithinMinutes) * time.Minute); t.After(free) {
+			free = t
+		}
+	}
+
+	for _, limit := range st.rules.MaxPerHour {
+		if !hasTag(item.Tags, limit.Tag) {
+			continue
+		}
+		if limit.Count <= 0 {
+			return time.Time{}, false
+		}
+		// The limit allows the item once the limit.Count-th most recent
+		// placement with the tag is an hour old
+		seen := 0
+		for j := len(st.placed) - 1; j >= 0; j-- {
+			if !hasTag(st.placed[j].tags, limit.Tag) {
+				continue
+			}
+			seen++
+			if seen == limit.Count {
+				if t := st.placed[j].start.Add(time.Hour); t.After(free) {
+					free = t
+				}
+				break
+			}
+		}
+	}
+	return free, true
+}
+
+// better reports whether candidate a outranks the current best b.
+func (st *autoFillState) better(a *ContentItem, aPreferred bool, b *ContentItem, bPreferred bool) bool {
+	if aPreferred != bPreferred {
+		return aPreferred
+	}
+	if a.DurationSeconds != b.DurationSeconds {
+		return a.DurationSeconds > b.DurationSeconds
+	}
+	return st.lastStart[a.ID].Before(st.lastStart[b.ID])
+}
+
+// record stores a placement so later rule checks can see it.
+func (st *autoFillState) record(item *ContentItem, start time.Time) {
+	st.lastStart[item.ID] = start
+	st.placed = append(st.placed, placement{start: start, tags: item.Tags})
+}
+
+// toProgram converts a placement into a draft ScheduledProgram.
+func (st *autoFillState) toProgram(item *ContentItem, start, end time.Time) ScheduledProgram {
+	title := item.Title
+	if title == "" {
+		title = item.ID
+	}
+	return ScheduledProgram{
+		ID:      fmt.Sprintf("%s%s-%d", AutoFillProgramIDPrefix, item.ID, start.Unix()),
+		Title:   title,
+		Enabled: true,
+		General: General{
+			Description: "Auto-filled draft",
+			Tags:        item.Tags,
+			ClassNames:  []string{"autofill-draft"},
+		},
+		Source: item.Source,
+		Timing: Timing{
+			Start: start.UTC(),
+			End:   end.UTC(),
+		},
+		Behavior: Behavior{OnEndAction: "hide"},
+	}
+}
+
+// ============================================================================
+// RULE HELPERS
+// ============================================================================
+
+// repeatAllowed enforces the no-repeat window for a single item.
+func (st *autoFillState) repeatAllowed(item *ContentItem, at time.Time) bool {
+	last, placed := st.lastStart[item.ID]
+	if !placed || st.rules.NoRepeatWithinMinutes <= 0 {
+		return true
+	}
+	return at.Sub(last) >= time.Duration(st.rules.NoRepeatWithinMinutes)*time.Minute
+}
+
+// tagLimitsAllow enforces per-tag limits over the rolling hour ending at `at`.
+func (st *autoFillState) tagLimitsAllow(item *ContentItem, at time.Time) bool {
+	windowStart := at.Add(-time.Hour)
+	for _, limit := range st.rules.MaxPerHour {
+		if !hasTag(item.Tags, limit.Tag) {
+			continue
+		}
+		count := 0
+		for _, p := range st.placed {
+			if p.start.After(windowStart) && hasTag(p.tags, limit.Tag) {
+				count++
+			}
+		}
+		if count >= limit.Count {
+			return false
+		}
+	}
+	return true
+}
+
+// preferred reports whether the item matches a preference active at `at`.
+// Preference windows are in local time, like the rest of the schedule.
+func (st *autoFillState) preferred(item *ContentItem, at time.Time) bool {
+	local := at.Local()
+	minuteOfDay := local.Hour()*60 + local.Minute()
+	for _, pref := range st.rules.Preferences {
+		if !hasTag(item.Tags, pref.Tag) {
+			continue
+		}
+		from, to, err := parseWindow(pref.From, pref.To)
+		if err != nil {
+			continue
+		}
+		if from <= to && minuteOfDay >= from && minuteOfDay < to {
+			return true
+		}
+		if from > to && (minuteOfDay >= from || minuteOfDay < to) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseWindow converts two HH:MM strings into minutes of the day.
+func parseWindow(from, to string) (int, int, error) {
+	f, err := time.Parse("15:04", from)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time %q, expected HH:MM", from)
+	}
+	t, err := time.Parse("15:04", to)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time %q, expected HH:MM", to)
+	}
+	return f.Hour()*60 + f.Minute(), t.Hour()*60 + t.Minute(), nil
+}
+
+// hasTag reports whether tags contains tag, ignoring case.
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// ============================================================================
+// CLIENT COMMUNICATION
+// ============================================================================
+
+// sendAutoFillError sends an error response to the client if generation fails.
+func (s *Scheduler) sendAutoFillError(clientID string, message string) {
+	eventbus.Publish(s.bus, eventbus.WebSocketSendMessageToClient{
+		ClientID:    clientID,
+		MessageType: "autoFillError",
+		Payload: map[string]interface{}{
+			"message": message,
+		},
+	})
+}
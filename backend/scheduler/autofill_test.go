@@ -0,0 +1,227 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// withLocal runs the test with time.Local set to the given zone.
+func withLocal(t *testing.T, loc *time.Location) {
+	t.Helper()
+	saved := time.Local
+	time.Local = loc
+	t.Cleanup(func() { time.Local = saved })
+}
+
+func TestFreeAt(t *testing.T) {
+	base := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	news := ContentItem{ID: "news", DurationSeconds: 600, Tags: []string{"news"}}
+
+	tests := []struct {
+		name     string
+		rules    AutoFillRules
+		placed   []time.Time // Earlier starts of the news item
+		wantFree time.Time
+		wantOK   bool
+	}{
+		{
+			name:     "never placed",
+			rules:    AutoFillRules{NoRepeatWithinMinutes: 30},
+			wantFree: base,
+			wantOK:   true,
+		},
+		{
+			name:     "inside the repeat window",
+			rules:    AutoFillRules{NoRepeatWithinMinutes: 30},
+			placed:   []time.Time{base.Add(-10 * time.Minute)},
+			wantFree: base.Add(20 * time.Minute),
+			wantOK:   true,
+		},
+		{
+			name:     "outside the repeat window",
+			rules:    AutoFillRules{NoRepeatWithinMinutes: 30},
+			placed:   []time.Time{base.Add(-45 * time.Minute)},
+			wantFree: base,
+			wantOK:   true,
+		},
+		{
+			name:     "tag limit reached",
+			rules:    AutoFillRules{MaxPerHour: []TagLimit{{Tag: "NEWS", Count: 2}}},
+			placed:   []time.Time{base.Add(-50 * time.Minute), base.Add(-20 * time.Minute)},
+			wantFree: base.Add(10 * time.Minute),
+			wantOK:   true,
+		},
+		{
+			name:     "tag limit not reached",
+			rules:    AutoFillRules{MaxPerHour: []TagLimit{{Tag: "news", Count: 3}}},
+			placed:   []time.Time{base.Add(-50 * time.Minute), base.Add(-20 * time.Minute)},
+			wantFree: base,
+			wantOK:   true,
+		},
+		{
+			name:   "tag never allowed",
+			rules:  AutoFillRules{MaxPerHour: []TagLimit{{Tag: "news", Count: 0}}},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := &autoFillState{rules: tt.rules, lastStart: make(map[string]time.Time)}
+			for _, start := range tt.placed {
+				st.record(&news, start)
+			}
+
+			free, ok := st.freeAt(&news, base)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && !free.Equal(tt.wantFree) {
+				t.Errorf("free = %v, want %v", free, tt.wantFree)
+			}
+		})
+	}
+}
+
+func TestNextEligible(t *testing.T) {
+	base := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	short := ContentItem{ID: "short", DurationSeconds: 300}
+	long := ContentItem{ID: "long", DurationSeconds: 1800}
+
+	tests := []struct {
+		name     string
+		limit    time.Time
+		wantNext time.Time
+		wantOK   bool
+	}{
+		{
+			name:     "earliest item wins",
+			limit:    base.Add(2 * time.Hour),
+			wantNext: base.Add(10 * time.Minute),
+			wantOK:   true,
+		},
+		{
+			name:     "only the item that still fits",
+			limit:    base.Add(30 * time.Minute),
+			wantNext: base.Add(20 * time.Minute),
+			wantOK:   true,
+		},
+		{
+			name:   "nothing fits before the limit",
+			limit:  base.Add(22 * time.Minute),
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := &autoFillState{
+				rules:     AutoFillRules{NoRepeatWithinMinutes: 60},
+				lastStart: make(map[string]time.Time),
+			}
+			st.record(&long, base.Add(-50*time.Minute))  // Free at +10m, ends at +40m
+			st.record(&short, base.Add(-40*time.Minute)) // Free at +20m, ends at +25m
+
+			next, ok := st.nextEligible([]ContentItem{short, long}, base, tt.limit)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && !next.Equal(tt.wantNext) {
+				t.Errorf("next = %v, want %v", next, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestGenerateAutoFillDraft(t *testing.T) {
+	// Preference windows are local; make local time differ from UTC
+	withLocal(t, time.FixedZone("UTC+2", 2*60*60))
+
+	from := time.Date(2026, 10, 19, 6, 0, 0, 0, time.UTC) // 08:00 local
+	minutes := func(m int) time.Time { return from.Add(time.Duration(m) * time.Minute) }
+
+	tests := []struct {
+		name         string
+		hours        int
+		pool         []ContentItem
+		rules        AutoFillRules
+		wantIDs      []string // Item IDs of the draft programs, in order
+		wantUnfilled []timeRange
+	}{
+		{
+			name:    "fills the range back to back",
+			hours:   1,
+			pool:    []ContentItem{{ID: "a", DurationSeconds: 1800}},
+			wantIDs: []string{"a", "a"},
+		},
+		{
+			name:         "repeat window leaves the rest unfilled",
+			hours:        1,
+			pool:         []ContentItem{{ID: "a", DurationSeconds: 1200}},
+			rules:        AutoFillRules{NoRepeatWithinMinutes: 60},
+			wantIDs:      []string{"a"},
+			wantUnfilled: []timeRange{{Start: minutes(20), End: minutes(60)}},
+		},
+		{
+			name:    "skips ahead while a tag limit blocks",
+			hours:   3,
+			pool:    []ContentItem{{ID: "a", DurationSeconds: 1800, Tags: []string{"ad"}}},
+			rules:   AutoFillRules{MaxPerHour: []TagLimit{{Tag: "ad", Count: 1}}},
+			wantIDs: []string{"a", "a", "a"},
+			wantUnfilled: []timeRange{
+				{Start: minutes(30), End: minutes(60)},
+				{Start: minutes(90), End: minutes(120)},
+				{Start: minutes(150), End: minutes(180)},
+			},
+		},
+		{
+			name:         "tag never allowed",
+			hours:        1,
+			pool:         []ContentItem{{ID: "a", DurationSeconds: 600, Tags: []string{"ad"}}},
+			rules:        AutoFillRules{MaxPerHour: []TagLimit{{Tag: "ad", Count: 0}}},
+			wantIDs:      []string{},
+			wantUnfilled: []timeRange{{Start: minutes(0), End: minutes(60)}},
+		},
+		{
+			name:  "preference window in local time",
+			hours: 1,
+			pool: []ContentItem{
+				{ID: "other", DurationSeconds: 1800},
+				{ID: "morning", DurationSeconds: 1800, Tags: []string{"morning"}},
+			},
+			rules:   AutoFillRules{Preferences: []TagPreference{{Tag: "morning", From: "08:00", To: "08:30"}}},
+			wantIDs: []string{"morning", "other"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			draft := generateAutoFillDraft(nil, AutoFillRequest{
+				From:  from,
+				To:    from.Add(time.Duration(tt.hours) * time.Hour),
+				Pool:  tt.pool,
+				Rules: tt.rules,
+			})
+
+			if len(draft.Programs) != len(tt.wantIDs) {
+				t.Fatalf("got %d programs, want %d", len(draft.Programs), len(tt.wantIDs))
+			}
+			for i, p := range draft.Programs {
+				want := AutoFillProgramIDPrefix + tt.wantIDs[i] + "-"
+				if !strings.HasPrefix(p.ID, want) {
+					t.Errorf("program %d: ID = %q, want prefix %q", i, p.ID, want)
+				}
+			}
+
+			if len(draft.Unfilled) != len(tt.wantUnfilled) {
+				t.Fatalf("got unfilled %v, want %v", draft.Unfilled, tt.wantUnfilled)
+			}
+			for i, r := range draft.Unfilled {
+				if !r.Start.Equal(tt.wantUnfilled[i].Start) || !r.End.Equal(tt.wantUnfilled[i].End) {
+					t.Errorf("unfilled %d = %v, want %v", i, r, tt.wantUnfilled[i])
+				}
+			}
+		})
+	}
+}
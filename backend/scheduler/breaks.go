@@ -0,0 +1,210 @@
+// backend/scheduler/breaks.go
+//
+// Break insertion: scheduled breaks that interrupt a long program and let it
+// resume where it left off.
+//
+// Contents:
+// - Break Windows
+// - Break Evaluation
+// - Break Item Selection
+
+package scheduler
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"scenescheduler/backend/config"
+	"scenescheduler/backend/eventbus"
+)
+
+// ============================================================================
+// BREAK WINDOWS
+// ============================================================================
+
+// breakWindow is one concrete break within a program occurrence.
+type breakWindow struct {
+	Index int
+	Rule  BreakRule
+	Start time.Time
+	End   time.Time
+}
+
+// breakWindows computes the breaks of one occurrence of p. Breaks are
+// triggered by program content time, so a program with a 20 minute interval
+// and 2 minute breaks airs content 0-20, break, content 20-40, break, and so
+// on. A break that would not fit before the end of the slot is dropped, and
+// so are the breaks of rules whose pool is empty or unknown, since nothing
+// could air during them. Like the rest of the scheduler this is a pure
+// function of time, so the calendar and the live evaluation always agree.
+func breakWindows(p *ScheduledProgram, occStart, occEnd time.Time, pools map[string][]config.FillerItem) []breakWindow {
+	type trigger struct {
+		content time.Duration
+		rule    int
+	}
+
+	var triggers []trigger
+	slot := occEnd.Sub(occStart)
+	for i, rule := range p.Behavior.Breaks {
+		interval := time.Duration(rule.IntervalMinutes) * time.Minute
+		if interval <= 0 || rule.DurationSeconds <= 0 || len(pools[rule.Pool]) == 0 {
+			continue
+		}
+		for content := interval; content < slot; content += interval {
+			triggers = append(triggers, trigger{content: content, rule: i})
+		}
+	}
+	sort.SliceStable(triggers, func(i, j int) bool {
+		return triggers[i].content < triggers[j].content
+	})
+
+	var windows []breakWindow
+	var elapsedBreaks time.Duration
+	for _, t := range triggers {
+		rule := p.Behavior.Breaks[t.rule]
+		start := occStart.Add(t.content + elapsedBreaks)
+		end := start.Add(time.Duration(rule.DurationSeconds) * time.Second)
+		if end.After(occEnd) {
+			break
+		}
+		windows = append(windows, breakWindow{Index: len(windows), Rule: rule, Start: start, End: end})
+		elapsedBreaks += end.Sub(start)
+	}
+	return windows
+}
+
+// ============================================================================
+// BREAK EVALUATION
+// ============================================================================
+
+// breakDecision is the outcome of applying break rules at a point in time.
+type breakDecision struct {
+	Target     *ScheduledProgram   // Break item, or the program itself between breaks
+	Next       *ScheduledProgram   // What airs after Target within the slot (nil if unknown)
+	SeekOffset time.Duration       // Offset into Target
+	Break      *eventbus.BreakInfo // Set while a break is on air
+}
+
+// resolveBreaks applies the break rules of p at `now`. Returns nil if p has no
+// break rules. Rundown blocks have none: their breaks are ignored, and their
+// segments do not inherit them (see segmentBehavior). Between breaks, the
+// seek offset is the program content time, which excludes the time spent in
+// earlier breaks.
+func (s *Scheduler) resolveBreaks(p *ScheduledProgram, now time.Time) *breakDecision {
+	if p == nil || len(p.Behavior.Breaks) == 0 || len(p.Rundown) > 0 || isGapProgram(p) {
+		return nil
+	}
+
+	occStart := getOccurrenceStart(p, now)
+	occEnd := getProgramEndTime(p, occStart)
+	if occStart.IsZero() || occEnd.IsZero() {
+		return nil
+	}
+
+	var elapsedBreaks time.Duration
+	for _, w := range breakWindows(p, occStart, occEnd, s.config.BreakPools) {
+		if now.Before(w.Start) {
+			// Next break is still ahead: the program is on air.
+			decision := &breakDecision{
+				Target:     p,
+				SeekOffset: now.Sub(occStart) - elapsedBreaks,
+			}
+			if item, _ := s.breakItemAt(p, w, w.Start); item != nil {
+				decision.Next = item
+			}
+			return decision
+		}
+		if now.Before(w.End) {
+			item, itemStart := s.breakItemAt(p, w, now)
+			return &breakDecision{
+				Target:     item,
+				Next:       p,
+				SeekOffset: now.Sub(itemStart),
+				Break: &eventbus.BreakInfo{
+					ProgramID:    p.ID,
+					ProgramTitle: p.Title,
+					Index:        w.Index,
+					Pool:         w.Rule.Pool,
+					Start:        w.Start,
+					End:          w.End,
+				},
+			}
+		}
+		elapsedBreaks += w.End.Sub(w.Start)
+	}
+
+	return &breakDecision{
+		Target:     p,
+		SeekOffset: now.Sub(occStart) - elapsedBreaks,
+	}
+}
+
+// ============================================================================
+// BREAK ITEM SELECTION
+// ============================================================================
+
+// breakItemAt returns the pool item airing at `at` within break w, and when
+// that item started. Items air back to back for their MaxDurationSeconds
+// (0 = the rest of the break). The first item is derived from the program,
+// occurrence and break index so consecutive breaks do not always open with
+// the same promo, while every evaluation of the same break agrees.
+func (s *Scheduler) breakItemAt(p *ScheduledProgram, w breakWindow, at time.Time) (*ScheduledProgram, time.Time) {
+	pool := s.config.BreakPools[w.Rule.Pool]
+	if len(pool) == 0 {
+		return nil, time.Time{}
+	}
+
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s@%d#%d", p.ID, w.Start.Unix(), w.Index)
+	first := int(h.Sum32() % uint32(len(pool)))
+
+	itemStart := w.Start
+	for slot := 0; ; slot++ {
+		item := &pool[(first+slot)%len(pool)]
+		itemEnd := w.End
+		if item.MaxDurationSeconds > 0 {
+			itemEnd = itemStart.Add(time.Duration(item.MaxDurationSeconds) * time.Second)
+			if itemEnd.After(w.End) {
+				itemEnd = w.End
+			}
+		}
+		if at.Before(itemEnd) || !itemEnd.Before(w.End) {
+			return breakItemProgram(p, w, slot, item, itemStart, itemEnd), itemStart
+		}
+		itemStart = itemEnd
+	}
+}
+
+// breakItemProgram converts a break pool item into a ScheduledProgram.
+func breakItemProgram(p *ScheduledProgram, w breakWindow, slot int, item *config.FillerItem, start, end time.Time) *ScheduledProgram {
+	var inputSettings map[string]interface{}
+	if m, ok := item.InputSettings.(map[string]interface{}); ok {
+		inputSettings = m
+	}
+	var transform map[string]interface{}
+	if m, ok := item.Transform.(map[string]interface{}); ok {
+		transform = m
+	}
+
+	title := item.Title
+	if title == "" {
+		title = item.Name
+	}
+
+	return &ScheduledProgram{
+		ID:      fmt.Sprintf("%s%s%d-%d", p.ID, BreakIDMarker, w.Index, slot),
+		Title:   fmt.Sprintf("Break: %s", title),
+		Enabled: true,
+		Source: Source{
+			Name:          item.Name,
+			InputKind:     item.InputKind,
+			URI:           item.URI,
+			InputSettings: inputSettings,
+			Transform:     transform,
+		},
+		Timing:   Timing{Start: start, End: end},
+		parentID: p.ID,
+	}
+}
@@ -0,0 +1,173 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"scenescheduler/backend/config"
+)
+
+func TestBreakWindows(t *testing.T) {
+	occStart := time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC)
+	pools := map[string][]config.FillerItem{
+		"promos": {{Name: "promo"}},
+		"empty":  {},
+	}
+	offset := func(minutes, seconds int) time.Time {
+		return occStart.Add(time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second)
+	}
+
+	tests := []struct {
+		name        string
+		breaks      []BreakRule
+		slotMinutes int
+		want        [][2]time.Time // Start and end of each window
+	}{
+		{
+			name:        "no rules",
+			slotMinutes: 60,
+		},
+		{
+			name:        "content time excludes earlier breaks",
+			breaks:      []BreakRule{{IntervalMinutes: 20, DurationSeconds: 120, Pool: "promos"}},
+			slotMinutes: 60,
+			want: [][2]time.Time{
+				{offset(20, 0), offset(22, 0)},
+				{offset(42, 0), offset(44, 0)},
+			},
+		},
+		{
+			name:        "break past the slot end is dropped",
+			breaks:      []BreakRule{{IntervalMinutes: 20, DurationSeconds: 120, Pool: "promos"}},
+			slotMinutes: 43,
+			want:        [][2]time.Time{{offset(20, 0), offset(22, 0)}},
+		},
+		{
+			name: "empty or unknown pools are skipped",
+			breaks: []BreakRule{
+				{IntervalMinutes: 20, DurationSeconds: 120, Pool: "empty"},
+				{IntervalMinutes: 20, DurationSeconds: 120, Pool: "missing"},
+			},
+			slotMinutes: 60,
+		},
+		{
+			name: "invalid rules are skipped",
+			breaks: []BreakRule{
+				{IntervalMinutes: 0, DurationSeconds: 120, Pool: "promos"},
+				{IntervalMinutes: 20, DurationSeconds: 0, Pool: "promos"},
+			},
+			slotMinutes: 60,
+		},
+		{
+			name: "rules are merged by content time",
+			breaks: []BreakRule{
+				{IntervalMinutes: 30, DurationSeconds: 60, Pool: "promos"},
+				{IntervalMinutes: 20, DurationSeconds: 30, Pool: "promos"},
+			},
+			slotMinutes: 65,
+			want: [][2]time.Time{
+				{offset(20, 0), offset(20, 30)},
+				{offset(30, 30), offset(31, 30)},
+				{offset(41, 30), offset(42, 0)},
+				{offset(62, 0), offset(63, 0)},
+				{offset(63, 0), offset(63, 30)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &ScheduledProgram{ID: "movie", Behavior: Behavior{Breaks: tt.breaks}}
+			occEnd := occStart.Add(time.Duration(tt.slotMinutes) * time.Minute)
+
+			windows := breakWindows(p, occStart, occEnd, pools)
+			if len(windows) != len(tt.want) {
+				t.Fatalf("got %d windows, want %d: %v", len(windows), len(tt.want), windows)
+			}
+			for i, w := range windows {
+				if w.Index != i {
+					t.Errorf("window %d: index = %d", i, w.Index)
+				}
+				if !w.Start.Equal(tt.want[i][0]) || !w.End.Equal(tt.want[i][1]) {
+					t.Errorf("window %d = %v - %v, want %v - %v", i, w.Start, w.End, tt.want[i][0], tt.want[i][1])
+				}
+			}
+		})
+	}
+}
+
+func TestBreakItemAt(t *testing.T) {
+	pools := map[string][]config.FillerItem{
+		"promos": {
+			{Name: "a", MaxDurationSeconds: 30},
+			{Name: "b", MaxDurationSeconds: 30},
+			{Name: "c", MaxDurationSeconds: 30},
+		},
+		"single": {{Name: "long"}},
+	}
+	s := &Scheduler{config: &config.SchedulerConfig{BreakPools: pools}}
+	p := &ScheduledProgram{ID: "movie"}
+
+	breakStart := time.Date(2026, 10, 19, 20, 20, 0, 0, time.UTC)
+	window := func(pool string) breakWindow {
+		return breakWindow{
+			Index: 1,
+			Rule:  BreakRule{Pool: pool},
+			Start: breakStart,
+			End:   breakStart.Add(100 * time.Second),
+		}
+	}
+
+	// The first item depends on the program and break; items rotate from it
+	first, _ := s.breakItemAt(p, window("promos"), breakStart)
+	if first == nil {
+		t.Fatal("no item at the start of the break")
+	}
+	firstIndex := strings.Index("abc", strings.TrimPrefix(first.Title, "Break: "))
+
+	tests := []struct {
+		name      string
+		pool      string
+		atSeconds int
+		wantSlot  int // Position in the rotation (-1 = no item)
+		wantStart int // Seconds into the break the item started
+	}{
+		{name: "start of the break", pool: "promos", atSeconds: 0, wantSlot: 0, wantStart: 0},
+		{name: "second item", pool: "promos", atSeconds: 35, wantSlot: 1, wantStart: 30},
+		{name: "rotation wraps around", pool: "promos", atSeconds: 95, wantSlot: 3, wantStart: 90},
+		{name: "past the end keeps the last item", pool: "promos", atSeconds: 120, wantSlot: 3, wantStart: 90},
+		{name: "unlimited item takes the whole break", pool: "single", atSeconds: 80, wantSlot: 0, wantStart: 0},
+		{name: "unknown pool", pool: "missing", atSeconds: 10, wantSlot: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := window(tt.pool)
+			item, start := s.breakItemAt(p, w, breakStart.Add(time.Duration(tt.atSeconds)*time.Second))
+
+			if tt.wantSlot < 0 {
+				if item != nil {
+					t.Fatalf("got item %q, want none", item.ID)
+				}
+				return
+			}
+			if item == nil {
+				t.Fatal("got no item")
+			}
+			if wantID := fmt.Sprintf("movie%s1-%d", BreakIDMarker, tt.wantSlot); item.ID != wantID {
+				t.Errorf("ID = %q, want %q", item.ID, wantID)
+			}
+			if wantStart := breakStart.Add(time.Duration(tt.wantStart) * time.Second); !start.Equal(wantStart) {
+				t.Errorf("item start = %v, want %v", start, wantStart)
+			}
+			if tt.pool == "promos" {
+				want := "Break: " + string("abc"[(firstIndex+tt.wantSlot)%3])
+				if item.Title != want {
+					t.Errorf("title = %q, want %q", item.Title, want)
+				}
+			}
+		})
+	}
+}
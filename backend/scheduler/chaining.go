@@ -0,0 +1,249 @@
+// backend/scheduler/chaining.go
+//
+// Resolution of chained programs into absolute start and end times.
+//
+// Contents:
+// - Chain Resolution
+// - Recurrence Inheritance
+
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// CHAIN RESOLUTION
+// ============================================================================
+
+// chainResolver walks the dependency graph formed by Timing.After and
+// Timing.Anchor references using a depth-first search.
+type chainResolver struct {
+	programs []ScheduledProgram
+	index    map[string]int  // Program ID -> position in programs
+	state    map[string]int  // Program ID -> visit state (see below)
+	failed   map[string]bool // Program IDs that could not be resolved
+	stack    []string        // Current DFS path, used to describe cycles
+	issues   []ValidationIssue
+}
+
+const (
+	chainUnvisited = iota
+	chainVisiting
+	chainResolved
+)
+
+// resolveChains computes absolute Start/End values for every chained program,
+// in place. It only runs on the evaluation copy of a schedule (see
+// validateSchedule), so the schedule sent to clients keeps its references. A
+// chained program inherits the recurrence of the program it depends on, so a
+// chain rooted at a recurring block repeats with it.
+//
+// Programs that cannot be resolved (unknown reference, cycle, missing
+// duration) are disabled so they never air at a stale time, and an error
+// issue is returned for each of them.
+func resolveChains(programs []ScheduledProgram) []ValidationIssue {
+	r := &chainResolver{
+		programs: programs,
+		index:    make(map[string]int, len(programs)),
+		state:    make(map[string]int, len(programs)),
+		failed:   make(map[string]bool),
+	}
+	for i := range programs {
+		if _, exists := r.index[programs[i].ID]; !exists {
+			r.index[programs[i].ID] = i
+		}
+	}
+
+	for i := range programs {
+		if programs[i].Timing.IsChained() {
+			r.resolve(programs[i].ID)
+		}
+	}
+
+	for id := range r.failed {
+		programs[r.index[id]].Enabled = false
+	}
+	return r.issues
+}
+
+// resolve resolves a single program and, recursively, everything it depends
+// on. Returns false if the program's times could not be determined.
+func (r *chainResolver) resolve(id string) bool {
+	i, exists := r.index[id]
+	if !exists {
+		return false
+	}
+	p := &r.programs[i]
+
+	switch r.state[id] {
+	case chainResolved:
+		return !r.failed[id]
+	case chainVisiting:
+		r.reportCycle(id)
+		return false
+	}
+
+	if !p.Timing.IsChained() {
+		r.state[id] = chainResolved
+		return true
+	}
+
+	r.state[id] = chainVisiting
+	r.stack = append(r.stack, id)
+	ok := r.resolveChained(p)
+	r.stack = r.stack[:len(r.stack)-1]
+	r.state[id] = chainResolved
+
+	if !ok {
+		r.failed[id] = true
+	}
+	return ok
+}
+
+// resolveChained computes the times of a chained program from its reference.
+func (r *chainResolver) resolveChained(p *ScheduledProgram) bool {
+	t := &p.Timing
+
+	if t.After != "" && t.Anchor != "" {
+		r.fail(p.ID, "both 'after' and 'anchor' are set, only one reference is allowed")
+		return false
+	}
+	if t.DurationSeconds <= 0 {
+		r.fail(p.ID, "chained program requires a positive durationSeconds")
+		return false
+	}
+
+	refID := t.After
+	if refID == "" {
+		refID = t.Anchor
+	}
+	refIndex, exists := r.index[refID]
+	if !exists {
+		r.fail(p.ID, fmt.Sprintf("references unknown program %q", refID))
+		return false
+	}
+	if !r.resolve(refID) {
+		if !r.failed[p.ID] {
+			r.fail(p.ID, fmt.Sprintf("depends on program %q which could not be resolved", refID))
+		}
+		return false
+	}
+
+	ref := &r.programs[refIndex]
+	refStart, refEnd := referenceBounds(ref)
+	if refStart.IsZero() || refEnd.IsZero() {
+		r.fail(p.ID, fmt.Sprintf("referenced program %q has no start or end time", refID))
+		return false
+	}
+
+	base := refStart
+	if t.After != "" {
+		base = refEnd
+	}
+	start := base.Add(time.Duration(t.OffsetSeconds) * time.Second)
+
+	t.Start = start
+	t.End = start.Add(time.Duration(t.DurationSeconds) * time.Second)
+	t.IsRecurring = ref.Timing.IsRecurring
+	if t.IsRecurring {
+		t.Recurrence = shiftRecurrence(ref.Timing.Recurrence, daysBetween(refStart, start))
+	}
+	return true
+}
+
+// referenceBounds returns the start and end of a referenced program. For
+// recurring programs the end is normalized so overnight templates end on the
+// following day, matching how occurrences are built during evaluation.
+func referenceBounds(ref *ScheduledProgram) (time.Time, time.Time) {
+	if !ref.Timing.IsRecurring || ref.Timing.Start.IsZero() || ref.Timing.End.IsZero() {
+		return ref.Timing.Start, ref.Timing.End
+	}
+	local := ref.Timing.Start.Local()
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)
+	return recurringBoundsOn(ref, day)
+}
+
+// reportCycle records a cycle error for every program on the current path
+// from the first occurrence of id to the top of the stack.
+func (r *chainResolver) reportCycle(id string) {
+	from := 0
+	for i, stacked := range r.stack {
+		if stacked == id {
+			from = i
+			break
+		}
+	}
+	cycle := append(append([]string{}, r.stack[from:]...), id)
+	message := "dependency cycle: " + strings.Join(cycle, " -> ")
+	for _, member := range r.stack[from:] {
+		r.fail(member, message)
+	}
+}
+
+// fail records a resolution error for a program, once.
+func (r *chainResolver) fail(id, message string) {
+	if r.failed[id] {
+		return
+	}
+	r.failed[id] = true
+	r.issues = append(r.issues, ValidationIssue{
+		ProgramID: id,
+		Severity:  SeverityError,
+		Message:   message,
+	})
+}
+
+// ============================================================================
+// RECURRENCE INHERITANCE
+// ============================================================================
+
+// weekDayNames maps weekdays back to the names used in schedule.json.
+var weekDayNames = [7]string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+// shiftRecurrence returns a copy of the recurrence moved by the given number
+// of days. This keeps a chained program attached to the right occurrence when
+// the chain crosses midnight relative to its root.
+func shiftRecurrence(rec Recurrence, days int) Recurrence {
+	if days == 0 {
+		return rec
+	}
+
+	shifted := Recurrence{
+		DaysOfWeek: make([]string, 0, len(rec.DaysOfWeek)),
+		StartRecur: shiftDate(rec.StartRecur, days),
+		EndRecur:   shiftDate(rec.EndRecur, days),
+	}
+	for _, name := range rec.DaysOfWeek {
+		weekday, ok := weekDaysMap[strings.ToUpper(name)]
+		if !ok {
+			continue
+		}
+		shifted.DaysOfWeek = append(shifted.DaysOfWeek, weekDayNames[((int(weekday)+days)%7+7)%7])
+	}
+	return shifted
+}
+
+// shiftDate moves a YYYY-MM-DD date by the given number of days. Empty or
+// unparsable dates are returned unchanged.
+func shiftDate(date string, days int) string {
+	if date == "" {
+		return date
+	}
+	d, err := time.ParseInLocation(dateFormat, date, time.Local)
+	if err != nil {
+		return date
+	}
+	return d.AddDate(0, 0, days).Format(dateFormat)
+}
+
+// daysBetween returns the number of local calendar days from a to b.
+func daysBetween(a, b time.Time) int {
+	a = a.Local()
+	b = b.Local()
+	dayA := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	dayB := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(dayB.Sub(dayA).Hours() / 24)
+}
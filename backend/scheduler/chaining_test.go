@@ -0,0 +1,235 @@
+package scheduler
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixed returns an enabled program with absolute times.
+func fixed(id string, start, end time.Time) ScheduledProgram {
+	return ScheduledProgram{ID: id, Enabled: true, Timing: Timing{Start: start, End: end}}
+}
+
+// chained returns an enabled program whose start depends on another one.
+func chained(id string, timing Timing) ScheduledProgram {
+	return ScheduledProgram{ID: id, Enabled: true, Timing: timing}
+}
+
+func TestResolveChains(t *testing.T) {
+	withLocal(t, time.UTC)
+
+	at := func(hour, minute int) time.Time { return time.Date(2026, 10, 19, hour, minute, 0, 0, time.UTC) }
+	block := fixed("block", at(10, 0), at(11, 0))
+
+	tests := []struct {
+		name       string
+		programs   []ScheduledProgram
+		wantTimes  map[string][2]time.Time // Resolved start and end by ID
+		wantFailed []string                // IDs disabled by the resolution
+		wantIssue  string                  // Substring of the first issue message
+	}{
+		{
+			name: "after a fixed program",
+			programs: []ScheduledProgram{
+				block,
+				chained("next", Timing{After: "block", DurationSeconds: 600}),
+			},
+			wantTimes: map[string][2]time.Time{"next": {at(11, 0), at(11, 10)}},
+		},
+		{
+			name: "anchored with an offset",
+			programs: []ScheduledProgram{
+				block,
+				chained("bumper", Timing{Anchor: "block", OffsetSeconds: 300, DurationSeconds: 60}),
+			},
+			wantTimes: map[string][2]time.Time{"bumper": {at(10, 5), at(10, 6)}},
+		},
+		{
+			name: "chain of chains listed before its root",
+			programs: []ScheduledProgram{
+				chained("third", Timing{After: "second", DurationSeconds: 300}),
+				chained("second", Timing{After: "block", DurationSeconds: 600}),
+				block,
+			},
+			wantTimes: map[string][2]time.Time{
+				"second": {at(11, 0), at(11, 10)},
+				"third":  {at(11, 10), at(11, 15)},
+			},
+		},
+		{
+			name: "cycle",
+			programs: []ScheduledProgram{
+				chained("a", Timing{After: "b", DurationSeconds: 60}),
+				chained("b", Timing{After: "a", DurationSeconds: 60}),
+			},
+			wantFailed: []string{"a", "b"},
+			wantIssue:  "dependency cycle",
+		},
+		{
+			name: "depends on a cycle",
+			programs: []ScheduledProgram{
+				chained("a", Timing{After: "b", DurationSeconds: 60}),
+				chained("b", Timing{After: "a", DurationSeconds: 60}),
+				chained("c", Timing{After: "a", DurationSeconds: 60}),
+			},
+			wantFailed: []string{"a", "b", "c"},
+			wantIssue:  "dependency cycle",
+		},
+		{
+			name: "unknown reference",
+			programs: []ScheduledProgram{
+				chained("orphan", Timing{After: "missing", DurationSeconds: 60}),
+			},
+			wantFailed: []string{"orphan"},
+			wantIssue:  "unknown program",
+		},
+		{
+			name: "missing duration",
+			programs: []ScheduledProgram{
+				block,
+				chained("next", Timing{After: "block"}),
+			},
+			wantFailed: []string{"next"},
+			wantIssue:  "durationSeconds",
+		},
+		{
+			name: "both references",
+			programs: []ScheduledProgram{
+				block,
+				chained("next", Timing{After: "block", Anchor: "block", DurationSeconds: 60}),
+			},
+			wantFailed: []string{"next"},
+			wantIssue:  "only one reference",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			programs := append([]ScheduledProgram(nil), tt.programs...)
+			issues := resolveChains(programs)
+
+			byID := make(map[string]*ScheduledProgram)
+			for i := range programs {
+				byID[programs[i].ID] = &programs[i]
+			}
+			for id, want := range tt.wantTimes {
+				p := byID[id]
+				if !p.Timing.Start.Equal(want[0]) || !p.Timing.End.Equal(want[1]) {
+					t.Errorf("%s: resolved %v - %v, want %v - %v", id, p.Timing.Start, p.Timing.End, want[0], want[1])
+				}
+			}
+
+			var failed []string
+			for _, p := range programs {
+				if !p.Enabled {
+					failed = append(failed, p.ID)
+				}
+			}
+			if !reflect.DeepEqual(failed, tt.wantFailed) {
+				t.Errorf("disabled = %v, want %v", failed, tt.wantFailed)
+			}
+			if len(issues) != len(tt.wantFailed) {
+				t.Fatalf("got %d issues, want %d: %v", len(issues), len(tt.wantFailed), issues)
+			}
+			if tt.wantIssue != "" && !strings.Contains(issues[0].Message, tt.wantIssue) {
+				t.Errorf("issue = %q, want it to mention %q", issues[0].Message, tt.wantIssue)
+			}
+		})
+	}
+}
+
+func TestResolveChainsRecurrence(t *testing.T) {
+	withLocal(t, time.UTC)
+
+	root := ScheduledProgram{
+		ID:      "late",
+		Enabled: true,
+		Timing: Timing{
+			Start:       time.Date(2026, 10, 19, 23, 0, 0, 0, time.UTC),
+			End:         time.Date(2026, 10, 19, 23, 30, 0, 0, time.UTC),
+			IsRecurring: true,
+			Recurrence:  Recurrence{DaysOfWeek: []string{"MON", "SAT"}, StartRecur: "2026-10-19", EndRecur: "2026-12-31"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		timing     Timing
+		wantStart  time.Time
+		wantRecurs Recurrence
+	}{
+		{
+			name:       "same day keeps the recurrence",
+			timing:     Timing{After: "late", DurationSeconds: 600},
+			wantStart:  time.Date(2026, 10, 19, 23, 30, 0, 0, time.UTC),
+			wantRecurs: root.Timing.Recurrence,
+		},
+		{
+			name:       "past midnight shifts the recurrence",
+			timing:     Timing{After: "late", OffsetSeconds: 3600, DurationSeconds: 600},
+			wantStart:  time.Date(2026, 10, 20, 0, 30, 0, 0, time.UTC),
+			wantRecurs: Recurrence{DaysOfWeek: []string{"TUE", "SUN"}, StartRecur: "2026-10-20", EndRecur: "2027-01-01"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			programs := []ScheduledProgram{root, chained("next", tt.timing)}
+			if issues := resolveChains(programs); len(issues) > 0 {
+				t.Fatalf("unexpected issues: %v", issues)
+			}
+
+			next := programs[1].Timing
+			if !next.IsRecurring {
+				t.Fatal("chained program did not inherit the recurrence")
+			}
+			if !next.Start.Equal(tt.wantStart) {
+				t.Errorf("start = %v, want %v", next.Start, tt.wantStart)
+			}
+			if !reflect.DeepEqual(next.Recurrence, tt.wantRecurs) {
+				t.Errorf("recurrence = %+v, want %+v", next.Recurrence, tt.wantRecurs)
+			}
+		})
+	}
+}
+
+func TestShiftRecurrence(t *testing.T) {
+	rec := Recurrence{DaysOfWeek: []string{"sun", "WED", "bogus"}, StartRecur: "2026-12-31", EndRecur: ""}
+
+	tests := []struct {
+		name string
+		days int
+		want Recurrence
+	}{
+		{
+			name: "no shift",
+			days: 0,
+			want: rec,
+		},
+		{
+			name: "forward across the year",
+			days: 1,
+			want: Recurrence{DaysOfWeek: []string{"MON", "THU"}, StartRecur: "2027-01-01", EndRecur: ""},
+		},
+		{
+			name: "backward wraps the week",
+			days: -1,
+			want: Recurrence{DaysOfWeek: []string{"SAT", "TUE"}, StartRecur: "2026-12-30", EndRecur: ""},
+		},
+		{
+			name: "a full week",
+			days: 7,
+			want: Recurrence{DaysOfWeek: []string{"SUN", "WED"}, StartRecur: "2027-01-07", EndRecur: ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shiftRecurrence(rec, tt.days); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("shiftRecurrence(%d) = %+v, want %+v", tt.days, got, tt.want)
+			}
+		})
+	}
+}
@@ -42,11 +42,18 @@ type Scheduler struct {
 	unsubscribeFuncs []func()
 
 	// --- Internal State (protected by mutex) ---
-	mu       sync.RWMutex
-	schedule *Schedule // Current loaded schedule
+	mu               sync.RWMutex
+	schedule         *Schedule         // Current loaded schedule, as stored in schedule.json
+	resolved         *Schedule         // Copy of schedule with chains resolved, used for evaluation
+	validationIssues []ValidationIssue // Issues found when the schedule was loaded
 
 	// --- Internal Components ---
-	fileWatcher *fileWatcher // Watches schedule.json for changes
+	fileWatcher *fileWatcher    // Watches schedule.json for changes
+	filler      *fillerRotation // Rotation state for the filler pool
+	rundown     *rundownTracker // Live segment of the active rundown block
+	earlyEnd    *earlyEnd       // Program occurrence ended early by its media
+	actions     *actionClock    // Window of time already scanned for OBS actions
+	evaluateNow chan struct{}   // Wakes the run loop for an immediate evaluation
 }
 
 // ============================================================================
@@ -80,6 +87,11 @@ func New(
 		bus:              bus,
 		paths:            pathsCfg,
 		config:           schedulerCfg,
+		filler:           newFillerRotation(),
+		rundown:          newRundownTracker(),
+		earlyEnd:         newEarlyEnd(),
+		actions:          newActionClock(),
+		evaluateNow:      make(chan struct{}, 1),
 		unsubscribeFuncs: make([]func(), 0),
 	}
 
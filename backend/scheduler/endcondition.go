@@ -0,0 +1,94 @@
+// backend/scheduler/endcondition.go
+//
+// Early program end driven by OBS media events.
+//
+// Contents:
+// - Early End State
+// - Media End Handling
+// - Evaluation Helpers
+
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// EARLY END STATE
+// ============================================================================
+
+// earlyEnd remembers the program occurrence whose media already finished.
+// Only one program is on air at a time, so a single occurrence is enough.
+type earlyEnd struct {
+	mu  sync.Mutex
+	key string    // Program ID + occurrence start of the ended occurrence
+	at  time.Time // When the media ended
+}
+
+// newEarlyEnd creates an empty early end state.
+func newEarlyEnd() *earlyEnd {
+	return &earlyEnd{}
+}
+
+// occurrenceKey identifies one occurrence of a program.
+func occurrenceKey(p *ScheduledProgram, now time.Time) string {
+	return fmt.Sprintf("%s@%d", p.ID, getOccurrenceStart(p, now).Unix())
+}
+
+// ============================================================================
+// MEDIA END HANDLING
+// ============================================================================
+
+// mediaEnded handles the end of media playback in the on-air program. Rundown
+// segments advance to the next segment; programs with the mediaEnd end
+// condition end early and the rest of their slot is treated as a gap. Either
+// way the schedule is re-evaluated immediately instead of on the next tick.
+func (s *Scheduler) mediaEnded(programID string, at time.Time) {
+	if strings.Contains(programID, SegmentIDSeparator) {
+		s.logger.Debug("Rundown segment media ended", "programID", programID)
+		s.rundown.segmentMediaEnded(programID, at)
+		s.requestEvaluation()
+		return
+	}
+
+	s.mu.RLock()
+	currentSchedule := s.resolved
+	s.mu.RUnlock()
+	if currentSchedule == nil {
+		return
+	}
+
+	program := findProgramAtTime(currentSchedule.Programs, at)
+	if program == nil || program.ID != programID || program.Behavior.EndCondition != EndConditionMediaEnd {
+		return
+	}
+
+	s.earlyEnd.mu.Lock()
+	s.earlyEnd.key = occurrenceKey(program, at)
+	s.earlyEnd.at = at
+	s.earlyEnd.mu.Unlock()
+
+	s.logger.InfoGui("Program media ended before its scheduled end, ending early",
+		"program", getProgramTitle(program),
+		"scheduledEnd", getProgramEndTime(program, at).Format(time.TimeOnly))
+	s.requestEvaluation()
+}
+
+// ============================================================================
+// EVALUATION HELPERS
+// ============================================================================
+
+// endedEarly reports whether the occurrence of p active at `now` already
+// ended because its media finished playing.
+func (s *Scheduler) endedEarly(p *ScheduledProgram, now time.Time) bool {
+	if p == nil || p.Behavior.EndCondition != EndConditionMediaEnd {
+		return false
+	}
+
+	s.earlyEnd.mu.Lock()
+	defer s.earlyEnd.mu.Unlock()
+	return s.earlyEnd.key != "" && s.earlyEnd.key == occurrenceKey(p, now)
+}
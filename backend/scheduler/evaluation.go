@@ -5,7 +5,7 @@
 // Contents:
 // - Main Evaluation Method
 // - Target State Publishing
-// - Default Source Handling
+// - Gap Handling (Filler Pool and Default Source)
 // - Helper Methods
 
 package scheduler
@@ -21,14 +21,17 @@ import (
 // ============================================================================
 
 // evaluateAndSwitch contains the core scheduling logic executed on every tick.
-// It is stateless: it always publishes the current desired state without
-// comparing to previous state. The responsibility of deciding whether to act
-// has been moved to the OBSClient.
+// It always publishes the current desired state without comparing to the
+// previous one; deciding whether to act is the responsibility of the
+// OBSClient. It is not stateless, though: the filler rotation, the live
+// rundown segment, early program ends and the action clock all carry state
+// from one evaluation to the next. It must only run on the run loop, which
+// serializes evaluations; other goroutines call requestEvaluation instead.
 func (s *Scheduler) evaluateAndSwitch() {
 	now := time.Now()
 
 	s.mu.RLock()
-	currentSchedule := s.schedule
+	currentSchedule := s.resolved
 	s.mu.RUnlock()
 
 	var targetProgram *ScheduledProgram
@@ -36,16 +39,31 @@ func (s *Scheduler) evaluateAndSwitch() {
 		targetProgram = findProgramAtTime(currentSchedule.Programs, now)
 	}
 
-	// If no scheduled program is active and a default source is configured, use it
-	if targetProgram == nil && s.config.DefaultSource.Name != "" {
-		targetProgram = s.defaultSourceToProgram()
+	// A program whose media already finished leaves the rest of its slot as a gap
+	if s.endedEarly(targetProgram, now) {
+		targetProgram = nil
+	}
+
+	// Rundown blocks resolve to their live segment
+	var nextSegment *ScheduledProgram
+	if targetProgram != nil && len(targetProgram.Rundown) > 0 {
+		targetProgram, nextSegment = s.rundownProgramAt(targetProgram, now)
+	} else {
+		s.leaveRundown(now)
+	}
+
+	// If no scheduled program is active, fill the gap
+	if targetProgram == nil {
+		targetProgram = s.gapProgramAt(now)
+	} else {
+		s.endFillerGap(now)
 	}
 
 	// Calculate the next program for informational purposes
-	var nextProgram *ScheduledProgram
-	if currentSchedule != nil {
+	nextProgram := nextSegment
+	if nextProgram == nil && currentSchedule != nil {
 		searchStartTime := now
-		if targetProgram != nil && !isDefaultSource(targetProgram) {
+		if targetProgram != nil && !isGapProgram(targetProgram) {
 			searchStartTime = getProgramEndTime(targetProgram, now)
 		}
 		if !searchStartTime.IsZero() {
@@ -55,20 +73,50 @@ func (s *Scheduler) evaluateAndSwitch() {
 
 	// Calculate seek offset for media that can be seeked
 	var seekOffset time.Duration
-	if targetProgram != nil && !isDefaultSource(targetProgram) {
+	if targetProgram != nil && !isGapProgram(targetProgram) {
 		startTime := getProgramStartTime(targetProgram, now)
 		if now.After(startTime) {
 			seekOffset = now.Sub(startTime)
 		}
 	}
 
+	// Breaks interrupt the program; between breaks it resumes where it left off
+	var breakInfo *eventbus.BreakInfo
+	if decision := s.resolveBreaks(targetProgram, now); decision != nil {
+		targetProgram = decision.Target
+		seekOffset = decision.SeekOffset
+		breakInfo = decision.Break
+		if decision.Next != nil {
+			nextProgram = decision.Next
+		}
+	}
+
 	// Always publish the desired state. The OBSClient will decide if action is needed.
 	eventbus.Publish(s.bus, eventbus.TargetProgramState{
 		Timestamp:     now,
-		TargetProgram: toExecutableProgram(targetProgram),
+		TargetProgram: s.withDefaultFallback(targetProgram, toExecutableProgram(targetProgram)),
 		NextProgram:   toExecutableProgram(nextProgram),
 		SeekOffset:    seekOffset,
+		Break:         breakInfo,
+		Tracks:        trackIDs(currentSchedule),
 	})
+
+	// Parallel tracks are evaluated independently of the main track
+	s.evaluateTracks(currentSchedule, now)
+
+	// OBS output actions fire once, when their time is crossed
+	s.fireDueActions(currentSchedule, now)
+}
+
+// requestEvaluation asks the run loop to evaluate immediately instead of on
+// the next tick. Because every evaluation runs on the run loop, the states
+// they publish can never reach the OBSClient out of order.
+func (s *Scheduler) requestEvaluation() {
+	select {
+	case s.evaluateNow <- struct{}{}:
+	default:
+		// An evaluation is already pending
+	}
 }
 
 // ============================================================================
@@ -85,20 +133,148 @@ func toExecutableProgram(p *ScheduledProgram) *eventbus.Program {
 	return &eventbus.Program{
 		ID:            p.ID,
 		Title:         p.Title,
+		Kind:          programKind(p),
+		ParentID:      p.parentID,
 		SourceName:    p.Source.Name,
+		SceneName:     p.Source.SceneName,
+		SceneMode:     p.Source.SceneMode,
 		InputKind:     p.Source.InputKind,
 		URI:           p.Source.URI,
 		InputSettings: p.Source.InputSettings,
 		Transform:     p.Source.Transform,
+		Audio:         toExecutableAudio(p.Source.Audio),
+		Filters:       toExecutableFilters(p.Source.Filters),
+		Captions:      p.Source.Captions,
+		Fallback:      toExecutableFallback(p, p.Behavior.Fallback),
+		ExpectSilence: p.Behavior.ExpectSilence,
+		Layers:        toExecutableLayers(p.Layers),
+		Transition:    toExecutableTransition(p.Behavior.Transition),
 		Start:         p.Timing.Start,
 		End:           p.Timing.End,
 	}
 }
 
+// toExecutableLayers converts a program's layers into their event form.
+func toExecutableLayers(layers []Layer) []eventbus.Layer {
+	if len(layers) == 0 {
+		return nil
+	}
+	result := make([]eventbus.Layer, len(layers))
+	for i, l := range layers {
+		result[i] = eventbus.Layer{
+			SourceName:    l.Name,
+			SceneName:     l.SceneName,
+			InputKind:     l.InputKind,
+			URI:           l.URI,
+			InputSettings: l.InputSettings,
+			Transform:     l.Transform,
+			Audio:         toExecutableAudio(l.Audio),
+			Filters:       toExecutableFilters(l.Filters),
+			ZIndex:        l.ZIndex,
+		}
+	}
+	return result
+}
+
+// toExecutableTransition converts a program's transition into its event form.
+func toExecutableTransition(t *Transition) *eventbus.Transition {
+	if t == nil {
+		return nil
+	}
+	return &eventbus.Transition{
+		Type:           t.Type,
+		DurationMs:     t.DurationMs,
+		StingerURI:     t.StingerURI,
+		StingerPointMs: t.StingerPointMs,
+		AudioFadeMs:    t.AudioFadeMs,
+	}
+}
+
+// toExecutableFallback converts the fallback source of a program into a
+// program of its own, so the OBSClient can stage it like any other.
+func toExecutableFallback(p *ScheduledProgram, src *Source) *eventbus.Program {
+	if src == nil || (src.Name == "" && src.SceneName == "") {
+		return nil
+	}
+	return &eventbus.Program{
+		ID:            p.ID,
+		Title:         p.Title + " [fallback]",
+		Kind:          programKind(p),
+		SourceName:    src.Name,
+		SceneName:     src.SceneName,
+		InputKind:     src.InputKind,
+		URI:           src.URI,
+		InputSettings: src.InputSettings,
+		Transform:     src.Transform,
+		Audio:         toExecutableAudio(src.Audio),
+		Filters:       toExecutableFilters(src.Filters),
+	}
+}
+
+// withDefaultFallback gives a scheduled program without a fallback of its
+// own the default source as fallback. Gap programs already are the fallback.
+func (s *Scheduler) withDefaultFallback(p *ScheduledProgram, program *eventbus.Program) *eventbus.Program {
+	if program == nil || program.Fallback != nil || isGapProgram(p) || s.config.DefaultSource.Name == "" {
+		return program
+	}
+	program.Fallback = toExecutableFallback(p, &s.defaultSourceToProgram().Source)
+	return program
+}
+
+// audioMonitorTypes maps the monitor types of the schedule to their OBS names.
+var audioMonitorTypes = map[string]string{
+	"none":             eventbus.AudioMonitorNone,
+	"monitorOnly":      eventbus.AudioMonitorOnly,
+	"monitorAndOutput": eventbus.AudioMonitorAndOutput,
+}
+
+// toExecutableAudio converts a source's audio settings into their event form.
+// Unknown monitor types are dropped; validation reports them.
+func toExecutableAudio(a *Audio) *eventbus.Audio {
+	if a == nil {
+		return nil
+	}
+	return &eventbus.Audio{
+		VolumeDb:    a.VolumeDb,
+		Muted:       a.Muted,
+		MonitorType: audioMonitorTypes[a.MonitorType],
+		Balance:     a.Balance,
+	}
+}
+
+// toExecutableFilters converts a source's filters into their event form.
+func toExecutableFilters(filters []Filter) []eventbus.Filter {
+	if len(filters) == 0 {
+		return nil
+	}
+	result := make([]eventbus.Filter, len(filters))
+	for i, f := range filters {
+		result[i] = eventbus.Filter{
+			Kind:     f.Kind,
+			Name:     f.Name,
+			Settings: f.Settings,
+		}
+	}
+	return result
+}
+
 // ============================================================================
-// DEFAULT SOURCE HANDLING
+// GAP HANDLING (FILLER POOL AND DEFAULT SOURCE)
 // ============================================================================
 
+// gapProgramAt returns the program that covers a gap in the schedule: an item
+// from the filler pool if one is configured, otherwise the default source.
+// Returns nil if neither is configured.
+func (s *Scheduler) gapProgramAt(now time.Time) *ScheduledProgram {
+	if filler := s.fillerProgramAt(now); filler != nil {
+		return filler
+	}
+	if s.config.DefaultSource.Name != "" {
+		return s.defaultSourceToProgram()
+	}
+	return nil
+}
+
 // defaultSourceToProgram converts the DefaultSource config into a ScheduledProgram struct.
 func (s *Scheduler) defaultSourceToProgram() *ScheduledProgram {
 	// Assert dynamic types from config to concrete maps when possible.
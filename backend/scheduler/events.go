@@ -27,6 +27,15 @@ func (s *Scheduler) subscribeToEvents() {
 
 	unsub2, err2 := eventbus.Subscribe(s.bus, "Scheduler", s.handleCommitScheduleRequest)
 	s.addUnsubscriber(unsub2, err2, "CommitScheduleRequested")
+
+	unsub3, err3 := eventbus.Subscribe(s.bus, "Scheduler", s.handleAutoFillScheduleRequest)
+	s.addUnsubscriber(unsub3, err3, "AutoFillScheduleRequested")
+
+	unsub4, err4 := eventbus.Subscribe(s.bus, "Scheduler", s.handleOBSMediaEnded)
+	s.addUnsubscriber(unsub4, err4, "OBSMediaEnded")
+
+	unsub5, err5 := eventbus.Subscribe(s.bus, "Scheduler", s.handleExpandScheduleRequest)
+	s.addUnsubscriber(unsub5, err5, "ExpandScheduleRequested")
 }
 
 // addUnsubscriber is a helper to reduce boilerplate in the subscription process.
@@ -69,3 +78,28 @@ func (s *Scheduler) handleCommitScheduleRequest(event eventbus.CommitScheduleReq
 	s.logger.Debug("Handling CommitScheduleRequested event", "clientID", event.ClientID)
 	s.commitSchedule(event.ClientID, event.Payload)
 }
+
+// handleAutoFillScheduleRequest receives the event and calls the corresponding method.
+//
+// Topic: websocket.command.autoFillSchedule
+func (s *Scheduler) handleAutoFillScheduleRequest(event eventbus.AutoFillScheduleRequested) {
+	s.logger.Debug("Handling AutoFillScheduleRequested event", "clientID", event.ClientID)
+	s.autoFill(event.ClientID, event.Payload)
+}
+
+// handleExpandScheduleRequest receives the event and calls the corresponding method.
+//
+// Topic: websocket.command.expandSchedule
+func (s *Scheduler) handleExpandScheduleRequest(event eventbus.ExpandScheduleRequested) {
+	s.logger.Debug("Handling ExpandScheduleRequested event", "clientID", event.ClientID)
+	s.expandSchedule(event.ClientID, event.Payload)
+}
+
+// handleOBSMediaEnded advances rundown segments and ends programs early when
+// their media finishes playing.
+//
+// Topic: obs.media.ended
+func (s *Scheduler) handleOBSMediaEnded(event eventbus.OBSMediaEnded) {
+	s.logger.Debug("Handling OBSMediaEnded event", "programID", event.ProgramID)
+	s.mediaEnded(event.ProgramID, event.Timestamp)
+}
\ No newline at end of file
@@ -0,0 +1,260 @@
+// backend/scheduler/expansion.go
+//
+// Expansion of scheduled programs into concrete occurrences over a time range.
+//
+// Contents:
+// - Occurrence Type
+// - Occurrence Expansion
+// - Gap Detection
+// - Recurrence Helpers
+// - Expansion API
+
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"scenescheduler/backend/eventbus"
+)
+
+// ============================================================================
+// OCCURRENCE TYPE
+// ============================================================================
+
+// occurrence is a single concrete airing of a program, in local time.
+type occurrence struct {
+	Program *ScheduledProgram
+	Start   time.Time
+	End     time.Time
+}
+
+// timeRange is a half-open interval [Start, End) in local time.
+type timeRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// ============================================================================
+// OCCURRENCE EXPANSION
+// ============================================================================
+
+// expandOccurrences returns every occurrence of the enabled programs that
+// overlaps [from, to), sorted by start time. Recurring programs are expanded
+// with the same local-time template rules used by findProgramAtTime.
+func expandOccurrences(programs []ScheduledProgram, from, to time.Time) []occurrence {
+	fromLocal := from.Local()
+	toLocal := to.Local()
+	var result []occurrence
+
+	for i := range programs {
+		p := &programs[i]
+		if !p.Enabled || p.Timing.Start.IsZero() || p.Timing.End.IsZero() {
+			continue
+		}
+
+		if !p.Timing.IsRecurring {
+			start := p.Timing.Start.Local()
+			end := p.Timing.End.Local()
+			if start.Before(toLocal) && end.After(fromLocal) {
+				result = append(result, occurrence{Program: p, Start: start, End: end})
+			}
+			continue
+		}
+
+		// Start one day early to catch overnight occurrences spilling into the range.
+		day := time.Date(fromLocal.Year(), fromLocal.Month(), fromLocal.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, -1)
+		for ; day.Before(toLocal); day = day.AddDate(0, 0, 1) {
+			if !recurrenceActiveOn(p, day) {
+				continue
+			}
+			start, end := recurringBoundsOn(p, day)
+			if start.Before(toLocal) && end.After(fromLocal) {
+				result = append(result, occurrence{Program: p, Start: start, End: end})
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Start.Before(result[j].Start)
+	})
+	return result
+}
+
+// ============================================================================
+// GAP DETECTION
+// ============================================================================
+
+// findGaps returns the parts of [from, to) not covered by any occurrence.
+// The occurrences must be sorted by start time.
+func findGaps(occurrences []occurrence, from, to time.Time) []timeRange {
+	var gaps []timeRange
+	cursor := from.Local()
+	end := to.Local()
+
+	for _, occ := range occurrences {
+		if occ.Start.After(cursor) {
+			gapEnd := occ.Start
+			if gapEnd.After(end) {
+				gapEnd = end
+			}
+			if gapEnd.After(cursor) {
+				gaps = append(gaps, timeRange{Start: cursor, End: gapEnd})
+			}
+		}
+		if occ.End.After(cursor) {
+			cursor = occ.End
+		}
+		if !cursor.Before(end) {
+			return gaps
+		}
+	}
+
+	if cursor.Before(end) {
+		gaps = append(gaps, timeRange{Start: cursor, End: end})
+	}
+	return gaps
+}
+
+// ============================================================================
+// RECURRENCE HELPERS
+// ============================================================================
+
+// recurrenceActiveOn reports whether a recurring program has an occurrence
+// starting on the given local day.
+func recurrenceActiveOn(p *ScheduledProgram, day time.Time) bool {
+	dayStr := day.Format(dateFormat)
+	if p.Timing.Recurrence.StartRecur != "" && dayStr < p.Timing.Recurrence.StartRecur {
+		return false
+	}
+	if p.Timing.Recurrence.EndRecur != "" && dayStr > p.Timing.Recurrence.EndRecur {
+		return false
+	}
+	weekday := day.Weekday()
+	for _, dayName := range p.Timing.Recurrence.DaysOfWeek {
+		if mappedDay, ok := weekDaysMap[strings.ToUpper(dayName)]; ok && mappedDay == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// recurringBoundsOn builds the local start and end of a recurring program's
+// occurrence on the given day, handling overnight programs.
+func recurringBoundsOn(p *ScheduledProgram, day time.Time) (time.Time, time.Time) {
+	templateStart := p.Timing.Start
+	templateEnd := p.Timing.End
+	start := time.Date(day.Year(), day.Month(), day.Day(),
+		templateStart.Hour(), templateStart.Minute(), templateStart.Second(), 0, time.Local)
+	end := time.Date(day.Year(), day.Month(), day.Day(),
+		templateEnd.Hour(), templateEnd.Minute(), templateEnd.Second(), 0, time.Local)
+	if !end.After(start) {
+		end = end.Add(24 * time.Hour)
+	}
+	return start, end
+}
+
+// ============================================================================
+// EXPANSION API
+// ============================================================================
+
+// maxExpansionRange bounds the range a client may expand in one request.
+const maxExpansionRange = 31 * 24 * time.Hour
+
+// expandRequest is the payload of an expandSchedule request.
+type expandRequest struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// expandedItem is one entry of a scheduleExpansion response. Breaks are
+// listed as separate items on top of the program they interrupt.
+type expandedItem struct {
+	ProgramID string    `json:"programId"`
+	ParentID  string    `json:"parentId,omitempty"`
+	Title     string    `json:"title"`
+	Kind      string    `json:"kind"`
+	Track     string    `json:"track,omitempty"` // Parallel track the item belongs to (empty = main track)
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+}
+
+// expandSchedule sends the concrete occurrences of the loaded schedule over
+// the requested range, including breaks, so the calendar can show them.
+func (s *Scheduler) expandSchedule(clientID string, payload json.RawMessage) {
+	var req expandRequest
+	if err := json.Unmarshal(payload, &req); err != nil || req.From.IsZero() || !req.To.After(req.From) {
+		s.sendExpandError(clientID, "A valid 'from' and 'to' range is required")
+		return
+	}
+	if req.To.Sub(req.From) > maxExpansionRange {
+		s.sendExpandError(clientID, fmt.Sprintf("The range cannot exceed %d days", int(maxExpansionRange.Hours()/24)))
+		return
+	}
+
+	s.mu.RLock()
+	currentSchedule := s.resolved
+	s.mu.RUnlock()
+
+	items := make([]expandedItem, 0)
+	if currentSchedule != nil {
+		for _, occ := range expandOccurrences(currentSchedule.Programs, req.From, req.To) {
+			items = append(items, expandedItem{
+				ProgramID: occ.Program.ID,
+				Title:     occ.Program.Title,
+				Kind:      eventbus.ProgramKindScheduled,
+				Start:     occ.Start,
+				End:       occ.End,
+			})
+			if len(occ.Program.Rundown) > 0 {
+				continue
+			}
+			for _, w := range breakWindows(occ.Program, occ.Start, occ.End, s.config.BreakPools) {
+				items = append(items, expandedItem{
+					ProgramID: fmt.Sprintf("%s%s%d", occ.Program.ID, BreakIDMarker, w.Index),
+					ParentID:  occ.Program.ID,
+					Title:     fmt.Sprintf("Break (%s)", w.Rule.Pool),
+					Kind:      eventbus.ProgramKindBreak,
+					Start:     w.Start,
+					End:       w.End,
+				})
+			}
+		}
+		for _, track := range currentSchedule.Tracks {
+			for _, occ := range expandOccurrences(track.Programs, req.From, req.To) {
+				items = append(items, expandedItem{
+					ProgramID: occ.Program.ID,
+					Title:     occ.Program.Title,
+					Kind:      eventbus.ProgramKindScheduled,
+					Track:     track.ID,
+					Start:     occ.Start,
+					End:       occ.End,
+				})
+			}
+		}
+	}
+
+	eventbus.Publish(s.bus, eventbus.WebSocketSendMessageToClient{
+		ClientID:    clientID,
+		MessageType: "scheduleExpansion",
+		Payload: map[string]interface{}{
+			"from":  req.From,
+			"to":    req.To,
+			"items": items,
+		},
+	})
+}
+
+// sendExpandError sends an error response to the client if expansion fails.
+func (s *Scheduler) sendExpandError(clientID string, message string) {
+	eventbus.Publish(s.bus, eventbus.WebSocketSendMessageToClient{
+		ClientID:    clientID,
+		MessageType: "expandScheduleError",
+		Payload: map[string]interface{}{
+			"message": message,
+		},
+	})
+}
@@ -85,7 +85,7 @@ func (s *Scheduler) commitSchedule(clientID string, payload json.RawMessage) {
 
 	// NOTE: Do NOT call evaluateAndSwitch() here.
 	// The FileWatcher will detect the change and trigger reloadSchedule(),
-	// which will then call evaluateAndSwitch().
+	// which will then request an evaluation.
 }
 
 // ============================================================================
@@ -110,6 +110,7 @@ func (s *Scheduler) getSchedule(clientID string) {
 		MessageType: "currentSchedule",
 		Payload:     currentSchedule,
 	})
+	s.sendValidation(clientID)
 }
 
 // sendCommitSuccess sends a success response to the client after committing schedule.
@@ -0,0 +1,234 @@
+// backend/scheduler/filler.go
+//
+// Filler pool rotation for schedule gaps.
+//
+// Contents:
+// - Filler Rotation State
+// - Gap Evaluation
+// - Item Selection
+// - Program Conversion
+
+package scheduler
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"scenescheduler/backend/config"
+)
+
+// ============================================================================
+// FILLER ROTATION STATE
+// ============================================================================
+
+// fillerRotation tracks which filler item is on air and when every item was
+// last taken off air. Unlike the rest of the scheduler, rotation is stateful:
+// weights and repeat spacing can only be honored by remembering past choices.
+type fillerRotation struct {
+	mu        sync.Mutex
+	current   *config.FillerItem   // Item currently on air (nil outside gaps)
+	startedAt time.Time            // When the current item went on air
+	sequence  uint64               // Incremented for every new airing
+	lastOff   map[string]time.Time // Item name -> when it last left the air
+}
+
+// newFillerRotation creates an empty rotation state.
+func newFillerRotation() *fillerRotation {
+	return &fillerRotation{
+		lastOff: make(map[string]time.Time),
+	}
+}
+
+// ============================================================================
+// GAP EVALUATION
+// ============================================================================
+
+// fillerProgramAt returns the filler program that should be on air at `now`,
+// rotating to a new item when the current one has exhausted its maximum
+// duration. Returns nil if the pool is empty.
+func (s *Scheduler) fillerProgramAt(now time.Time) *ScheduledProgram {
+	items := s.config.FillerPool.Items
+	if len(items) == 0 {
+		return nil
+	}
+
+	r := s.filler
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.current != nil && !r.expired(now) && r.inPool(items) {
+		return r.toProgram()
+	}
+
+	next := r.pick(items, now)
+	if r.current != nil && r.current.Name == next.Name {
+		// Same item chosen again: keep it on air instead of recreating the
+		// input, which would restart the media and cause a visible glitch.
+		r.startedAt = now
+		return r.toProgram()
+	}
+
+	r.takeOffAir(now)
+	r.current = next
+	r.startedAt = now
+	r.sequence++
+
+	s.logger.Info("Rotating filler item on air", "item", next.Name, "sequence", r.sequence)
+	return r.toProgram()
+}
+
+// endFillerGap records that the gap has ended, taking the current item off air.
+func (s *Scheduler) endFillerGap(now time.Time) {
+	r := s.filler
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.takeOffAir(now)
+}
+
+// takeOffAir records the off-air time of the current item and clears it.
+// Must be called with r.mu held.
+func (r *fillerRotation) takeOffAir(now time.Time) {
+	if r.current == nil {
+		return
+	}
+	r.lastOff[r.current.Name] = now
+	r.current = nil
+}
+
+// expired reports whether the current item reached its maximum duration.
+// Must be called with r.mu held.
+func (r *fillerRotation) expired(now time.Time) bool {
+	if r.current.MaxDurationSeconds <= 0 {
+		return false
+	}
+	return now.Sub(r.startedAt) >= time.Duration(r.current.MaxDurationSeconds)*time.Second
+}
+
+// inPool reports whether the current item still exists in the configured pool.
+// Must be called with r.mu held.
+func (r *fillerRotation) inPool(items []config.FillerItem) bool {
+	for i := range items {
+		if items[i].Name == r.current.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// ============================================================================
+// ITEM SELECTION
+// ============================================================================
+
+// pick chooses the next item using weighted random selection among the items
+// whose repeat spacing has elapsed. The current item is only eligible again if
+// nothing else is. If no item is eligible, the one off air the longest wins.
+// Must be called with r.mu held.
+func (r *fillerRotation) pick(items []config.FillerItem, now time.Time) *config.FillerItem {
+	var eligible []*config.FillerItem
+	for i := range items {
+		item := &items[i]
+		if r.current != nil && item.Name == r.current.Name {
+			continue
+		}
+		if r.spacingElapsed(item, now) {
+			eligible = append(eligible, item)
+		}
+	}
+
+	if len(eligible) == 0 {
+		return r.leastRecent(items)
+	}
+
+	total := 0
+	for _, item := range eligible {
+		total += itemWeight(item)
+	}
+	n := rand.IntN(total)
+	for _, item := range eligible {
+		n -= itemWeight(item)
+		if n < 0 {
+			return item
+		}
+	}
+	return eligible[len(eligible)-1]
+}
+
+// spacingElapsed reports whether an item has been off air for at least its
+// minimum repeat spacing. Items that never aired are always eligible.
+func (r *fillerRotation) spacingElapsed(item *config.FillerItem, now time.Time) bool {
+	last, aired := r.lastOff[item.Name]
+	if !aired {
+		return true
+	}
+	return now.Sub(last) >= time.Duration(item.MinRepeatSeconds)*time.Second
+}
+
+// leastRecent returns the item that has been off air the longest, preferring
+// items other than the current one.
+func (r *fillerRotation) leastRecent(items []config.FillerItem) *config.FillerItem {
+	var best *config.FillerItem
+	for i := range items {
+		item := &items[i]
+		if r.current != nil && item.Name == r.current.Name && len(items) > 1 {
+			continue
+		}
+		if best == nil || r.lastOff[item.Name].Before(r.lastOff[best.Name]) {
+			best = item
+		}
+	}
+	return best
+}
+
+// itemWeight returns the effective selection weight of an item.
+func itemWeight(item *config.FillerItem) int {
+	if item.Weight <= 0 {
+		return 1
+	}
+	return item.Weight
+}
+
+// ============================================================================
+// PROGRAM CONVERSION
+// ============================================================================
+
+// toProgram converts the current filler item into a ScheduledProgram. The ID
+// carries the airing sequence so consecutive airings are distinct programs.
+// Must be called with r.mu held.
+func (r *fillerRotation) toProgram() *ScheduledProgram {
+	item := r.current
+
+	var inputSettings map[string]interface{}
+	if m, ok := item.InputSettings.(map[string]interface{}); ok {
+		inputSettings = m
+	}
+	var transform map[string]interface{}
+	if m, ok := item.Transform.(map[string]interface{}); ok {
+		transform = m
+	}
+
+	title := item.Title
+	if title == "" {
+		title = item.Name
+	}
+
+	timing := Timing{Start: r.startedAt}
+	if item.MaxDurationSeconds > 0 {
+		timing.End = r.startedAt.Add(time.Duration(item.MaxDurationSeconds) * time.Second)
+	}
+
+	return &ScheduledProgram{
+		ID:      fmt.Sprintf("%s%s-%d", FillerProgramIDPrefix, item.Name, r.sequence),
+		Title:   title,
+		Enabled: true,
+		Source: Source{
+			Name:          item.Name,
+			InputKind:     item.InputKind,
+			URI:           item.URI,
+			InputSettings: inputSettings,
+			Transform:     transform,
+		},
+		Timing: timing,
+	}
+}
@@ -15,6 +15,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"scenescheduler/backend/eventbus"
 )
 
 // ============================================================================
@@ -22,8 +24,13 @@ import (
 // ============================================================================
 
 const (
-	DefaultProgramID = "default-source"
-	NoProgramTitle   = "<none>"
+	DefaultProgramID        = "default-source"
+	FillerProgramIDPrefix   = "filler-"
+	AutoFillProgramIDPrefix = "autofill-"
+	SegmentIDSeparator      = "#"
+	BreakIDMarker           = "!break-"
+	TrackIDSeparator        = "@"
+	NoProgramTitle          = "<none>"
 
 	isoFormat  = "2006-01-02T15:04:05Z" // RFC3339 format for UTC
 	dateFormat = "2006-01-02"
@@ -225,6 +232,17 @@ func getProgramStartTime(p *ScheduledProgram, now time.Time) time.Time {
 	return p.Timing.Start.Local()
 }
 
+// getOccurrenceStart returns the start of the occurrence of a program that is
+// active at `now`. Unlike getProgramStartTime, it steps back one day for
+// recurring overnight programs that started yesterday.
+func getOccurrenceStart(p *ScheduledProgram, now time.Time) time.Time {
+	start := getProgramStartTime(p, now)
+	if p != nil && p.Timing.IsRecurring && start.After(now) {
+		start = start.AddDate(0, 0, -1)
+	}
+	return start
+}
+
 // getProgramEndTime returns the effective end time of a program for a given day in local time.
 func getProgramEndTime(p *ScheduledProgram, now time.Time) time.Time {
 	if p == nil || p.Timing.Start.IsZero() || p.Timing.End.IsZero() {
@@ -254,9 +272,47 @@ func getProgramEndTime(p *ScheduledProgram, now time.Time) time.Time {
 // MISCELLANEOUS HELPERS
 // ============================================================================
 
-// isDefaultSource returns true if the given program represents the default source.
+// isDefaultSource returns true if the given program represents the default
+// source, of the main schedule or of a track.
 func isDefaultSource(p *ScheduledProgram) bool {
-	return p != nil && p.ID == DefaultProgramID
+	return p != nil && (p.ID == DefaultProgramID || strings.HasPrefix(p.ID, DefaultProgramID+TrackIDSeparator))
+}
+
+// isFillerProgram returns true if the given program was rotated in from the filler pool.
+func isFillerProgram(p *ScheduledProgram) bool {
+	return p != nil && strings.HasPrefix(p.ID, FillerProgramIDPrefix)
+}
+
+// isGapProgram returns true if the program only covers a gap in the schedule
+// (the default source or a filler item) rather than a scheduled slot.
+func isGapProgram(p *ScheduledProgram) bool {
+	return isDefaultSource(p) || isFillerProgram(p)
+}
+
+// isSegmentProgram returns true if the program is a segment of a rundown block.
+func isSegmentProgram(p *ScheduledProgram) bool {
+	return p != nil && strings.Contains(p.ID, SegmentIDSeparator)
+}
+
+// isBreakProgram returns true if the program is an item aired during a break.
+func isBreakProgram(p *ScheduledProgram) bool {
+	return p != nil && strings.Contains(p.ID, BreakIDMarker)
+}
+
+// programKind classifies a program for the eventbus.Program contract.
+func programKind(p *ScheduledProgram) string {
+	switch {
+	case isDefaultSource(p):
+		return eventbus.ProgramKindDefault
+	case isFillerProgram(p):
+		return eventbus.ProgramKindFiller
+	case isSegmentProgram(p):
+		return eventbus.ProgramKindSegment
+	case isBreakProgram(p):
+		return eventbus.ProgramKindBreak
+	default:
+		return eventbus.ProgramKindScheduled
+	}
 }
 
 // getProgramTitle returns the title of a program, or a placeholder if nil.
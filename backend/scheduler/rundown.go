@@ -0,0 +1,249 @@
+// backend/scheduler/rundown.go
+//
+// Rundown blocks: ordered segments played inside a single program slot.
+//
+// Contents:
+// - Rundown Tracker State
+// - Segment Evaluation
+// - Media End Signals
+// - Position Publishing
+// - Segment Conversion
+
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"scenescheduler/backend/eventbus"
+)
+
+// ============================================================================
+// RUNDOWN TRACKER STATE
+// ============================================================================
+
+// rundownTracker remembers the live segment of the active rundown block.
+// Segments that end when their media finishes cannot be derived from the
+// clock alone, so, like the filler rotation, this part of the scheduler is
+// stateful.
+type rundownTracker struct {
+	mu           sync.Mutex
+	key          string    // Block ID + occurrence start; empty outside rundowns
+	index        int       // Index of the live segment (len(Rundown) once finished)
+	segmentStart time.Time // When the live segment went on air
+	mediaEnded   string    // Program ID reported by the last OBSMediaEnded event
+	mediaEndedAt time.Time // When that media ended
+
+	publishedKey   string // Position last published on the bus
+	publishedIndex int
+}
+
+// newRundownTracker creates an empty tracker.
+func newRundownTracker() *rundownTracker {
+	return &rundownTracker{}
+}
+
+// ============================================================================
+// SEGMENT EVALUATION
+// ============================================================================
+
+// rundownProgramAt returns the segment of the block that should be on air at
+// `now` and the segment that follows it. The current segment is nil once the
+// rundown has finished before the end of the slot; the remainder of the slot
+// is then handled as a gap.
+func (s *Scheduler) rundownProgramAt(block *ScheduledProgram, now time.Time) (*ScheduledProgram, *ScheduledProgram) {
+	r := s.rundown
+	r.mu.Lock()
+
+	blockStart := getOccurrenceStart(block, now)
+	key := fmt.Sprintf("%s@%d", block.ID, blockStart.Unix())
+	if key != r.key {
+		r.key = key
+		r.catchUp(block, blockStart, now)
+		s.logger.Info("Entering rundown block", "program", block.Title, "segment", r.index)
+	}
+	r.advance(block, now)
+
+	var current, next *ScheduledProgram
+	if r.index < len(block.Rundown) {
+		current = segmentProgram(block, r.index, r.segmentStart)
+		if r.index+1 < len(block.Rundown) {
+			next = segmentProgram(block, r.index+1, current.Timing.End)
+		}
+	}
+
+	changed := r.publishedKey != r.key || r.publishedIndex != r.index
+	r.publishedKey = r.key
+	r.publishedIndex = r.index
+	index := r.index
+	r.mu.Unlock()
+
+	if changed {
+		s.publishRundownPosition(block, index, current, next, now)
+	}
+	return current, next
+}
+
+// leaveRundown resets the tracker when no rundown block is scheduled and
+// tells clients that no rundown is live anymore.
+func (s *Scheduler) leaveRundown(now time.Time) {
+	r := s.rundown
+	r.mu.Lock()
+	wasActive := r.publishedKey != ""
+	r.key = ""
+	r.index = 0
+	r.mediaEnded = ""
+	r.publishedKey = ""
+	r.publishedIndex = 0
+	r.mu.Unlock()
+
+	if wasActive {
+		s.publishRundownPosition(nil, 0, nil, nil, now)
+	}
+}
+
+// catchUp positions the tracker when entering a block, for example after a
+// restart in the middle of a rundown. Segments are assumed to have run for
+// their nominal duration; a mediaEnd segment without a maximum duration is
+// assumed to be still playing. Must be called with r.mu held.
+func (r *rundownTracker) catchUp(block *ScheduledProgram, blockStart, now time.Time) {
+	r.index = 0
+	r.segmentStart = blockStart
+	r.mediaEnded = ""
+
+	for r.index < len(block.Rundown) {
+		duration := segmentDuration(&block.Rundown[r.index])
+		if duration <= 0 || now.Before(r.segmentStart.Add(duration)) {
+			return
+		}
+		r.segmentStart = r.segmentStart.Add(duration)
+		r.index++
+	}
+}
+
+// advance moves past every segment that has ended by `now`.
+// Must be called with r.mu held.
+func (r *rundownTracker) advance(block *ScheduledProgram, now time.Time) {
+	for r.index < len(block.Rundown) {
+		seg := &block.Rundown[r.index]
+		var nextStart time.Time
+
+		if duration := segmentDuration(seg); duration > 0 && !now.Before(r.segmentStart.Add(duration)) {
+			nextStart = r.segmentStart.Add(duration)
+		} else if seg.EndCondition == EndConditionMediaEnd && r.mediaEnded == segmentProgramID(block, r.index) {
+			nextStart = r.mediaEndedAt
+		} else {
+			return
+		}
+
+		r.index++
+		r.segmentStart = nextStart
+		r.mediaEnded = ""
+	}
+}
+
+// segmentDuration returns the nominal duration of a segment, or zero if it
+// only ends with its media.
+func segmentDuration(seg *Segment) time.Duration {
+	return time.Duration(seg.DurationSeconds) * time.Second
+}
+
+// ============================================================================
+// MEDIA END SIGNALS
+// ============================================================================
+
+// segmentMediaEnded records that the media of a live segment finished
+// playing. The segment advances on the next evaluation.
+func (r *rundownTracker) segmentMediaEnded(programID string, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mediaEnded = programID
+	r.mediaEndedAt = at
+}
+
+// ============================================================================
+// POSITION PUBLISHING
+// ============================================================================
+
+// publishRundownPosition announces the live segment of a rundown. A nil block
+// announces that no rundown is live.
+func (s *Scheduler) publishRundownPosition(block *ScheduledProgram, index int, current, next *ScheduledProgram, now time.Time) {
+	event := eventbus.RundownPositionChanged{Timestamp: now}
+	if block != nil {
+		event.ProgramID = block.ID
+		event.Title = block.Title
+		event.Active = current != nil
+		event.Index = index
+		event.Total = len(block.Rundown)
+		event.Current = toExecutableProgram(current)
+		event.Next = toExecutableProgram(next)
+	}
+
+	if event.Current != nil {
+		s.logger.InfoGui("Rundown segment live",
+			"program", block.Title,
+			"segment", event.Current.Title,
+			"position", fmt.Sprintf("%d/%d", index+1, event.Total))
+	}
+	eventbus.Publish(s.bus, event)
+}
+
+// ============================================================================
+// SEGMENT CONVERSION
+// ============================================================================
+
+// segmentProgramID builds the program ID of a segment: the block ID followed
+// by the segment ID, so every segment is a distinct program for the OBS client.
+func segmentProgramID(block *ScheduledProgram, index int) string {
+	segID := block.Rundown[index].ID
+	if segID == "" {
+		segID = fmt.Sprintf("seg-%d", index)
+	}
+	return block.ID + SegmentIDSeparator + segID
+}
+
+// segmentProgram converts a segment into a ScheduledProgram starting at
+// `start`. The end is left zero when the segment only ends with its media.
+func segmentProgram(block *ScheduledProgram, index int, start time.Time) *ScheduledProgram {
+	seg := &block.Rundown[index]
+
+	title := seg.Title
+	if title == "" {
+		title = fmt.Sprintf("%s (%d/%d)", block.Title, index+1, len(block.Rundown))
+	}
+
+	timing := Timing{Start: start}
+	if duration := segmentDuration(seg); duration > 0 && !start.IsZero() {
+		timing.End = start.Add(duration)
+	}
+
+	return &ScheduledProgram{
+		ID:       segmentProgramID(block, index),
+		Title:    title,
+		Enabled:  true,
+		General:  block.General,
+		Source:   seg.Source,
+		Timing:   timing,
+		Behavior: segmentBehavior(block, index),
+		parentID: block.ID,
+	}
+}
+
+// segmentBehavior returns the behavior a segment inherits from its block:
+// OnEndAction, PreloadSeconds, Fallback and ExpectSilence. The first segment
+// also inherits the Transition, so the block comes on air as configured;
+// later segments use the default transition. Breaks, actions and the end
+// condition apply to the block as a whole and are not inherited.
+func segmentBehavior(block *ScheduledProgram, index int) Behavior {
+	behavior := Behavior{
+		OnEndAction:    block.Behavior.OnEndAction,
+		PreloadSeconds: block.Behavior.PreloadSeconds,
+		Fallback:       block.Behavior.Fallback,
+		ExpectSilence:  block.Behavior.ExpectSilence,
+	}
+	if index == 0 {
+		behavior.Transition = block.Behavior.Transition
+	}
+	return behavior
+}
@@ -21,7 +21,8 @@ import (
 // until the context is canceled or Stop() is called.
 // The context for this module already exists from the constructor.
 //
-// The evaluation loop runs every second to check if the target program has changed.
+// The evaluation loop runs every second to check if the target program has
+// changed, and immediately when another goroutine calls requestEvaluation.
 func (s *Scheduler) Run() {
 	defer s.cleanup()
 
@@ -46,6 +47,9 @@ func (s *Scheduler) Run() {
 		case <-ticker.C:
 			s.evaluateAndSwitch()
 
+		case <-s.evaluateNow:
+			s.evaluateAndSwitch()
+
 		case <-s.ctx.Done():
 			s.logger.InfoGui("Scheduler context canceled, stopping")
 			return
@@ -23,14 +23,20 @@ func (s *Scheduler) reloadSchedule() {
 		return
 	}
 
+	// Resolve chained programs before the schedule becomes visible to evaluation.
+	resolved, issues := validateSchedule(newSchedule, s.config.BreakPools)
+
 	s.mu.Lock()
 	s.schedule = newSchedule
+	s.resolved = resolved
 	s.mu.Unlock()
 
+	s.reportValidation(issues)
+
 	s.logger.InfoGui("Successfully reloaded schedule into memory", "program_count", len(newSchedule.Programs))
 
 	// Always trigger an immediate evaluation after reload
-	s.evaluateAndSwitch()
+	s.requestEvaluation()
 }
 
 // ============================================================================
@@ -40,6 +46,10 @@ func (s *Scheduler) reloadSchedule() {
 // setupDefaultSource prepares the default source from configuration.
 // This is called once during startup in Run().
 func (s *Scheduler) setupDefaultSource() {
+	if n := len(s.config.FillerPool.Items); n > 0 {
+		s.logger.Debug("Filler pool configured, it takes precedence over the default source", "items", n)
+	}
+
 	if s.config.DefaultSource.Name == "" {
 		s.logger.Debug("No default source configured")
 		return
@@ -0,0 +1,109 @@
+// backend/scheduler/tracks.go
+//
+// Parallel tracks: independent lists of programs evaluated alongside the main
+// schedule on every tick, each published as its own target state.
+//
+// Contents:
+// - Track Evaluation
+// - Track Helpers
+
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"scenescheduler/backend/eventbus"
+)
+
+// ============================================================================
+// TRACK EVALUATION
+// ============================================================================
+
+// evaluateTracks publishes the desired state of every track of the schedule.
+// Like the main evaluation it is stateless: each track's target is a pure
+// function of its programs and the current time.
+func (s *Scheduler) evaluateTracks(schedule *Schedule, now time.Time) {
+	if schedule == nil {
+		return
+	}
+
+	seen := make(map[string]bool, len(schedule.Tracks))
+	for i := range schedule.Tracks {
+		track := &schedule.Tracks[i]
+		// Tracks without a unique ID are reported by validation and skipped
+		if track.ID == "" || seen[track.ID] {
+			continue
+		}
+		seen[track.ID] = true
+
+		targetProgram := findProgramAtTime(track.Programs, now)
+
+		var seekOffset time.Duration
+		searchStartTime := now
+		if targetProgram != nil {
+			startTime := getProgramStartTime(targetProgram, now)
+			if now.After(startTime) {
+				seekOffset = now.Sub(startTime)
+			}
+			searchStartTime = getProgramEndTime(targetProgram, now)
+		} else {
+			targetProgram = trackDefaultProgram(track)
+		}
+
+		var nextProgram *ScheduledProgram
+		if !searchStartTime.IsZero() {
+			nextProgram = findNextProgramAfter(track.Programs, searchStartTime)
+		}
+
+		eventbus.Publish(s.bus, eventbus.TrackTargetState{
+			Timestamp:     now,
+			TrackID:       track.ID,
+			SceneName:     track.SceneName,
+			ZIndex:        track.ZIndex,
+			TargetProgram: toExecutableProgram(targetProgram),
+			NextProgram:   toExecutableProgram(nextProgram),
+			SeekOffset:    seekOffset,
+		})
+	}
+}
+
+// ============================================================================
+// TRACK HELPERS
+// ============================================================================
+
+// trackDefaultProgram converts a track's default source into a program.
+// Returns nil if the track has none.
+func trackDefaultProgram(track *Track) *ScheduledProgram {
+	if track.DefaultSource == nil || track.DefaultSource.Name == "" {
+		return nil
+	}
+	return &ScheduledProgram{
+		ID:      DefaultProgramID + TrackIDSeparator + track.ID,
+		Title:   fmt.Sprintf("Default Source (%s)", trackTitle(track)),
+		Enabled: true,
+		Source:  *track.DefaultSource,
+	}
+}
+
+// trackIDs lists the IDs of the tracks of a schedule.
+func trackIDs(schedule *Schedule) []string {
+	if schedule == nil || len(schedule.Tracks) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(schedule.Tracks))
+	for _, track := range schedule.Tracks {
+		if track.ID != "" {
+			ids = append(ids, track.ID)
+		}
+	}
+	return ids
+}
+
+// trackTitle returns the title of a track, or its ID if it has none.
+func trackTitle(track *Track) string {
+	if track.Title != "" {
+		return track.Title
+	}
+	return track.ID
+}
@@ -7,6 +7,7 @@
 // - Program Types
 // - Timing and Recurrence Types
 // - Behavior Types
+// - Rundown Types
 
 package scheduler
 
@@ -19,9 +20,48 @@ import "time"
 // Schedule is the root object representing a full scheduling configuration.
 // It maps directly to the schedule.json file.
 type Schedule struct {
-	Version      string             `json:"version"`      // Schema version
-	ScheduleName string             `json:"scheduleName"` // Human-readable name of the schedule
-	Programs     []ScheduledProgram `json:"schedule"`     // List of programs (events)
+	Version      string             `json:"version"`          // Schema version
+	ScheduleName string             `json:"scheduleName"`     // Human-readable name of the schedule
+	Programs     []ScheduledProgram `json:"schedule"`         // List of programs (events)
+	Tracks       []Track            `json:"tracks,omitempty"` // Parallel tracks evaluated alongside the main schedule
+	Events       []TimelineEvent    `json:"events,omitempty"` // Standalone OBS actions, independent of any program
+}
+
+// Track is an independent layer of programming evaluated in parallel with the
+// main schedule, such as a music bed running across several video programs or
+// a logo or ticker overlay with its own timing. Track programs support plain
+// timing, layers and transitions; rundowns, breaks and filler are main-track
+// features.
+type Track struct {
+	ID            string             `json:"id"`                      // Unique identifier of the track
+	Title         string             `json:"title"`                   // Human-readable title displayed in UI
+	SceneName     string             `json:"sceneName,omitempty"`     // Sub-scene nested in the schedule scene (empty = derived from the ID)
+	ZIndex        int                `json:"zIndex"`                  // Stacking relative to the main track (0); higher is on top
+	DefaultSource *Source            `json:"defaultSource,omitempty"` // Airs on the track when none of its programs is active
+	Programs      []ScheduledProgram `json:"schedule"`                // Programs of the track
+}
+
+// TimelineEvent is a standalone OBS action at a given time, such as "start
+// streaming at 08:55 every weekday", or a macro of arbitrary obs-websocket
+// requests. Only Timing.Start and the recurrence fields are used.
+type TimelineEvent struct {
+	ID       string      `json:"id"`                 // Unique identifier of the event
+	Title    string      `json:"title"`              // Human-readable title displayed in UI
+	Enabled  bool        `json:"enabled"`            // Whether the event fires
+	Action   string      `json:"action"`             // One of the eventbus.OBSAction* constants
+	Filename string      `json:"filename,omitempty"` // Recording filename for start and split actions (empty = keep the OBS setting)
+	Steps    []MacroStep `json:"steps,omitempty"`    // Requests sent in order by a "macro" event
+	Timing   Timing      `json:"timing"`             // When the action fires
+}
+
+// MacroStep is one obs-websocket request of a macro event, such as
+// triggering a hotkey, switching the profile or calling a plugin's vendor
+// request.
+type MacroStep struct {
+	RequestType string                 `json:"requestType"`           // obs-websocket request name, e.g. "TriggerHotkeyByName"
+	RequestData map[string]interface{} `json:"requestData,omitempty"` // Request fields as documented by obs-websocket
+	OnError     string                 `json:"onError,omitempty"`     // "stop" (default) aborts the macro, "continue" goes on
+	DelayMs     int                    `json:"delayMs,omitempty"`     // Wait before the step is sent
 }
 
 // ============================================================================
@@ -31,13 +71,17 @@ type Schedule struct {
 // ScheduledProgram defines a single scheduled event, including metadata, source, timing,
 // and behavior configuration. This is the internal domain model for the scheduler.
 type ScheduledProgram struct {
-	ID       string   `json:"id"`      // Unique identifier for the program
-	Title    string   `json:"title"`   // Human-readable title displayed in UI
-	Enabled  bool     `json:"enabled"` // Whether the program is active and should be scheduled
-	General  General  `json:"general"` // Visual metadata for frontend display
-	Source   Source   `json:"source"`  // OBS input source configuration
-	Timing   Timing   `json:"timing"`  // Scheduling details
-	Behavior Behavior `json:"behavior"`// Runtime behavior at start/end
+	ID       string    `json:"id"`                // Unique identifier for the program
+	Title    string    `json:"title"`             // Human-readable title displayed in UI
+	Enabled  bool      `json:"enabled"`           // Whether the program is active and should be scheduled
+	General  General   `json:"general"`           // Visual metadata for frontend display
+	Source   Source    `json:"source"`            // OBS input source configuration
+	Timing   Timing    `json:"timing"`            // Scheduling details
+	Behavior Behavior  `json:"behavior"`          // Runtime behavior at start/end
+	Rundown  []Segment `json:"rundown,omitempty"` // Ordered segments played inside the slot
+	Layers   []Layer   `json:"layers,omitempty"`  // Additional sources composited with Source
+
+	parentID string // Set on programs derived from another one (segments, break items)
 }
 
 // General stores metadata for program visualization in the frontend calendar.
@@ -52,11 +96,32 @@ type General struct {
 
 // Source defines an OBS input that should be activated during the program.
 type Source struct {
-	Name          string                 `json:"name"`          // Technical input name in OBS
-	InputKind     string                 `json:"inputKind"`     // OBS input type (e.g. browser_source, ffmpeg_source)
-	URI           string                 `json:"uri"`           // Path or URL for the source
-	InputSettings map[string]interface{} `json:"inputSettings"` // Type-specific OBS input settings
-	Transform     map[string]interface{} `json:"transform"`     // Transform properties (position, size, crop)
+	Name          string                 `json:"name"`              // Technical input name in OBS
+	InputKind     string                 `json:"inputKind"`         // OBS input type (e.g. browser_source, ffmpeg_source)
+	URI           string                 `json:"uri"`               // Path or URL for the source
+	InputSettings map[string]interface{} `json:"inputSettings"`     // Type-specific OBS input settings
+	Transform     map[string]interface{} `json:"transform"`         // Transform properties (position, size, crop)
+	Audio         *Audio                 `json:"audio,omitempty"`   // Audio settings applied when the input is created
+	Filters       []Filter               `json:"filters,omitempty"` // OBS filters added to the input when it is created
+
+	MediaDurationMs int64 `json:"mediaDurationMs,omitempty"` // Media length discovered with probeMediaDuration
+
+	// Captions is an SRT or WebVTT file whose cues are sent as stream
+	// captions while the program is on air, timed from the start of the media.
+	Captions string `json:"captions,omitempty"`
+
+	// SceneName targets an existing, operator-built OBS scene instead of
+	// creating an input. The scene is never modified or removed.
+	SceneName string `json:"sceneName,omitempty"`
+	SceneMode string `json:"sceneMode,omitempty"` // "nested" (default) inside the schedule scene, or "program" to make it the program scene
+}
+
+// Layer is an additional source composited with the program's main source,
+// such as a logo bug or a lower third. Layers are stacked by ZIndex: the main
+// source sits at 0, higher values are on top, and ties keep list order.
+type Layer struct {
+	Source
+	ZIndex int `json:"zIndex,omitempty"`
 }
 
 // ============================================================================
@@ -64,11 +129,26 @@ type Source struct {
 // ============================================================================
 
 // Timing defines when the program should run, either once or recurrently.
+//
+// Instead of an absolute Start, a program may be chained: After references a
+// predecessor (start when it ends) and Anchor references a block (start when
+// it starts). Chained programs are resolved into absolute Start/End values
+// when the schedule is loaded, see chaining.go.
 type Timing struct {
 	Start       time.Time  `json:"start"`       // ISO 8601 format for single events
 	End         time.Time  `json:"end"`         // ISO 8601 format for single events
 	IsRecurring bool       `json:"isRecurring"` // Whether the program repeats
 	Recurrence  Recurrence `json:"recurrence"`  // Recurrence rule if repeating
+
+	After           string `json:"after,omitempty"`           // ID of the predecessor program
+	Anchor          string `json:"anchor,omitempty"`          // ID of the anchor block program
+	OffsetSeconds   int    `json:"offsetSeconds,omitempty"`   // Offset applied to the resolved start
+	DurationSeconds int    `json:"durationSeconds,omitempty"` // Duration of a chained program
+}
+
+// IsChained reports whether the start time is relative to another program.
+func (t *Timing) IsChained() bool {
+	return t.After != "" || t.Anchor != ""
 }
 
 // Recurrence defines the rule for repeating programs.
@@ -82,8 +162,79 @@ type Recurrence struct {
 // BEHAVIOR TYPES
 // ============================================================================
 
+// End conditions for programs and rundown segments.
+const (
+	EndConditionDuration = "duration" // Ends at the scheduled end (or after DurationSeconds for segments)
+	EndConditionMediaEnd = "mediaEnd" // Ends early when OBS reports the media finished playing
+)
+
 // Behavior defines how the program should behave during and after execution.
 type Behavior struct {
-	OnEndAction    string `json:"onEndAction"`    // Action after program ends (hide, none, stop)
-	PreloadSeconds int    `json:"preloadSeconds"` // Seconds before start to preload the source
-}
\ No newline at end of file
+	OnEndAction    string          `json:"onEndAction"`             // Action after program ends (hide, none, stop)
+	PreloadSeconds int             `json:"preloadSeconds"`          // Seconds before start to preload the source
+	EndCondition   string          `json:"endCondition,omitempty"`  // "duration" (default) or "mediaEnd"; the slot end is always a hard cap
+	Breaks         []BreakRule     `json:"breaks,omitempty"`        // Breaks interrupting the program
+	Transition     *Transition     `json:"transition,omitempty"`    // How the program is brought on air (nil = configured default)
+	Actions        []ProgramAction `json:"actions,omitempty"`       // OBS output actions tied to the program
+	Fallback       *Source         `json:"fallback,omitempty"`      // Shown while the live source is down (nil = the default source)
+	ExpectSilence  bool            `json:"expectSilence,omitempty"` // The program has no audio, so silence is not reported
+}
+
+// ProgramAction is an OBS output action tied to the start or end of a
+// program, e.g. "start recording when the program starts".
+type ProgramAction struct {
+	Action        string `json:"action"`                  // One of the eventbus.OBSAction* constants
+	At            string `json:"at,omitempty"`            // "start" (default) or "end" of the program
+	OffsetSeconds int    `json:"offsetSeconds,omitempty"` // Shift relative to At; negative is earlier (at most one hour)
+	Filename      string `json:"filename,omitempty"`      // Recording filename for start and split actions (empty = program title)
+}
+
+// Transition describes how a program replaces the one before it.
+type Transition struct {
+	Type           string `json:"type"`                     // "cut", "fade" or "stinger"
+	DurationMs     int    `json:"durationMs,omitempty"`     // Length of the fade, or of the stinger clip
+	StingerURI     string `json:"stingerUri,omitempty"`     // Stinger clip (empty = configured default)
+	StingerPointMs int    `json:"stingerPointMs,omitempty"` // Point in the stinger clip where the cut happens
+	AudioFadeMs    int    `json:"audioFadeMs,omitempty"`    // Audio crossfade between the programs (0 = configured default)
+}
+
+// Audio holds per-program audio settings, so programs mastered at different
+// levels air at a consistent loudness.
+type Audio struct {
+	VolumeDb    *float64 `json:"volumeDb,omitempty"`    // Input volume in dB, 0 is unity (nil = OBS default)
+	Muted       bool     `json:"muted,omitempty"`       // Air the program without sound
+	MonitorType string   `json:"monitorType,omitempty"` // "none", "monitorOnly" or "monitorAndOutput" (empty = OBS default)
+	Balance     *float64 `json:"balance,omitempty"`     // 0.0 (left) to 1.0 (right), 0.5 is centered (nil = OBS default)
+}
+
+// Filter is an OBS source filter, such as a color correction, chroma key,
+// crop or sharpen filter. Inputs are recreated for every program, so the
+// filters are part of the program rather than of the OBS scene collection.
+type Filter struct {
+	Kind     string                 `json:"kind"`               // OBS filter kind (e.g. color_filter_v2, chroma_key_filter_v2, crop_filter, sharpness_filter_v2)
+	Name     string                 `json:"name"`               // Filter name, unique within the source
+	Settings map[string]interface{} `json:"settings,omitempty"` // Kind-specific filter settings (empty = OBS defaults)
+}
+
+// BreakRule inserts a break after every IntervalMinutes of program content,
+// e.g. "every 20 minutes insert 2 minutes from the promo pool". The program
+// resumes where it left off after each break.
+type BreakRule struct {
+	IntervalMinutes int    `json:"intervalMinutes"` // Program content between breaks
+	DurationSeconds int    `json:"durationSeconds"` // Length of each break
+	Pool            string `json:"pool"`            // Name of a pool in scheduler.breakPools
+}
+
+// ============================================================================
+// RUNDOWN TYPES
+// ============================================================================
+
+// Segment is one entry of a program's rundown. Segments play in order inside
+// the program's slot; the slot end always cuts the rundown short.
+type Segment struct {
+	ID              string `json:"id"`              // Identifier, unique within the rundown
+	Title           string `json:"title"`           // Human-readable title displayed in UI
+	Source          Source `json:"source"`          // OBS input source configuration
+	DurationSeconds int    `json:"durationSeconds"` // Duration, or maximum duration for mediaEnd segments
+	EndCondition    string `json:"endCondition"`    // "duration" (default) or "mediaEnd"
+}
@@ -0,0 +1,687 @@
+// backend/scheduler/validation.go
+//
+// Schedule validation performed when a schedule is loaded.
+//
+// Contents:
+// - Validation Types
+// - Schedule Validation
+// - Issue Reporting
+
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"scenescheduler/backend/config"
+	"scenescheduler/backend/eventbus"
+)
+
+// ============================================================================
+// VALIDATION TYPES
+// ============================================================================
+
+// maxVolumeDb is the highest input volume OBS accepts.
+const maxVolumeDb = 26.0
+
+// Severity levels for validation issues.
+const (
+	SeverityError   = "error"   // The program cannot be scheduled as written
+	SeverityWarning = "warning" // The program will air, but likely not as intended
+)
+
+// ValidationIssue describes a problem found in a loaded schedule.
+type ValidationIssue struct {
+	ProgramID string `json:"programId"`
+	Severity  string `json:"severity"`
+	Message   string `json:"message"`
+}
+
+// ============================================================================
+// SCHEDULE VALIDATION
+// ============================================================================
+
+// validateSchedule checks a freshly loaded schedule and returns the copy of it
+// used for evaluation, with chained programs resolved. The loaded schedule is
+// left as stored in schedule.json, so it can be sent to clients and saved
+// back unchanged. It never rejects the schedule: programs with errors are
+// disabled in the copy and the rest of the schedule stays usable.
+func validateSchedule(schedule *Schedule, breakPools map[string][]config.FillerItem) (*Schedule, []ValidationIssue) {
+	var issues []ValidationIssue
+	schedule = evaluationCopy(schedule)
+
+	seen := make(map[string]bool, len(schedule.Programs))
+	for i := range schedule.Programs {
+		p := &schedule.Programs[i]
+		if p.ID == "" {
+			continue
+		}
+		if issue, reserved := reservedIDIssue(p); reserved {
+			issues = append(issues, issue)
+			p.Enabled = false
+		}
+		if seen[p.ID] {
+			issues = append(issues, ValidationIssue{
+				ProgramID: p.ID,
+				Severity:  SeverityWarning,
+				Message:   "duplicate program ID, references resolve to the first program with this ID",
+			})
+		}
+		seen[p.ID] = true
+	}
+
+	issues = append(issues, resolveChains(schedule.Programs)...)
+	for i := range schedule.Programs {
+		p := &schedule.Programs[i]
+		switch p.Behavior.EndCondition {
+		case "", EndConditionDuration, EndConditionMediaEnd:
+		default:
+			issues = append(issues, ValidationIssue{
+				ProgramID: p.ID,
+				Severity:  SeverityWarning,
+				Message:   fmt.Sprintf("unknown endCondition %q, the program will run until its scheduled end", p.Behavior.EndCondition),
+			})
+		}
+		issues = append(issues, validateRundown(p)...)
+		issues = append(issues, validateMediaLength(p)...)
+		issues = append(issues, validateBreaks(p, breakPools)...)
+		issues = append(issues, validateTransition(p)...)
+		issues = append(issues, validateLayers(p)...)
+		issues = append(issues, validateScene(p)...)
+		issues = append(issues, validateActions(p)...)
+		issues = append(issues, validateAudio(p)...)
+		issues = append(issues, validateFilters(p)...)
+		issues = append(issues, validateCaptions(p)...)
+		issues = append(issues, validateFallback(p)...)
+	}
+	issues = append(issues, validateTracks(schedule.Tracks)...)
+	issues = append(issues, validateEvents(schedule.Events)...)
+	return schedule, issues
+}
+
+// reservedIDIssue reports a program ID that contains one of the markers the
+// scheduler uses to tell generated programs apart (filler items, rundown
+// segments, break items, track default sources). Such a program would be
+// mistaken for a generated one, so it is not scheduled. Auto-fill IDs are
+// not reserved: accepted drafts are saved with them.
+func reservedIDIssue(p *ScheduledProgram) (ValidationIssue, bool) {
+	var marker string
+	switch {
+	case p.ID == DefaultProgramID || strings.HasPrefix(p.ID, DefaultProgramID+TrackIDSeparator):
+		marker = DefaultProgramID
+	case strings.HasPrefix(p.ID, FillerProgramIDPrefix):
+		marker = FillerProgramIDPrefix
+	case strings.Contains(p.ID, SegmentIDSeparator):
+		marker = SegmentIDSeparator
+	case strings.Contains(p.ID, BreakIDMarker):
+		marker = BreakIDMarker
+	case strings.Contains(p.ID, TrackIDSeparator):
+		marker = TrackIDSeparator
+	default:
+		return ValidationIssue{}, false
+	}
+	return ValidationIssue{
+		ProgramID: p.ID,
+		Severity:  SeverityError,
+		Message:   fmt.Sprintf("program ID contains the reserved marker %q and will not be scheduled", marker),
+	}, true
+}
+
+// evaluationCopy returns a copy of a schedule whose programs can be resolved
+// and disabled without touching the original. Nested values such as rundowns
+// and layers are shared and must not be modified.
+func evaluationCopy(schedule *Schedule) *Schedule {
+	c := *schedule
+	c.Programs = slices.Clone(schedule.Programs)
+	c.Tracks = slices.Clone(schedule.Tracks)
+	for i := range c.Tracks {
+		c.Tracks[i].Programs = slices.Clone(schedule.Tracks[i].Programs)
+	}
+	return &c
+}
+
+// validateRundown checks the segments of a rundown block. A segment that can
+// never end holds the air until the end of the slot.
+func validateRundown(p *ScheduledProgram) []ValidationIssue {
+	var issues []ValidationIssue
+	warn := func(format string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{
+			ProgramID: p.ID,
+			Severity:  SeverityWarning,
+			Message:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	ids := make(map[string]bool, len(p.Rundown))
+	for i, seg := range p.Rundown {
+		if seg.ID != "" && ids[seg.ID] {
+			warn("rundown segment %d: duplicate segment ID %q", i, seg.ID)
+		}
+		ids[seg.ID] = true
+
+		switch seg.EndCondition {
+		case "", EndConditionDuration:
+			if seg.DurationSeconds <= 0 {
+				warn("rundown segment %d: a positive durationSeconds is required", i)
+			}
+		case EndConditionMediaEnd:
+		default:
+			warn("rundown segment %d: unknown endCondition %q", i, seg.EndCondition)
+		}
+		if seg.Source.Name == "" || seg.Source.InputKind == "" {
+			warn("rundown segment %d: source name and inputKind are required", i)
+		}
+	}
+	return issues
+}
+
+// ============================================================================
+// ISSUE REPORTING
+// ============================================================================
+
+// reportValidation logs the issues for the operator, stores them for clients
+// that connect later, and broadcasts them to all connected web clients. The
+// broadcast is sent even when there are no issues so clients clear old ones.
+func (s *Scheduler) reportValidation(issues []ValidationIssue) {
+	for _, issue := range issues {
+		msg := fmt.Sprintf("Schedule validation: %s", issue.Message)
+		if issue.Severity == SeverityError {
+			s.logger.ErrorGui(msg, "programID", issue.ProgramID)
+		} else {
+			s.logger.WarnGui(msg, "programID", issue.ProgramID)
+		}
+	}
+
+	s.mu.Lock()
+	s.validationIssues = issues
+	s.mu.Unlock()
+
+	payload, err := json.Marshal(validationPayload(issues))
+	if err != nil {
+		s.logger.Error("Failed to marshal validation issues", "error", err)
+		return
+	}
+	eventbus.Publish(s.bus, eventbus.WebSocketBroadcastMessage{
+		MessageType: "scheduleValidation",
+		Payload:     payload,
+	})
+}
+
+// sendValidation sends the current validation issues to a single client.
+func (s *Scheduler) sendValidation(clientID string) {
+	s.mu.RLock()
+	issues := s.validationIssues
+	s.mu.RUnlock()
+
+	eventbus.Publish(s.bus, eventbus.WebSocketSendMessageToClient{
+		ClientID:    clientID,
+		MessageType: "scheduleValidation",
+		Payload:     validationPayload(issues),
+	})
+}
+
+// validationPayload builds the message body shared by broadcast and unicast.
+func validationPayload(issues []ValidationIssue) map[string]interface{} {
+	if issues == nil {
+		issues = make([]ValidationIssue, 0)
+	}
+	return map[string]interface{}{
+		"issues": issues,
+	}
+}
+
+// validateMediaLength warns when a slot is shorter than its known media
+// duration, so the end of the media would be cut off.
+func validateMediaLength(p *ScheduledProgram) []ValidationIssue {
+	var issues []ValidationIssue
+	check := func(what string, media int64, slot time.Duration) {
+		mediaDuration := time.Duration(media) * time.Millisecond
+		if media <= 0 || slot <= 0 || slot >= mediaDuration {
+			return
+		}
+		issues = append(issues, ValidationIssue{
+			ProgramID: p.ID,
+			Severity:  SeverityWarning,
+			Message: fmt.Sprintf("%s is shorter than its media (%s < %s), the end will be cut off",
+				what, slot.Round(time.Second), mediaDuration.Round(time.Second)),
+		})
+	}
+
+	if len(p.Rundown) == 0 && !p.Timing.Start.IsZero() && !p.Timing.End.IsZero() {
+		start := getProgramStartTime(p, p.Timing.Start)
+		check("slot", p.Source.MediaDurationMs, getProgramEndTime(p, p.Timing.Start).Sub(start))
+	}
+	for i, seg := range p.Rundown {
+		check(fmt.Sprintf("rundown segment %d", i), seg.Source.MediaDurationMs, segmentDuration(&seg))
+	}
+	return issues
+}
+
+// validateBreaks checks break rules against the configured break pools.
+func validateBreaks(p *ScheduledProgram, breakPools map[string][]config.FillerItem) []ValidationIssue {
+	var issues []ValidationIssue
+	warn := func(format string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{
+			ProgramID: p.ID,
+			Severity:  SeverityWarning,
+			Message:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	if len(p.Behavior.Breaks) > 0 && len(p.Rundown) > 0 {
+		warn("breaks are ignored on rundown blocks")
+	}
+	for i, rule := range p.Behavior.Breaks {
+		if rule.IntervalMinutes <= 0 || rule.DurationSeconds <= 0 {
+			warn("break rule %d: intervalMinutes and durationSeconds must be positive", i)
+		}
+		if len(breakPools[rule.Pool]) == 0 {
+			warn("break rule %d: break pool %q is not configured or empty, the program will not be interrupted", i, rule.Pool)
+		}
+	}
+	return issues
+}
+
+// validateTransition checks a program's own transition. The switcher falls
+// back to a cut for anything it cannot perform, so problems are warnings.
+func validateTransition(p *ScheduledProgram) []ValidationIssue {
+	t := p.Behavior.Transition
+	if t == nil {
+		return nil
+	}
+
+	var message string
+	switch {
+	case t.Type != eventbus.TransitionCut && t.Type != eventbus.TransitionFade && t.Type != eventbus.TransitionStinger:
+		message = fmt.Sprintf("unknown transition type %q, the program will cut in", t.Type)
+	case t.DurationMs < 0 || t.StingerPointMs < 0 || t.AudioFadeMs < 0:
+		message = "transition durationMs, stingerPointMs and audioFadeMs cannot be negative"
+	case t.Type == eventbus.TransitionStinger && t.DurationMs > 0 && t.StingerPointMs > t.DurationMs:
+		message = "transition stingerPointMs is past the end of the stinger"
+	default:
+		return nil
+	}
+	return []ValidationIssue{{ProgramID: p.ID, Severity: SeverityWarning, Message: message}}
+}
+
+// validateLayers checks that every layer can be created alongside the main
+// source. Each layer needs its own input name, since inputs are created and
+// removed by name.
+func validateLayers(p *ScheduledProgram) []ValidationIssue {
+	var issues []ValidationIssue
+	fail := func(format string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{
+			ProgramID: p.ID,
+			Severity:  SeverityError,
+			Message:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	if len(p.Layers) > 0 && len(p.Rundown) > 0 {
+		issues = append(issues, ValidationIssue{
+			ProgramID: p.ID,
+			Severity:  SeverityWarning,
+			Message:   "layers are ignored on rundown blocks",
+		})
+	}
+
+	names := map[string]bool{sourceKey(&p.Source): true}
+	for i, layer := range p.Layers {
+		if layer.SceneName == "" && (layer.Name == "" || layer.InputKind == "") {
+			fail("layer %d: name and inputKind, or sceneName, are required", i)
+			continue
+		}
+		if layer.SceneMode == eventbus.SceneModeProgram {
+			fail("layer %d: only the main source can use sceneMode \"program\"", i)
+		}
+		key := sourceKey(&layer.Source)
+		if names[key] {
+			fail("layer %d: %q is already used by another source of the program", i, key)
+		}
+		names[key] = true
+	}
+	return issues
+}
+
+// validateTracks checks the parallel tracks and resolves chained programs
+// within each track. Issues about a track itself use the track ID in place
+// of a program ID.
+func validateTracks(tracks []Track) []ValidationIssue {
+	var issues []ValidationIssue
+	trackIssue := func(id, severity, format string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{
+			ProgramID: id,
+			Severity:  severity,
+			Message:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	seen := make(map[string]bool, len(tracks))
+	for i := range tracks {
+		track := &tracks[i]
+		if track.ID == "" {
+			trackIssue("", SeverityError, "track %d has no id and will not be evaluated", i)
+			continue
+		}
+		if seen[track.ID] {
+			trackIssue(track.ID, SeverityError, "duplicate track id %q, only the first track with this id is evaluated", track.ID)
+			continue
+		}
+		seen[track.ID] = true
+
+		for j := range track.Programs {
+			if issue, reserved := reservedIDIssue(&track.Programs[j]); reserved {
+				issues = append(issues, issue)
+				track.Programs[j].Enabled = false
+			}
+		}
+		issues = append(issues, resolveChains(track.Programs)...)
+		for j := range track.Programs {
+			p := &track.Programs[j]
+			if len(p.Rundown) > 0 || len(p.Behavior.Breaks) > 0 || p.Behavior.EndCondition == EndConditionMediaEnd {
+				trackIssue(p.ID, SeverityWarning, "track %q: rundowns, breaks and the mediaEnd end condition are only supported on the main track", track.ID)
+			}
+			if p.Source.SceneMode == eventbus.SceneModeProgram {
+				trackIssue(p.ID, SeverityWarning, "track %q: sceneMode \"program\" is only supported on the main track, the scene will be nested", track.ID)
+			}
+			if p.Source.Captions != "" {
+				trackIssue(p.ID, SeverityWarning, "track %q: captions are only sent for the main track", track.ID)
+			}
+			if p.Behavior.Fallback != nil {
+				trackIssue(p.ID, SeverityWarning, "track %q: source health and fallbacks are only supported on the main track", track.ID)
+			}
+			issues = append(issues, validateTransition(p)...)
+			issues = append(issues, validateLayers(p)...)
+			issues = append(issues, validateScene(p)...)
+			issues = append(issues, validateActions(p)...)
+			issues = append(issues, validateAudio(p)...)
+			issues = append(issues, validateFilters(p)...)
+		}
+	}
+	return issues
+}
+
+// validateScene checks programs that air an existing OBS scene.
+func validateScene(p *ScheduledProgram) []ValidationIssue {
+	src := &p.Source
+	if src.SceneName == "" {
+		if src.SceneMode != "" {
+			return []ValidationIssue{{ProgramID: p.ID, Severity: SeverityWarning, Message: "sceneMode is ignored without a sceneName"}}
+		}
+		return nil
+	}
+
+	var issues []ValidationIssue
+	switch src.SceneMode {
+	case "", eventbus.SceneModeNested, eventbus.SceneModeProgram:
+	default:
+		issues = append(issues, ValidationIssue{
+			ProgramID: p.ID,
+			Severity:  SeverityWarning,
+			Message:   fmt.Sprintf("unknown sceneMode %q, the scene will be nested", src.SceneMode),
+		})
+	}
+	if src.InputKind != "" || src.URI != "" {
+		issues = append(issues, ValidationIssue{
+			ProgramID: p.ID,
+			Severity:  SeverityWarning,
+			Message:   "inputKind and uri are ignored for programs that air an existing scene",
+		})
+	}
+	if src.SceneMode == eventbus.SceneModeProgram && len(p.Layers) > 0 {
+		issues = append(issues, ValidationIssue{
+			ProgramID: p.ID,
+			Severity:  SeverityWarning,
+			Message:   "layers are ignored when the scene becomes the program scene",
+		})
+	}
+	return issues
+}
+
+// validateAudio checks the audio settings of the main source and of every
+// layer. Out-of-range values are clamped by the switcher.
+func validateAudio(p *ScheduledProgram) []ValidationIssue {
+	var issues []ValidationIssue
+	check := func(where string, a *Audio) {
+		if a == nil {
+			return
+		}
+		warn := func(message string) {
+			issues = append(issues, ValidationIssue{
+				ProgramID: p.ID,
+				Severity:  SeverityWarning,
+				Message:   fmt.Sprintf("%s audio: %s", where, message),
+			})
+		}
+		if a.VolumeDb != nil && *a.VolumeDb > maxVolumeDb {
+			warn(fmt.Sprintf("volumeDb cannot exceed %+.0f dB", maxVolumeDb))
+		}
+		if a.Balance != nil && (*a.Balance < 0 || *a.Balance > 1) {
+			warn("balance must be between 0.0 (left) and 1.0 (right)")
+		}
+		if _, ok := audioMonitorTypes[a.MonitorType]; a.MonitorType != "" && !ok {
+			warn(fmt.Sprintf("unknown monitorType %q, the OBS default is used", a.MonitorType))
+		}
+	}
+
+	if p.Source.SceneName != "" && p.Source.Audio != nil {
+		issues = append(issues, ValidationIssue{
+			ProgramID: p.ID,
+			Severity:  SeverityWarning,
+			Message:   "audio settings are ignored for programs that air an existing scene",
+		})
+	} else {
+		check("source", p.Source.Audio)
+	}
+	for i := range p.Layers {
+		check(fmt.Sprintf("layer %d", i), p.Layers[i].Audio)
+	}
+	return issues
+}
+
+// validateFilters checks the filters of the main source and of every layer.
+// Whether OBS supports a filter kind is only known once connected; the
+// switcher skips unsupported kinds when the input is created.
+func validateFilters(p *ScheduledProgram) []ValidationIssue {
+	var issues []ValidationIssue
+	check := func(where string, filters []Filter) {
+		warn := func(format string, args ...interface{}) {
+			issues = append(issues, ValidationIssue{
+				ProgramID: p.ID,
+				Severity:  SeverityWarning,
+				Message:   where + " filters: " + fmt.Sprintf(format, args...),
+			})
+		}
+		seen := make(map[string]bool, len(filters))
+		for i, f := range filters {
+			switch {
+			case f.Kind == "":
+				warn("filter %d has no kind and will be skipped", i)
+			case f.Name == "":
+				warn("filter %d has no name and will be skipped", i)
+			case seen[f.Name]:
+				warn("duplicate filter name %q, only the first filter with this name is added", f.Name)
+			}
+			seen[f.Name] = true
+		}
+	}
+
+	if p.Source.SceneName != "" && len(p.Source.Filters) > 0 {
+		issues = append(issues, ValidationIssue{
+			ProgramID: p.ID,
+			Severity:  SeverityWarning,
+			Message:   "filters are ignored for programs that air an existing scene",
+		})
+	} else {
+		check("source", p.Source.Filters)
+	}
+	for i := range p.Layers {
+		if p.Layers[i].SceneName != "" && len(p.Layers[i].Filters) > 0 {
+			issues = append(issues, ValidationIssue{
+				ProgramID: p.ID,
+				Severity:  SeverityWarning,
+				Message:   fmt.Sprintf("layer %d: filters are ignored for layers that nest an existing scene", i),
+			})
+			continue
+		}
+		check(fmt.Sprintf("layer %d", i), p.Layers[i].Filters)
+	}
+	return issues
+}
+
+// validateCaptions checks the caption file of a program. Layers cannot carry
+// captions, since the stream has a single caption channel.
+func validateCaptions(p *ScheduledProgram) []ValidationIssue {
+	var issues []ValidationIssue
+	warn := func(format string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{
+			ProgramID: p.ID,
+			Severity:  SeverityWarning,
+			Message:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	if p.Source.Captions != "" {
+		switch strings.ToLower(filepath.Ext(p.Source.Captions)) {
+		case ".srt", ".vtt":
+		default:
+			warn("captions %q should be an SRT (.srt) or WebVTT (.vtt) file", p.Source.Captions)
+		}
+	}
+	for i := range p.Layers {
+		if p.Layers[i].Captions != "" {
+			warn("layer %d: captions are ignored for layers, set them on the program source", i)
+		}
+	}
+	return issues
+}
+
+// validateFallback checks the fallback source of a program. Only live
+// sources are monitored, so a fallback elsewhere is never shown.
+func validateFallback(p *ScheduledProgram) []ValidationIssue {
+	f := p.Behavior.Fallback
+	if f == nil {
+		return nil
+	}
+	warn := func(message string) []ValidationIssue {
+		return []ValidationIssue{{ProgramID: p.ID, Severity: SeverityWarning, Message: message}}
+	}
+	switch {
+	case f.Name == "" && f.SceneName == "":
+		return warn("fallback has neither a name nor a sceneName and is ignored")
+	case f.SceneName == "" && f.InputKind == "":
+		return warn("fallback has no inputKind")
+	case p.Source.SceneName != "":
+		return warn("fallback is ignored for programs that air an existing scene")
+	}
+	return nil
+}
+
+// validateActions checks the OBS output actions of a program. Actions with an
+// unknown name are skipped by the OBSClient.
+func validateActions(p *ScheduledProgram) []ValidationIssue {
+	var issues []ValidationIssue
+	warn := func(format string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{
+			ProgramID: p.ID,
+			Severity:  SeverityWarning,
+			Message:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	for i, a := range p.Behavior.Actions {
+		switch {
+		case a.Action == eventbus.OBSActionMacro:
+			warn("action %d: macros are only supported on timeline events, the action will be skipped", i)
+		case !isKnownOBSAction(a.Action):
+			warn("action %d: unknown action %q", i, a.Action)
+		}
+		if a.At != "" && a.At != actionAtStart && a.At != actionAtEnd {
+			warn("action %d: unknown anchor %q, the action fires at the start of the program", i, a.At)
+		}
+		if offset := time.Duration(a.OffsetSeconds) * time.Second; offset > maxActionOffset || offset < -maxActionOffset {
+			warn("action %d: offsetSeconds is limited to one hour, the action will not fire", i)
+		}
+	}
+	return issues
+}
+
+// validateEvents checks the standalone timeline events of the schedule.
+func validateEvents(events []TimelineEvent) []ValidationIssue {
+	var issues []ValidationIssue
+	seen := make(map[string]bool, len(events))
+	for i, event := range events {
+		var message string
+		switch {
+		case event.ID == "":
+			message = fmt.Sprintf("timeline event %d has no id", i)
+		case seen[event.ID]:
+			message = fmt.Sprintf("duplicate timeline event id %q", event.ID)
+		case !isKnownOBSAction(event.Action):
+			message = fmt.Sprintf("unknown action %q, the event will be skipped", event.Action)
+		case event.Timing.Start.IsZero():
+			message = "timeline event has no start time and will never fire"
+		case event.Action == eventbus.OBSActionMacro && len(event.Steps) == 0:
+			message = "macro event has no steps"
+		}
+		seen[event.ID] = true
+		if message != "" {
+			issues = append(issues, ValidationIssue{ProgramID: event.ID, Severity: SeverityWarning, Message: message})
+		}
+		if event.Action == eventbus.OBSActionMacro {
+			issues = append(issues, validateMacro(&event)...)
+		}
+	}
+	return issues
+}
+
+// validateMacro checks the steps of a macro event. Request names and fields
+// are only checked by OBS when the macro runs.
+func validateMacro(event *TimelineEvent) []ValidationIssue {
+	var issues []ValidationIssue
+	warn := func(format string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{
+			ProgramID: event.ID,
+			Severity:  SeverityWarning,
+			Message:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	for i, step := range event.Steps {
+		if step.RequestType == "" {
+			warn("macro step %d has no requestType", i)
+		}
+		if step.OnError != "" && step.OnError != eventbus.MacroOnErrorStop && step.OnError != eventbus.MacroOnErrorContinue {
+			warn("macro step %d: unknown onError %q, a failure will stop the macro", i, step.OnError)
+		}
+		if step.DelayMs < 0 {
+			warn("macro step %d: delayMs cannot be negative", i)
+		}
+	}
+	return issues
+}
+
+// isKnownOBSAction reports whether the OBSClient can execute the action.
+func isKnownOBSAction(action string) bool {
+	switch action {
+	case eventbus.OBSActionStartStreaming, eventbus.OBSActionStopStreaming,
+		eventbus.OBSActionStartRecording, eventbus.OBSActionStopRecording,
+		eventbus.OBSActionSplitRecording, eventbus.OBSActionSaveReplayBuffer,
+		eventbus.OBSActionMacro:
+		return true
+	}
+	return false
+}
+
+// sourceKey identifies a source within a program: the scene it airs, or its
+// input name.
+func sourceKey(src *Source) string {
+	if src.SceneName != "" {
+		return src.SceneName
+	}
+	return src.Name
+}
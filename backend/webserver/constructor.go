@@ -43,6 +43,7 @@ type WebServer struct {
 	wsHandler         *websocket.Handler
 	whepHandler       *whep.Handler
 	previewManager    *sourcepreview.Manager
+	thumbnails        *thumbnailStore
 
 	// --- Lifecycle Management ---
 	ctx              context.Context
@@ -84,6 +85,7 @@ func New(
 		config:           cfg,
 		logger:           log,
 		bus:              bus,
+		thumbnails:       newThumbnailStore(),
 		unsubscribeFuncs: make([]eventbus.UnsubscribeFunc, 0, 4),
 	}
 
@@ -102,12 +104,15 @@ func New(
 	})
 
 	// Create preview manager (may fail non-fatally if binary not found)
-	previewMgr, err := sourcepreview.New(log, cfg.HlsPath)
+	previewMgr, err := sourcepreview.New(log, cfg)
 	if err != nil {
 		log.Error("Failed to create preview manager", "error", err)
 		// Continue anyway - preview requests will fail gracefully
 	}
 	server.previewManager = previewMgr
+	if previewMgr != nil && cfg.PreviewBackend == "obs" {
+		server.useOBSPreviews()
+	}
 
 	// Setup HTTP routing and middleware.
 	mux := server.setupRouter(staticFiles)
@@ -169,6 +174,24 @@ func (ws *WebServer) createWebSocketCallbacks(bus *eventbus.EventBus) websocket.
 				Payload:  payload,
 			})
 		},
+		OnAutoFillSchedule: func(clientID string, payload json.RawMessage) {
+			eventbus.Publish(bus, eventbus.AutoFillScheduleRequested{
+				ClientID: clientID,
+				Payload:  payload,
+			})
+		},
+		OnProbeMediaDuration: func(clientID string, payload json.RawMessage) {
+			eventbus.Publish(bus, eventbus.MediaDurationRequested{
+				ClientID: clientID,
+				Payload:  payload,
+			})
+		},
+		OnExpandSchedule: func(clientID string, payload json.RawMessage) {
+			eventbus.Publish(bus, eventbus.ExpandScheduleRequested{
+				ClientID: clientID,
+				Payload:  payload,
+			})
+		},
 		OnGetStatus: func(clientID string) {
 			eventbus.Publish(bus, eventbus.GetStatusRequested{
 				ClientID: clientID,
@@ -56,9 +56,37 @@ func (s *WebServer) subscribeToEvents() {
 	unsub9, err9 := eventbus.Subscribe(s.bus, "WebServer", s.handleVirtualCamStopped)
 	s.addUnsubscriber(unsub9, err9, "OBSVirtualCamStopped")
 
+	// OBS output events (broadcast to all clients)
+	unsub12, err12 := eventbus.Subscribe(s.bus, "WebServer", s.handleStreamStateChanged)
+	s.addUnsubscriber(unsub12, err12, "OBSStreamStateChanged")
+	unsub13, err13 := eventbus.Subscribe(s.bus, "WebServer", s.handleRecordStateChanged)
+	s.addUnsubscriber(unsub13, err13, "OBSRecordStateChanged")
+	unsub14, err14 := eventbus.Subscribe(s.bus, "WebServer", s.handleMacroExecuted)
+	s.addUnsubscriber(unsub14, err14, "OBSMacroExecuted")
+	unsub15, err15 := eventbus.Subscribe(s.bus, "WebServer", s.handleCaptionStatusChanged)
+	s.addUnsubscriber(unsub15, err15, "OBSCaptionStatusChanged")
+	unsub16, err16 := eventbus.Subscribe(s.bus, "WebServer", s.handleSourceHealthChanged)
+	s.addUnsubscriber(unsub16, err16, "OBSSourceHealthChanged")
+	unsub17, err17 := eventbus.Subscribe(s.bus, "WebServer", s.handleFrameAnomaly)
+	s.addUnsubscriber(unsub17, err17, "OBSFrameAnomaly")
+	unsub18, err18 := eventbus.Subscribe(s.bus, "WebServer", s.handleSilenceChanged)
+	s.addUnsubscriber(unsub18, err18, "OBSSilenceChanged")
+	unsub19, err19 := eventbus.Subscribe(s.bus, "WebServer", s.handleThumbnailUpdated)
+	s.addUnsubscriber(unsub19, err19, "OBSThumbnailUpdated")
+	unsub20, err20 := eventbus.Subscribe(s.bus, "WebServer", s.handleSourcePreviewReady)
+	s.addUnsubscriber(unsub20, err20, "OBSSourcePreviewReady")
+	unsub21, err21 := eventbus.Subscribe(s.bus, "WebServer", s.handleSourcePreviewFailed)
+	s.addUnsubscriber(unsub21, err21, "OBSSourcePreviewFailed")
+	unsub22, err22 := eventbus.Subscribe(s.bus, "WebServer", s.handlePreflightResult)
+	s.addUnsubscriber(unsub22, err22, "OBSPreflightResult")
+
 	// Status response (send to specific client that requested it)
 	unsub10, err10 := eventbus.Subscribe(s.bus, "WebServer", s.handleStatusResponse)
 	s.addUnsubscriber(unsub10, err10, "StatusResponse")
+
+	// Scheduler rundown position (broadcast to all clients)
+	unsub11, err11 := eventbus.Subscribe(s.bus, "WebServer", s.handleRundownPositionChanged)
+	s.addUnsubscriber(unsub11, err11, "RundownPositionChanged")
 }
 
 // addUnsubscriber is a helper to reduce boilerplate in the subscription process.
@@ -260,6 +288,169 @@ func (s *WebServer) handleVirtualCamStopped(event eventbus.OBSVirtualCamStopped)
 	s.wsHandler.Broadcast("virtualCamStopped", json.RawMessage(payload))
 }
 
+// =============================================================================
+// Event Handlers (OBS Outputs)
+// =============================================================================
+
+// handleStreamStateChanged broadcasts stream start and stop to all WebSocket clients.
+//
+// Topic: obs.stream.state.changed
+func (s *WebServer) handleStreamStateChanged(event eventbus.OBSStreamStateChanged) {
+	s.logger.Debug("Stream state changed, broadcasting to clients", "streaming", event.IsStreaming)
+
+	if s.wsHandler == nil {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Error("Failed to marshal StreamStateChanged payload", "error", err)
+		return
+	}
+
+	s.wsHandler.Broadcast("streamStateChanged", json.RawMessage(payload))
+}
+
+// handleRecordStateChanged broadcasts recording start and stop to all
+// WebSocket clients, including the file written when a recording stops.
+//
+// Topic: obs.record.state.changed
+func (s *WebServer) handleRecordStateChanged(event eventbus.OBSRecordStateChanged) {
+	s.logger.Debug("Record state changed, broadcasting to clients", "recording", event.IsRecording)
+
+	if s.wsHandler == nil {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Error("Failed to marshal RecordStateChanged payload", "error", err)
+		return
+	}
+
+	s.wsHandler.Broadcast("recordStateChanged", json.RawMessage(payload))
+}
+
+// handleMacroExecuted broadcasts the outcome of a scheduled macro, step by
+// step, to all WebSocket clients.
+//
+// Topic: obs.macro.executed
+func (s *WebServer) handleMacroExecuted(event eventbus.OBSMacroExecuted) {
+	s.logger.Debug("Macro executed, broadcasting to clients", "event", event.EventID, "success", event.Success)
+
+	if s.wsHandler == nil {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Error("Failed to marshal MacroExecuted payload", "error", err)
+		return
+	}
+
+	s.wsHandler.Broadcast("macroExecuted", json.RawMessage(payload))
+}
+
+// handleCaptionStatusChanged broadcasts the caption playback status of the
+// program on air to all WebSocket clients.
+//
+// Topic: obs.captions.status
+func (s *WebServer) handleCaptionStatusChanged(event eventbus.OBSCaptionStatusChanged) {
+	s.logger.Debug("Caption status changed, broadcasting to clients", "program", event.ProgramID, "status", event.Status)
+
+	if s.wsHandler == nil {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Error("Failed to marshal CaptionStatusChanged payload", "error", err)
+		return
+	}
+
+	s.wsHandler.Broadcast("captionStatus", json.RawMessage(payload))
+}
+
+// handleSourceHealthChanged broadcasts outages and recoveries of the source
+// on air to all WebSocket clients.
+//
+// Topic: obs.source.health
+func (s *WebServer) handleSourceHealthChanged(event eventbus.OBSSourceHealthChanged) {
+	s.logger.Debug("Source health changed, broadcasting to clients", "program", event.ProgramID, "status", event.Status)
+
+	if s.wsHandler == nil {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Error("Failed to marshal SourceHealthChanged payload", "error", err)
+		return
+	}
+
+	s.wsHandler.Broadcast("sourceHealth", json.RawMessage(payload))
+}
+
+// handleFrameAnomaly broadcasts picture anomalies on air, and their end, to
+// all WebSocket clients.
+//
+// Topic: obs.frame.anomaly
+func (s *WebServer) handleFrameAnomaly(event eventbus.OBSFrameAnomaly) {
+	s.logger.Debug("Frame anomaly changed, broadcasting to clients", "program", event.ProgramID, "kind", event.Kind, "active", event.Active)
+
+	if s.wsHandler == nil {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Error("Failed to marshal FrameAnomaly payload", "error", err)
+		return
+	}
+
+	s.wsHandler.Broadcast("frameAnomaly", json.RawMessage(payload))
+}
+
+// handleSilenceChanged broadcasts dead air on the program on air, and its
+// end, to all WebSocket clients.
+//
+// Topic: obs.audio.silence
+func (s *WebServer) handleSilenceChanged(event eventbus.OBSSilenceChanged) {
+	s.logger.Debug("Silence changed, broadcasting to clients", "program", event.ProgramID, "silent", event.Silent)
+
+	if s.wsHandler == nil {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Error("Failed to marshal SilenceChanged payload", "error", err)
+		return
+	}
+
+	s.wsHandler.Broadcast("silenceAlert", json.RawMessage(payload))
+}
+
+// handlePreflightResult broadcasts the outcome of a pre-flight check on an
+// upcoming program to all WebSocket clients.
+//
+// Topic: obs.preflight.result
+func (s *WebServer) handlePreflightResult(event eventbus.OBSPreflightResult) {
+	s.logger.Debug("Pre-flight result, broadcasting to clients", "program", event.ProgramID, "passed", event.Passed)
+
+	if s.wsHandler == nil {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Error("Failed to marshal PreflightResult payload", "error", err)
+		return
+	}
+
+	s.wsHandler.Broadcast("preflightAlert", json.RawMessage(payload))
+}
+
 // =============================================================================
 // Event Handlers (Status Response)
 // =============================================================================
@@ -286,3 +477,30 @@ func (s *WebServer) handleStatusResponse(event eventbus.StatusResponse) {
 
 	s.wsHandler.SendToClient(event.ClientID, "currentStatus", json.RawMessage(payload))
 }
+
+// =============================================================================
+// Event Handlers (Scheduler Rundown)
+// =============================================================================
+
+// handleRundownPositionChanged broadcasts the live rundown segment to all
+// WebSocket clients so they can show what is live and up next.
+//
+// Topic: scheduler.state.rundownPosition
+func (s *WebServer) handleRundownPositionChanged(event eventbus.RundownPositionChanged) {
+	s.logger.Debug("Rundown position changed, broadcasting to clients",
+		"programID", event.ProgramID,
+		"index", event.Index,
+		"active", event.Active)
+
+	if s.wsHandler == nil {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Error("Failed to marshal RundownPositionChanged payload", "error", err)
+		return
+	}
+
+	s.wsHandler.Broadcast("rundownPosition", json.RawMessage(payload))
+}
@@ -35,6 +35,9 @@ func (s *WebServer) setupRouter(staticFiles fs.FS) *http.ServeMux {
 	hlsHandler := http.StripPrefix("/hls/", http.FileServer(http.Dir(s.config.HlsPath)))
 	mux.Handle("/hls/", auth(hlsHandler))
 
+	// Register the confidence thumbnails captured by the OBS client.
+	mux.Handle("/thumbnails/", auth(http.HandlerFunc(s.handleThumbnailRequest)))
+
 	// Register the static file server for the frontend application.
 	// IMPORTANT: This must be registered LAST as it's a catch-all route.
 	staticHandler := http.FileServer(http.FS(staticFiles))
@@ -0,0 +1,156 @@
+// backend/webserver/internal/sourcepreview/generator.go
+//
+// Preview generator implementations. Each one wraps an external program
+// that turns a source into an HLS stream in an output directory, with the
+// arguments from its section of the configuration.
+//
+// Contents:
+// - NewGenerator - Generator selection
+// - hlsGenerator - hls-generator (companion C++ tool)
+// - ffmpegGenerator - FFmpeg command line
+// - findBinary - Binary discovery
+
+package sourcepreview
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"scenescheduler/backend/config"
+)
+
+// NewGenerator returns the generator of a preview backend name, or nil if
+// the name is not a generator (e.g. "obs").
+func NewGenerator(name string, generators config.PreviewGeneratorsConfig) PreviewGenerator {
+	switch name {
+	case "hls-generator":
+		return &hlsGenerator{options: generators.HLSGenerator}
+	case "ffmpeg":
+		return &ffmpegGenerator{options: generators.FFmpeg}
+	default:
+		return nil
+	}
+}
+
+// =============================================================================
+// hls-generator
+// =============================================================================
+
+// hlsGenerator runs the hls-generator companion tool:
+//
+//	hls-generator <sourceURI> <outputDir> [--segment-duration N] [--resolution WxH] [--video-bitrate B]
+type hlsGenerator struct {
+	options config.PreviewGeneratorConfig
+	path    string // Set by Probe
+}
+
+func (g *hlsGenerator) Name() string { return "hls-generator" }
+
+func (g *hlsGenerator) Probe() error {
+	path, err := findBinary("hls-generator", g.options.Path)
+	g.path = path
+	return err
+}
+
+func (g *hlsGenerator) Command(sourceURI, outputDir string) *exec.Cmd {
+	args := []string{sourceURI, outputDir}
+	if g.options.SegmentDuration > 0 {
+		args = append(args, "--segment-duration", strconv.Itoa(g.options.SegmentDuration))
+	}
+	if g.options.Resolution != "" {
+		args = append(args, "--resolution", g.options.Resolution)
+	}
+	if g.options.VideoBitrate != "" {
+		args = append(args, "--video-bitrate", g.options.VideoBitrate)
+	}
+	return exec.Command(g.path, args...)
+}
+
+// =============================================================================
+// FFmpeg
+// =============================================================================
+
+// ffmpegGenerator runs the FFmpeg command line. Local files are read at
+// their native rate so the preview plays in real time instead of being
+// transcoded as fast as possible.
+type ffmpegGenerator struct {
+	options config.PreviewGeneratorConfig
+	path    string // Set by Probe
+}
+
+func (g *ffmpegGenerator) Name() string { return "ffmpeg" }
+
+func (g *ffmpegGenerator) Probe() error {
+	path, err := findBinary("ffmpeg", g.options.Path)
+	g.path = path
+	return err
+}
+
+func (g *ffmpegGenerator) Command(sourceURI, outputDir string) *exec.Cmd {
+	args := []string{"-hide_banner", "-loglevel", "error"}
+	if !strings.Contains(sourceURI, "://") || strings.HasPrefix(sourceURI, "file://") {
+		args = append(args, "-re")
+	}
+	args = append(args, "-i", strings.TrimPrefix(sourceURI, "file://"),
+		"-c:v", "libx264", "-preset", "veryfast", "-tune", "zerolatency")
+	if g.options.Resolution != "" {
+		args = append(args, "-s", g.options.Resolution)
+	}
+	if g.options.VideoBitrate != "" {
+		args = append(args, "-b:v", g.options.VideoBitrate)
+	}
+	args = append(args, "-c:a", "aac", "-f", "hls")
+	if g.options.SegmentDuration > 0 {
+		args = append(args, "-hls_time", strconv.Itoa(g.options.SegmentDuration))
+	}
+	args = append(args,
+		"-hls_list_size", "5",
+		"-hls_flags", "delete_segments",
+		"-hls_segment_filename", filepath.Join(outputDir, "segment_%03d.ts"),
+		filepath.Join(outputDir, "playlist.m3u8"))
+	return exec.Command(g.path, args...)
+}
+
+// =============================================================================
+// Binary Discovery
+// =============================================================================
+
+// findBinary discovers the location of a generator binary.
+// It searches in the following order:
+//  1. The configured path, if any (the only candidate then)
+//  2. Same directory as the scenescheduler executable
+//  3. System PATH
+//
+// Returns the absolute path to the binary, or error if not found.
+func findBinary(name, configured string) (string, error) {
+	// 1. Configured path
+	if configured != "" {
+		path, err := exec.LookPath(configured)
+		if err != nil {
+			return "", fmt.Errorf("%w at %s", ErrBinaryNotFound, configured)
+		}
+		return path, nil
+	}
+
+	// 2. Same directory as executable
+	exePath, err := os.Executable()
+	if err == nil {
+		exeDir := filepath.Dir(exePath)
+		candidate := filepath.Join(exeDir, name)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+
+	// 3. System PATH
+	path, err := exec.LookPath(name)
+	if err == nil {
+		return path, nil
+	}
+
+	return "", ErrBinaryNotFound
+}
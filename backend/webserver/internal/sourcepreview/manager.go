@@ -13,10 +13,10 @@ package sourcepreview
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"sync"
 	"time"
 
+	"scenescheduler/backend/config"
 	"scenescheduler/backend/logger"
 )
 
@@ -25,11 +25,11 @@ import (
 // =============================================================================
 
 // New creates a new preview manager instance.
-// It discovers the hls-generator binary and performs startup cleanup.
+// It probes the available generators and performs startup cleanup.
 //
 // Parameters:
 //   - logger: Logger instance (will be scoped to "sourcepreview" module)
-//   - hlsBasePath: Base directory for HLS files (e.g., "./hls")
+//   - cfg: Web server configuration (HLS path, preview backend, generators and limits)
 //
 // Returns:
 //   - *Manager: Configured manager ready to handle requests
@@ -37,19 +37,34 @@ import (
 //
 // Non-fatal issues (binary not found) are logged but don't prevent creation.
 // Preview requests will fail gracefully with clear error messages.
-func New(logger *logger.Logger, hlsBasePath string) (*Manager, error) {
+func New(logger *logger.Logger, cfg *config.WebServerConfig) (*Manager, error) {
 	logger = logger.WithModule("sourcepreview")
+	hlsBasePath := cfg.HlsPath
+
+	// Probe every generator, so the log shows which backends could be used
+	var generator PreviewGenerator
+	var generatorErr error
+	for _, name := range []string{"hls-generator", "ffmpeg"} {
+		candidate := NewGenerator(name, cfg.PreviewGenerators)
+		err := candidate.Probe()
+		if err != nil {
+			logger.Debug("Preview generator not available", "generator", name, "error", err)
+		} else {
+			logger.Debug("Preview generator available", "generator", name)
+		}
+		if name == cfg.PreviewBackend {
+			generator, generatorErr = candidate, err
+		}
+	}
 
-	// Discover hls-generator binary
-	binaryPath, err := findHLSGeneratorBinary()
-	if err != nil {
-		logger.Error("hls-generator binary not found - preview requests will fail",
-			"error", err,
-			"suggestion", "Place hls-generator in same directory as scenescheduler executable")
+	if generator != nil && generatorErr != nil {
+		logger.Error("Configured preview generator not found - preview requests will fail",
+			"generator", generator.Name(),
+			"error", generatorErr,
+			"suggestion", "Place "+generator.Name()+" in same directory as scenescheduler executable, or set its path")
 		// Don't fail constructor - allow WebServer to start
-		binaryPath = "" // Will cause preview requests to fail gracefully
-	} else {
-		logger.Info("Found hls-generator binary", "path", binaryPath)
+	} else if generator != nil {
+		logger.Info("Using preview generator", "generator", generator.Name())
 	}
 
 	// Startup cleanup: remove all old preview directories
@@ -66,11 +81,14 @@ func New(logger *logger.Logger, hlsBasePath string) (*Manager, error) {
 	logger.Debug("HLS base directory ready", "path", hlsBasePath)
 
 	return &Manager{
-		logger:           logger,
-		hlsBasePath:      hlsBasePath,
-		hlsGeneratorPath: binaryPath,
-		activePreviews:   make(map[uint64]*Session),
-		connIDToPreview:  make(map[string]uint64),
+		logger:          logger,
+		hlsBasePath:     hlsBasePath,
+		limits:          cfg.PreviewLimits,
+		generator:       generator,
+		generatorErr:    generatorErr,
+		activePreviews:  make(map[uint64]*Session),
+		connIDToPreview: make(map[string]uint64),
+		streams:         make(map[string]*Stream),
 		// nextPreviewID starts at 0, first Add(1) will return 1
 	}, nil
 }
@@ -85,18 +103,23 @@ func New(logger *logger.Logger, hlsBasePath string) (*Manager, error) {
 // Behavior:
 //   - Validates request
 //   - Auto-cancels previous preview if client already has one active
-//   - Spawns hls-generator process asynchronously
+//   - Joins the stream of the same source if one exists
+//   - Otherwise spawns the generator process asynchronously, or queues it
+//     while limits.MaxProcesses processes are running
 //   - Polls for playlist.m3u8 creation (30s timeout)
 //   - Calls OnReady(hlsURL) on success or OnError(msg) on failure
 //
 // Returns:
-//   - error: Immediate validation errors only (binary not found)
+//   - error: Immediate validation errors only (generator not available)
 //   - nil: Request accepted, result will be delivered via callbacks
 func (m *Manager) StartPreview(req StartPreviewRequest) error {
-	// Validation: check binary availability
-	if m.hlsGeneratorPath == "" {
-		errMsg := "hls-generator binary not found. Please ensure hls-generator is installed."
-		m.logger.Error("Cannot start preview - binary not available", "remoteAddr", req.RemoteAddr)
+	// Validation: check generator availability
+	if m.obs == nil && (m.generator == nil || m.generatorErr != nil) {
+		errMsg := "No preview backend is available."
+		if m.generator != nil {
+			errMsg = fmt.Sprintf("%s: %v. Please ensure %s is installed.", m.generator.Name(), m.generatorErr, m.generator.Name())
+		}
+		m.logger.Error("Cannot start preview - generator not available", "remoteAddr", req.RemoteAddr)
 		if req.OnError != nil {
 			req.OnError(errMsg)
 		}
@@ -125,53 +148,33 @@ func (m *Manager) StartPreview(req StartPreviewRequest) error {
 	// Generate incremental preview ID (atomic, thread-safe)
 	previewID := m.nextPreviewID.Add(1)
 
-	// Create preview directory: {hlsBase}/preview-{id}/
-	tempDir := filepath.Join(m.hlsBasePath, fmt.Sprintf("preview-%d", previewID))
-	if err := os.MkdirAll(tempDir, 0755); err != nil {
-		errMsg := fmt.Sprintf("failed to create preview directory: %v", err)
-		m.logger.Error("Failed to create preview directory",
-			"error", err,
-			"previewID", previewID,
-			"remoteAddr", req.RemoteAddr)
-		if req.OnError != nil {
-			req.OnError(errMsg)
-		}
-		return err
+	// OBS previews need no process or directory, see obs.go
+	if m.obs != nil {
+		m.startOBSPreview(previewID, req)
+		return nil
 	}
 
-	m.logger.Debug("Preview directory created", "path", tempDir, "previewID", previewID)
-
-	// Create session
+	// Create session and attach it to the stream of its source
 	session := &Session{
 		PreviewID:    previewID,
 		ConnectionID: req.ConnectionID,
 		RemoteAddr:   req.RemoteAddr,
 		SourceURI:    req.SourceURI,
 		InputKind:    req.InputKind,
-		TempDir:      tempDir,
 		CreatedAt:    time.Now(),
 		onReady:      req.OnReady,
 		onError:      req.OnError,
 		onStopped:    req.OnStopped,
+		onQueued:     req.OnQueued,
 	}
-
-	// Store session
-	m.addSession(session)
-
-	m.logger.Info("Starting preview",
-		"previewID", previewID,
-		"connectionID", req.ConnectionID,
-		"remoteAddr", req.RemoteAddr,
-		"sourceURI", req.SourceURI)
-
-	// Spawn process asynchronously
-	go m.processPreview(session)
+	m.joinStream(session, req)
 
 	return nil
 }
 
 // StopPreview terminates an active preview session for a client.
-// It kills the process, cleans up filesystem, and removes session tracking.
+// The stream of the session stops when it has no other viewers: its process
+// is killed and its files removed, after the grace period if configured.
 //
 // This operation is idempotent - calling on non-existent session is a no-op.
 // This is automatically called when WebSocket disconnects.
@@ -180,49 +183,25 @@ func (m *Manager) StartPreview(req StartPreviewRequest) error {
 //   - connectionID: WebSocket connection ID (unique identifier)
 //
 // Returns:
-//   - error: Only if session cleanup fails (logged, not fatal)
+//   - error: Always nil (cleanup failures are logged)
 func (m *Manager) StopPreview(connectionID string) error {
-	m.mu.Lock()
+	m.mu.RLock()
 	previewID, exists := m.connIDToPreview[connectionID]
+	m.mu.RUnlock()
 	if !exists {
-		m.mu.Unlock()
 		return nil // Idempotent - stopping non-existent session is no-op
 	}
 
-	session, exists := m.activePreviews[previewID]
-	if !exists {
-		m.mu.Unlock()
-		return nil
-	}
-
-	// Remove from maps immediately (before killing process)
-	delete(m.activePreviews, previewID)
-	delete(m.connIDToPreview, connectionID)
-	m.mu.Unlock()
-
-	m.logger.Debug("Stopping preview", "previewID", previewID, "connectionID", connectionID, "remoteAddr", session.RemoteAddr)
-
-	// Kill process (outside lock)
-	if session.Process != nil {
-		m.killProcess(session.Process)
-	}
-
-	// Cleanup filesystem
-	if err := os.RemoveAll(session.TempDir); err != nil {
-		m.logger.Warn("Failed to remove temp directory", "path", session.TempDir, "error", err)
-		return err
-	}
-
-	m.logger.Debug("Preview stopped and cleaned up", "previewID", previewID)
+	m.stopPreviewInternal(previewID)
 	return nil
 }
 
 // Shutdown performs graceful cleanup of all resources.
 // Called by WebServer during shutdown sequence.
 //
-// It kills all active processes in parallel using WaitGroup,
-// removes all preview directories, and clears internal state.
-// This operation is idempotent.
+// It ends all streams, including queued ones and ones kept warm, kills their
+// processes in parallel using WaitGroup, removes all preview directories,
+// and clears internal state. This operation is idempotent.
 //
 // Returns:
 //   - error: Only if filesystem cleanup fails (logged, not fatal)
@@ -230,31 +209,52 @@ func (m *Manager) Shutdown() error {
 	var shutdownErr error
 
 	m.shutdownOnce.Do(func() {
-		// Get all active preview IDs (copy to avoid holding lock during cleanup)
-		m.mu.RLock()
-		previewIDs := make([]uint64, 0, len(m.activePreviews))
-		for id := range m.activePreviews {
-			previewIDs = append(previewIDs, id)
+		// End everything under the lock, clean up outside of it
+		m.mu.Lock()
+		var obsPreviews []uint64
+		for previewID, session := range m.activePreviews {
+			if session.TimeoutTimer != nil {
+				session.TimeoutTimer.Stop()
+			}
+			if session.InOBS {
+				obsPreviews = append(obsPreviews, previewID)
+			}
+		}
+		streams := make([]*Stream, 0, len(m.streams))
+		for _, stream := range m.streams {
+			streams = append(streams, stream)
+		}
+		for _, stream := range streams {
+			m.endStreamLocked(stream) // Queued streams are never launched
 		}
-		m.mu.RUnlock()
+		activeCount := len(m.activePreviews)
+		m.activePreviews = nil
+		m.connIDToPreview = nil
+		m.streams = nil
+		m.streamQueue = nil
+		m.mu.Unlock()
 
-		if len(previewIDs) == 0 {
+		if activeCount == 0 && len(streams) == 0 {
 			m.logger.Info("Preview manager shutdown: no active previews")
 		} else {
-			m.logger.Info("Shutting down preview manager", "activePreviews", len(previewIDs))
+			m.logger.Info("Shutting down preview manager", "activePreviews", activeCount, "streams", len(streams))
+
+			for _, previewID := range obsPreviews {
+				m.obs.Stop(previewID)
+			}
 
 			// Kill all processes in parallel
 			var wg sync.WaitGroup
-			for _, id := range previewIDs {
+			for _, stream := range streams {
 				wg.Add(1)
-				go func(previewID uint64) {
+				go func(stream *Stream) {
 					defer wg.Done()
-					m.stopPreviewInternal(previewID)
-				}(id)
+					m.cleanupStream(stream)
+				}(stream)
 			}
 			wg.Wait()
 
-			m.logger.Debug("All preview processes terminated", "count", len(previewIDs))
+			m.logger.Debug("All preview processes terminated", "count", len(streams))
 		}
 
 		// Cleanup all preview directories
@@ -263,12 +263,6 @@ func (m *Manager) Shutdown() error {
 			shutdownErr = err
 		}
 
-		// Clear maps
-		m.mu.Lock()
-		m.activePreviews = nil
-		m.connIDToPreview = nil
-		m.mu.Unlock()
-
 		m.logger.Info("Preview manager shutdown complete")
 	})
 
@@ -282,29 +276,31 @@ func (m *Manager) Shutdown() error {
 // stopPreviewInternal is internal version that operates on PreviewID directly.
 // Used when already holding lock or during auto-cancel scenarios.
 func (m *Manager) stopPreviewInternal(previewID uint64) {
-	m.mu.RLock()
+	m.mu.Lock()
 	session, exists := m.activePreviews[previewID]
-	m.mu.RUnlock()
-
 	if !exists {
+		m.mu.Unlock()
 		return
 	}
 
+	// Remove from maps immediately (before stopping anything)
+	delete(m.activePreviews, previewID)
+	if m.connIDToPreview[session.ConnectionID] == previewID {
+		delete(m.connIDToPreview, session.ConnectionID)
+	}
+
 	// Cancel timeout timer if it exists (prevents double cleanup)
 	if session.TimeoutTimer != nil {
 		session.TimeoutTimer.Stop()
 	}
-
-	m.mu.Lock()
-	delete(m.activePreviews, previewID)
-	if session.ConnectionID != "" {
-		delete(m.connIDToPreview, session.ConnectionID)
-	}
 	m.mu.Unlock()
 
-	if session.Process != nil {
-		m.killProcess(session.Process)
-	}
+	m.logger.Debug("Stopping preview", "previewID", previewID, "connectionID", session.ConnectionID, "remoteAddr", session.RemoteAddr)
 
-	os.RemoveAll(session.TempDir)
+	// Tear down the OBS input, or leave the shared stream (outside lock)
+	if session.InOBS {
+		m.obs.Stop(previewID)
+		return
+	}
+	m.leaveStream(session)
 }
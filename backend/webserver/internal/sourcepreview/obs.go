@@ -0,0 +1,91 @@
+// backend/webserver/internal/sourcepreview/obs.go
+//
+// OBS backend for the source preview module. Instead of spawning a
+// generator, the requested input is rendered in OBS and its frames are
+// sent to the client by the OBS client. Sessions are tracked like HLS
+// previews: one per client, stopped on request, on disconnect, or after the
+// runtime limit. They are not shared, since frames go to a single client.
+//
+// Contents:
+// - UseOBS - Select the OBS backend
+// - startOBSPreview - Start a preview in OBS
+// - OBS result notifications
+
+package sourcepreview
+
+import (
+	"time"
+)
+
+// UseOBS makes the manager render previews in OBS. It must be called before
+// the first preview is started. No generator is required then.
+func (m *Manager) UseOBS(backend OBSBackend) {
+	m.obs = &backend
+	m.logger.Info("Source previews are rendered in OBS")
+}
+
+// startOBSPreview records the session and asks OBS to render the source.
+// The result arrives through OBSPreviewReady or OBSPreviewFailed.
+func (m *Manager) startOBSPreview(previewID uint64, req StartPreviewRequest) {
+	session := &Session{
+		PreviewID:    previewID,
+		ConnectionID: req.ConnectionID,
+		RemoteAddr:   req.RemoteAddr,
+		SourceURI:    req.SourceURI,
+		InputKind:    req.InputKind,
+		InOBS:        true,
+		CreatedAt:    time.Now(),
+		onReady:      req.OnReady,
+		onError:      req.OnError,
+		onStopped:    req.OnStopped,
+	}
+	m.addSession(session)
+
+	m.logger.Info("Starting OBS preview",
+		"previewID", previewID,
+		"connectionID", req.ConnectionID,
+		"remoteAddr", req.RemoteAddr,
+		"sourceURI", req.SourceURI)
+
+	m.obs.Start(previewID, req)
+}
+
+// OBSPreviewReady is called when OBS renders a preview. The client is told
+// to expect frames, and the runtime limit starts.
+func (m *Manager) OBSPreviewReady(previewID uint64) {
+	m.mu.RLock()
+	session, exists := m.activePreviews[previewID]
+	m.mu.RUnlock()
+	if !exists {
+		return // Stopped while OBS was creating the input
+	}
+
+	m.logger.Info("OBS preview ready", "previewID", previewID, "remoteAddr", session.RemoteAddr)
+	if session.onReady != nil {
+		session.onReady("")
+	}
+
+	m.startRuntimeLimit(session)
+}
+
+// OBSPreviewFailed is called when OBS cannot render a preview, or stopped
+// rendering it. OBS has already removed the input, so only the session is
+// forgotten.
+func (m *Manager) OBSPreviewFailed(previewID uint64, errMsg string) {
+	m.mu.RLock()
+	session, exists := m.activePreviews[previewID]
+	m.mu.RUnlock()
+	if !exists {
+		return
+	}
+
+	if session.TimeoutTimer != nil {
+		session.TimeoutTimer.Stop()
+	}
+	m.removeSession(previewID)
+
+	m.logger.Warn("OBS preview failed", "previewID", previewID, "remoteAddr", session.RemoteAddr, "error", errMsg)
+	if session.onError != nil {
+		session.onError("OBS: " + errMsg)
+	}
+}
@@ -3,7 +3,7 @@
 // Preview processing goroutine implementation.
 //
 // Contents:
-// - processPreview - Main async goroutine for generating HLS previews
+// - processPreview - Main async goroutine for generating HLS streams
 
 package sourcepreview
 
@@ -16,67 +16,79 @@ import (
 	"time"
 )
 
-// processPreview is the async goroutine that spawns hls-generator,
-// waits for playlist creation, and invokes callbacks.
+// processPreview is the async goroutine that spawns the generator of a
+// stream, waits for playlist creation, and tells the stream's viewers.
 //
 // This function runs in its own goroutine and manages the entire lifecycle
 // of generating an HLS preview stream:
-//  1. Spawn hls-generator process
+//  1. Spawn generator process
 //  2. Poll for playlist.m3u8 creation
-//  3. Invoke OnReady callback when ready, or OnError on failure
+//  3. Mark the stream ready, or failed on error
+//
+// The stream may end while this runs, when its last viewer leaves; the
+// process is then killed by cleanupStream.
 //
 // The function includes panic recovery to ensure failures don't crash the server.
-func (m *Manager) processPreview(session *Session) {
+func (m *Manager) processPreview(stream *Stream) {
 	defer func() {
 		if r := recover(); r != nil {
 			m.logger.Error("Preview processing panic",
-				"previewID", session.PreviewID,
-				"remoteAddr", session.RemoteAddr,
+				"streamID", stream.ID,
 				"panic", r)
-			if session.onError != nil {
-				session.onError(fmt.Sprintf("internal error: %v", r))
-			}
-			m.removeSession(session.PreviewID)
+			m.failStream(stream, fmt.Sprintf("internal error: %v", r))
 		}
 	}()
 
-	// 1. Spawn hls-generator process
-	m.logger.Debug("Spawning hls-generator process",
-		"previewID", session.PreviewID,
-		"remoteAddr", session.RemoteAddr)
+	// Create preview directory: {hlsBase}/preview-{id}/
+	if err := os.MkdirAll(stream.TempDir, 0755); err != nil {
+		m.logger.Error("Failed to create preview directory",
+			"error", err,
+			"streamID", stream.ID)
+		m.failStream(stream, fmt.Sprintf("failed to create preview directory: %v", err))
+		return
+	}
+
+	// 1. Spawn generator process
+	m.logger.Debug("Spawning generator process",
+		"generator", m.generator.Name(),
+		"streamID", stream.ID,
+		"sourceURI", stream.SourceURI)
 
-	process, err := m.spawnProcess(session.SourceURI, session.TempDir)
+	process, err := m.spawnProcess(stream.SourceURI, stream.TempDir)
 	if err != nil {
 		m.logger.Error("Failed to spawn process",
 			"error", err,
-			"previewID", session.PreviewID,
-			"remoteAddr", session.RemoteAddr)
+			"streamID", stream.ID)
 
-		if session.onError != nil {
-			stderr := ""
-			if process != nil && process.StderrBuf != nil {
-				stderr = process.StderrBuf.String()
-			}
-			session.onError(fmt.Sprintf("failed to spawn process: %v\nStderr: %s", err, stderr))
+		stderr := ""
+		if process != nil && process.StderrBuf != nil {
+			stderr = process.StderrBuf.String()
 		}
-		m.removeSession(session.PreviewID)
+		m.failStream(stream, fmt.Sprintf("%s: failed to spawn process: %v\nStderr: %s", m.generator.Name(), err, stderr))
 		return
 	}
 
-	// 2. Store process handle in session
+	// 2. Store process handle in stream
 	m.mu.Lock()
-	if s, ok := m.activePreviews[session.PreviewID]; ok {
-		s.Process = process
+	ended := stream.ended
+	if !ended {
+		stream.Process = process
 	}
 	m.mu.Unlock()
 
+	if ended {
+		// Last viewer left while spawning
+		m.killProcess(process)
+		os.RemoveAll(stream.TempDir)
+		return
+	}
+
 	m.logger.Debug("Process spawned",
-		"previewID", session.PreviewID,
-		"pid", process.PID,
-		"remoteAddr", session.RemoteAddr)
+		"streamID", stream.ID,
+		"pid", process.PID)
 
 	// 3. Wait for playlist.m3u8 to be created
-	playlistPath := filepath.Join(session.TempDir, "playlist.m3u8")
+	playlistPath := filepath.Join(stream.TempDir, "playlist.m3u8")
 
 	ctx, cancel := context.WithTimeout(context.Background(), playlistWaitTimeout)
 	defer cancel()
@@ -86,6 +98,10 @@ func (m *Manager) processPreview(session *Session) {
 
 	for {
 		select {
+		case <-stream.done:
+			// Last viewer left before the playlist was ready
+			return
+
 		case <-ticker.C:
 			// Check if playlist exists
 			if _, err := os.Stat(playlistPath); err == nil {
@@ -93,58 +109,25 @@ func (m *Manager) processPreview(session *Session) {
 				hasSegments, err := playlistHasSegments(playlistPath)
 				if err != nil {
 					m.logger.Debug("Error reading playlist",
-						"previewID", session.PreviewID,
+						"streamID", stream.ID,
 						"error", err)
 					continue // Keep polling
 				}
 
 				if !hasSegments {
 					m.logger.Debug("Playlist exists but has no segments yet, waiting...",
-						"previewID", session.PreviewID)
+						"streamID", stream.ID)
 					continue // Keep polling
 				}
 
 				// SUCCESS! Playlist has segments
-				hlsURL := fmt.Sprintf("/hls/preview-%d/playlist.m3u8", session.PreviewID)
+				hlsURL := fmt.Sprintf("/hls/preview-%d/playlist.m3u8", stream.ID)
 
 				m.logger.Info("Preview ready",
-					"previewID", session.PreviewID,
-					"remoteAddr", session.RemoteAddr,
+					"streamID", stream.ID,
 					"hlsURL", hlsURL)
 
-				if session.onReady != nil {
-					session.onReady(hlsURL)
-				}
-
-				// Schedule automatic timeout to prevent resource accumulation
-				timeoutTimer := time.AfterFunc(previewMaxRuntime, func() {
-					m.logger.Info("Preview auto-stopped after maximum runtime",
-						"previewID", session.PreviewID,
-						"connectionID", session.ConnectionID,
-						"runtime", previewMaxRuntime)
-
-					// Notify frontend BEFORE cleanup (so it can gracefully stop HLS.js)
-					if session.onStopped != nil {
-						session.onStopped("Preview automatically stopped after 30 seconds")
-					}
-
-					// Small delay to ensure WebSocket message is sent before cleanup
-					time.Sleep(100 * time.Millisecond)
-
-					if err := m.StopPreview(session.ConnectionID); err != nil {
-						m.logger.Error("Failed to stop preview on timeout",
-							"previewID", session.PreviewID,
-							"error", err)
-					}
-				})
-
-				// Store timer in session so it can be canceled if manually stopped
-				m.mu.Lock()
-				if s, ok := m.activePreviews[session.PreviewID]; ok {
-					s.TimeoutTimer = timeoutTimer
-				}
-				m.mu.Unlock()
-
+				m.streamReady(stream, hlsURL)
 				return
 			}
 
@@ -155,20 +138,15 @@ func (m *Manager) processPreview(session *Session) {
 				stderr = process.StderrBuf.String()
 			}
 
-			errMsg := fmt.Sprintf("preview generation timed out after %v. Playlist file was not created.\nStderr: %s",
-				playlistWaitTimeout, stderr)
+			errMsg := fmt.Sprintf("%s: preview generation timed out after %v. Playlist file was not created.\nStderr: %s",
+				m.generator.Name(), playlistWaitTimeout, stderr)
 
 			m.logger.Error("Preview timeout",
-				"previewID", session.PreviewID,
-				"remoteAddr", session.RemoteAddr,
+				"streamID", stream.ID,
 				"timeout", playlistWaitTimeout)
 
-			if session.onError != nil {
-				session.onError(errMsg)
-			}
-
 			// Cleanup failed preview
-			m.StopPreview(session.ConnectionID)
+			m.failStream(stream, errMsg)
 			return
 		}
 	}
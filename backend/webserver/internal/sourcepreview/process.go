@@ -1,32 +1,9 @@
 // backend/webserver/internal/sourcepreview/process.go
 //
-// Process management utilities for hls-generator.
-//
-// DESIGN NOTE: Binary Coupling
-// =============================
-// This file is intentionally coupled to the "hls-generator" binary (external C++ tool).
-// No abstraction layer exists because we follow the YAGNI principle - we don't need
-// multiple generator implementations yet.
-//
-// If migrating to FFmpeg in the future, modify these sections:
-//
-// 1. findHLSGeneratorBinary() - Change binary name from "hls-generator" to "ffmpeg"
-// 2. spawnProcess() - Replace CLI invocation:
-//    Current:  hls-generator <sourceURI> <outputDir>
-//    FFmpeg:   ffmpeg -i <sourceURI> -c:v libx264 -hls_time 4 -hls_list_size 5 \
-//              -hls_flags delete_segments -hls_segment_filename <outputDir>/segment_%03d.ts \
-//              <outputDir>/playlist.m3u8
-// 3. Log messages referencing "hls-generator"
-// 4. Verify playlist.m3u8 generation behavior matches (see preview.go:78)
-//
-// Expected CLI Interface:
-//   - Takes source URI (RTMP, file path, device) as input
-//   - Generates playlist.m3u8 in output directory
-//   - Responds to SIGTERM for graceful shutdown
-//   - Writes diagnostic info to stderr
+// Process management utilities for preview generators. The generator
+// builds the command (see generator.go); this file runs and stops it.
 //
 // Contents:
-// - findHLSGeneratorBinary - Binary discovery
 // - spawnProcess - Process spawning
 // - killProcess - Graceful process termination
 
@@ -35,40 +12,11 @@ package sourcepreview
 import (
 	"bytes"
 	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
 	"syscall"
 	"time"
 )
 
-// findHLSGeneratorBinary discovers the hls-generator binary location.
-// It searches in the following order:
-//  1. Same directory as the scenescheduler executable
-//  2. System PATH
-//
-// Returns the absolute path to the binary, or error if not found.
-func findHLSGeneratorBinary() (string, error) {
-	// 1. Same directory as executable
-	exePath, err := os.Executable()
-	if err == nil {
-		exeDir := filepath.Dir(exePath)
-		candidate := filepath.Join(exeDir, "hls-generator")
-		if _, err := os.Stat(candidate); err == nil {
-			return candidate, nil
-		}
-	}
-
-	// 2. System PATH
-	path, err := exec.LookPath("hls-generator")
-	if err == nil {
-		return path, nil
-	}
-
-	return "", ErrBinaryNotFound
-}
-
-// spawnProcess starts an hls-generator process with stderr capture.
+// spawnProcess starts a generator process with stderr capture.
 //
 // Parameters:
 //   - sourceURI: The source URI to process (RTMP, file, etc.)
@@ -82,7 +30,7 @@ func (m *Manager) spawnProcess(sourceURI, outputDir string) (*ProcessHandle, err
 	stderrBuf := &bytes.Buffer{}
 	limitedWriter := &limitedWriter{buf: stderrBuf, maxSize: stderrBufferSize}
 
-	cmd := exec.Command(m.hlsGeneratorPath, sourceURI, outputDir)
+	cmd := m.generator.Command(sourceURI, outputDir)
 	cmd.Stderr = limitedWriter
 
 	if err := cmd.Start(); err != nil {
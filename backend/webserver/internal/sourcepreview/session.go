@@ -19,7 +19,7 @@ func (m *Manager) addSession(session *Session) {
 }
 
 // removeSession removes a session from tracking maps by PreviewID.
-// Used during error handling in processPreview goroutine.
+// Used when an OBS preview fails.
 func (m *Manager) removeSession(previewID uint64) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -0,0 +1,287 @@
+// backend/webserver/internal/sourcepreview/stream.go
+//
+// Shared preview streams. Sessions previewing the same source (same
+// fingerprint) watch one stream, generated by one process. A stream is
+// reference-counted by its viewers: it stops when the last one leaves, or
+// after the grace period so a returning viewer starts instantly. At most
+// limits.MaxProcesses streams run at once; further streams wait in a queue.
+//
+// Contents:
+// - sourceFingerprint - Stream key
+// - joinStream - Attach a session to a stream
+// - leaveStream - Detach a session from its stream
+// - Stream lifecycle (ready, failed, ended)
+// - Runtime limit
+
+package sourcepreview
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// sourceFingerprint identifies the source of a preview request. Requests
+// with the same fingerprint share a stream.
+func sourceFingerprint(req StartPreviewRequest) string {
+	settings, _ := json.Marshal(req.InputSettings) // Map keys are sorted
+	sum := sha256.Sum256([]byte(req.InputKind + "\x00" + req.SourceURI + "\x00" + string(settings)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// =============================================================================
+// Viewers
+// =============================================================================
+
+// joinStream attaches a session to the stream of its source, creating the
+// stream if there is none. The session is told right away if the stream is
+// ready or queued; otherwise it is told when the stream becomes ready.
+func (m *Manager) joinStream(session *Session, req StartPreviewRequest) {
+	fingerprint := sourceFingerprint(req)
+
+	m.mu.Lock()
+	stream, shared := m.streams[fingerprint]
+	if !shared {
+		stream = &Stream{
+			ID:          session.PreviewID,
+			Fingerprint: fingerprint,
+			SourceURI:   req.SourceURI,
+			TempDir:     filepath.Join(m.hlsBasePath, fmt.Sprintf("preview-%d", session.PreviewID)),
+			CreatedAt:   time.Now(),
+			viewers:     make(map[uint64]*Session),
+			done:        make(chan struct{}),
+		}
+		m.streams[fingerprint] = stream
+	}
+	if stream.graceTimer != nil {
+		stream.graceTimer.Stop()
+		stream.graceTimer = nil
+	}
+	session.Stream = stream
+	stream.viewers[session.PreviewID] = session
+	m.activePreviews[session.PreviewID] = session
+	m.connIDToPreview[session.ConnectionID] = session.PreviewID
+
+	var launch []*Stream
+	if !shared {
+		stream.state = streamQueued
+		m.streamQueue = append(m.streamQueue, stream)
+		launch = m.dequeueStreamsLocked()
+	}
+	state, hlsURL := stream.state, stream.HLSURL
+	position := slices.Index(m.streamQueue, stream) + 1
+	m.mu.Unlock()
+
+	m.logger.Info("Starting preview",
+		"previewID", session.PreviewID,
+		"streamID", stream.ID,
+		"shared", shared,
+		"connectionID", session.ConnectionID,
+		"remoteAddr", session.RemoteAddr,
+		"sourceURI", session.SourceURI)
+
+	switch state {
+	case streamReady:
+		if session.onReady != nil {
+			session.onReady(hlsURL)
+		}
+		m.startRuntimeLimit(session)
+	case streamQueued:
+		m.logger.Info("Preview waiting for a free process slot", "streamID", stream.ID, "position", position)
+		if session.onQueued != nil {
+			session.onQueued(position)
+		}
+	}
+	m.launchStreams(launch)
+}
+
+// leaveStream detaches a session from its stream. The stream ends when it
+// has no viewers left, once the grace period is over if it is ready.
+func (m *Manager) leaveStream(session *Session) {
+	stream := session.Stream
+
+	m.mu.Lock()
+	delete(stream.viewers, session.PreviewID)
+	if len(stream.viewers) > 0 || stream.ended {
+		m.mu.Unlock()
+		return
+	}
+
+	grace := time.Duration(m.limits.GraceSeconds) * time.Second
+	if stream.state == streamReady && grace > 0 {
+		m.logger.Debug("Keeping preview stream warm", "streamID", stream.ID, "grace", grace)
+		stream.graceTimer = time.AfterFunc(grace, func() {
+			m.mu.Lock()
+			if len(stream.viewers) > 0 || stream.ended {
+				m.mu.Unlock()
+				return // A viewer came back
+			}
+			launch := m.endStreamLocked(stream)
+			m.mu.Unlock()
+
+			m.launchStreams(launch)
+			m.cleanupStream(stream)
+		})
+		m.mu.Unlock()
+		return
+	}
+
+	launch := m.endStreamLocked(stream)
+	m.mu.Unlock()
+
+	m.launchStreams(launch)
+	m.cleanupStream(stream)
+}
+
+// =============================================================================
+// Stream Lifecycle
+// =============================================================================
+
+// dequeueStreamsLocked takes queued streams while process slots are free,
+// and returns them to be launched. Must be called with mu held.
+func (m *Manager) dequeueStreamsLocked() []*Stream {
+	var launch []*Stream
+	for len(m.streamQueue) > 0 && (m.limits.MaxProcesses == 0 || m.runningCount < m.limits.MaxProcesses) {
+		stream := m.streamQueue[0]
+		m.streamQueue = m.streamQueue[1:]
+		stream.state = streamStarting
+		m.runningCount++
+		launch = append(launch, stream)
+	}
+	return launch
+}
+
+// launchStreams starts the generator of each stream.
+func (m *Manager) launchStreams(streams []*Stream) {
+	for _, stream := range streams {
+		go m.processPreview(stream)
+	}
+}
+
+// endStreamLocked forgets a stream and frees its process slot, returning
+// the queued streams that can now be launched. The process and files are
+// removed by cleanupStream. Must be called with mu held.
+func (m *Manager) endStreamLocked(stream *Stream) []*Stream {
+	if stream.ended {
+		return nil
+	}
+	stream.ended = true
+	close(stream.done)
+	if stream.graceTimer != nil {
+		stream.graceTimer.Stop()
+	}
+
+	if m.streams[stream.Fingerprint] == stream {
+		delete(m.streams, stream.Fingerprint)
+	}
+	if stream.state == streamQueued {
+		m.streamQueue = slices.DeleteFunc(m.streamQueue, func(s *Stream) bool { return s == stream })
+		return nil
+	}
+	m.runningCount--
+	return m.dequeueStreamsLocked()
+}
+
+// cleanupStream kills the process of an ended stream and removes its files.
+func (m *Manager) cleanupStream(stream *Stream) {
+	m.mu.Lock()
+	process := stream.Process
+	m.mu.Unlock()
+
+	if process != nil {
+		m.killProcess(process)
+	}
+	if err := os.RemoveAll(stream.TempDir); err != nil {
+		m.logger.Warn("Failed to remove temp directory", "path", stream.TempDir, "error", err)
+	}
+	m.logger.Debug("Preview stream stopped and cleaned up", "streamID", stream.ID)
+}
+
+// streamReady marks a stream ready and tells its viewers.
+func (m *Manager) streamReady(stream *Stream, hlsURL string) {
+	m.mu.Lock()
+	stream.state = streamReady
+	stream.HLSURL = hlsURL
+	viewers := make([]*Session, 0, len(stream.viewers))
+	for _, session := range stream.viewers {
+		viewers = append(viewers, session)
+	}
+	m.mu.Unlock()
+
+	for _, session := range viewers {
+		if session.onReady != nil {
+			session.onReady(hlsURL)
+		}
+		m.startRuntimeLimit(session)
+	}
+}
+
+// failStream ends a stream that could not be generated and tells its viewers.
+func (m *Manager) failStream(stream *Stream, errMsg string) {
+	m.mu.Lock()
+	viewers := make([]*Session, 0, len(stream.viewers))
+	for previewID, session := range stream.viewers {
+		viewers = append(viewers, session)
+		delete(m.activePreviews, previewID)
+		delete(m.connIDToPreview, session.ConnectionID)
+		if session.TimeoutTimer != nil {
+			session.TimeoutTimer.Stop()
+		}
+	}
+	clear(stream.viewers)
+	launch := m.endStreamLocked(stream)
+	m.mu.Unlock()
+
+	for _, session := range viewers {
+		if session.onError != nil {
+			session.onError(errMsg)
+		}
+	}
+	m.launchStreams(launch)
+	m.cleanupStream(stream)
+}
+
+// =============================================================================
+// Runtime Limit
+// =============================================================================
+
+// startRuntimeLimit stops a session once it has watched its preview for the
+// configured maximum runtime. The stream itself keeps running for the other
+// viewers.
+func (m *Manager) startRuntimeLimit(session *Session) {
+	if m.limits.MaxRuntimeSeconds <= 0 {
+		return
+	}
+	runtime := time.Duration(m.limits.MaxRuntimeSeconds) * time.Second
+
+	timeoutTimer := time.AfterFunc(runtime, func() {
+		m.logger.Info("Preview auto-stopped after maximum runtime",
+			"previewID", session.PreviewID,
+			"connectionID", session.ConnectionID,
+			"runtime", runtime)
+
+		// Notify frontend BEFORE cleanup (so it can gracefully stop playback)
+		if session.onStopped != nil {
+			session.onStopped(fmt.Sprintf("Preview automatically stopped after %d seconds", m.limits.MaxRuntimeSeconds))
+		}
+
+		// Small delay to ensure WebSocket message is sent before cleanup
+		time.Sleep(100 * time.Millisecond)
+
+		m.stopPreviewInternal(session.PreviewID)
+	})
+
+	// Store timer in session so it can be canceled if manually stopped
+	m.mu.Lock()
+	if _, ok := m.activePreviews[session.PreviewID]; ok {
+		session.TimeoutTimer = timeoutTimer
+	} else {
+		timeoutTimer.Stop()
+	}
+	m.mu.Unlock()
+}
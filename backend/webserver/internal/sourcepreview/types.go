@@ -5,7 +5,10 @@
 // Contents:
 // - Constants (timeouts, buffer sizes)
 // - Error definitions
+// - PreviewGenerator interface
 // - Manager struct
+// - OBSBackend struct
+// - Stream struct
 // - Session struct
 // - ProcessHandle struct
 // - StartPreviewRequest struct
@@ -20,6 +23,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"scenescheduler/backend/config"
 	"scenescheduler/backend/logger"
 )
 
@@ -34,9 +38,6 @@ const (
 	// processKillTimeout is the grace period for SIGTERM before SIGKILL
 	processKillTimeout = 5 * time.Second
 
-	// previewMaxRuntime is the maximum time a preview can run before auto-stop
-	previewMaxRuntime = 30 * time.Second
-
 	// pollInterval is the interval for checking playlist file existence
 	pollInterval = 500 * time.Millisecond
 
@@ -49,27 +50,51 @@ const (
 // =============================================================================
 
 var (
-	// ErrBinaryNotFound indicates hls-generator binary was not found
-	ErrBinaryNotFound = errors.New("hls-generator binary not found")
+	// ErrBinaryNotFound indicates a preview generator binary was not found
+	ErrBinaryNotFound = errors.New("binary not found")
 
 	// ErrSessionNotFound indicates requested session does not exist
 	ErrSessionNotFound = errors.New("preview session not found")
 )
 
+// =============================================================================
+// PreviewGenerator
+// =============================================================================
+
+// PreviewGenerator is an external program that turns a source into an HLS
+// stream. It must write playlist.m3u8 to the output directory, stop on
+// SIGTERM, and report problems on stderr.
+type PreviewGenerator interface {
+	// Name identifies the generator in logs and error messages.
+	Name() string
+
+	// Probe locates the binary, returning ErrBinaryNotFound if it is missing.
+	Probe() error
+
+	// Command builds the command that generates the preview of sourceURI.
+	Command(sourceURI, outputDir string) *exec.Cmd
+}
+
 // =============================================================================
 // Manager
 // =============================================================================
 
 // Manager orchestrates HLS preview generation for program sources.
-// It manages temporary hls-generator processes and filesystem resources.
-// Each WebSocket client can have at most one active preview.
+// It manages temporary generator processes and filesystem resources.
+// Each WebSocket client can have at most one active preview; clients
+// previewing the same source share one stream.
 type Manager struct {
 	// --- Dependencies (immutable) ---
 	logger      *logger.Logger
-	hlsBasePath string // Base directory for HLS files (e.g., "./hls")
+	hlsBasePath string                     // Base directory for HLS files (e.g., "./hls")
+	limits      config.PreviewLimitsConfig // Process cap, runtime per client, grace period
+
+	// --- Generator (probed at startup) ---
+	generator    PreviewGenerator // Configured generator
+	generatorErr error            // Why the generator cannot be used (nil = available)
 
-	// --- Binary Discovery ---
-	hlsGeneratorPath string // Cached path to hls-generator binary
+	// --- OBS Backend (set once before use, see UseOBS) ---
+	obs *OBSBackend // nil = previews are generated by the generator
 
 	// --- Active Sessions (guarded by mu) ---
 	mu              sync.RWMutex
@@ -77,23 +102,74 @@ type Manager struct {
 	connIDToPreview map[string]uint64   // Key: connectionID → previewID (for lookup)
 	nextPreviewID   atomic.Uint64       // Incremental counter (thread-safe)
 
+	// --- Shared Streams (guarded by mu) ---
+	streams      map[string]*Stream // Key: source fingerprint
+	streamQueue  []*Stream          // Streams waiting for a free process slot (FIFO)
+	runningCount int                // Streams with a generator process
+
 	// --- Shutdown Coordination ---
 	shutdownOnce sync.Once
 }
 
+// =============================================================================
+// OBSBackend
+// =============================================================================
+
+// OBSBackend renders previews in OBS instead of with a generator. The OBS
+// client is reached over the event bus, so the web server supplies the
+// functions that start and stop a preview there. Frames are sent to the
+// client by the OBS client directly.
+type OBSBackend struct {
+	Start func(previewID uint64, req StartPreviewRequest)
+	Stop  func(previewID uint64)
+}
+
+// =============================================================================
+// Stream
+// =============================================================================
+
+// streamState is the lifecycle state of a Stream.
+type streamState int
+
+const (
+	streamQueued   streamState = iota // Waiting for a free process slot
+	streamStarting                    // Process running, playlist not ready yet
+	streamReady                       // Playlist has segments, viewers are playing
+)
+
+// Stream is the HLS output of one generator process. It is shared by every
+// session previewing the same source, and stops when its last viewer leaves
+// (after the grace period, if any).
+type Stream struct {
+	ID          uint64 // PreviewID of the session that created it
+	Fingerprint string // Source fingerprint (input kind, URI, settings)
+	SourceURI   string // Source URI to preview
+	TempDir     string // Filesystem path: {hlsBase}/preview-{ID}/
+	Process     *ProcessHandle
+	HLSURL      string // Set once ready
+	CreatedAt   time.Time
+
+	// Guarded by Manager.mu
+	state      streamState
+	viewers    map[uint64]*Session // Key: previewID
+	graceTimer *time.Timer         // Running while the stream has no viewers
+	ended      bool
+	done       chan struct{} // Closed when the stream ends
+}
+
 // =============================================================================
 // Session
 // =============================================================================
 
-// Session represents an active HLS preview generation session.
+// Session represents a client's active preview.
 type Session struct {
-	PreviewID    uint64 // Incremental ID (1, 2, 3, ...)
-	ConnectionID string // WebSocket connection ID (unique identifier)
-	RemoteAddr   string // WebSocket remote address (for logging/debugging)
-	SourceURI    string // Source URI to preview
-	InputKind    string // OBS input kind (ffmpeg_source, etc)
-	TempDir      string // Filesystem path: {hlsBase}/preview-{previewID}/
-	Process      *ProcessHandle
+	PreviewID    uint64  // Incremental ID (1, 2, 3, ...)
+	ConnectionID string  // WebSocket connection ID (unique identifier)
+	RemoteAddr   string  // WebSocket remote address (for logging/debugging)
+	SourceURI    string  // Source URI to preview
+	InputKind    string  // OBS input kind (ffmpeg_source, etc)
+	Stream       *Stream // Shared generator output (nil for OBS previews)
+	InOBS        bool    // Rendered by the OBS backend instead of a process
 	CreatedAt    time.Time
 	TimeoutTimer *time.Timer // Auto-stop timer (canceled on manual stop)
 
@@ -101,13 +177,14 @@ type Session struct {
 	onReady   func(hlsURL string)
 	onError   func(errorMsg string)
 	onStopped func(reason string) // Called when preview is auto-stopped
+	onQueued  func(position int)
 }
 
 // =============================================================================
 // ProcessHandle
 // =============================================================================
 
-// ProcessHandle holds resources for an active hls-generator process.
+// ProcessHandle holds resources for an active generator process.
 type ProcessHandle struct {
 	Cmd       *exec.Cmd
 	PID       int
@@ -128,7 +205,8 @@ type StartPreviewRequest struct {
 	InputSettings interface{} // OBS input settings (optional)
 
 	// Async callbacks (called from goroutine)
-	OnReady   func(hlsURL string)   // Called when HLS stream is ready
+	OnReady   func(hlsURL string)   // Called when HLS stream is ready (empty URL for OBS frame previews)
 	OnError   func(errorMsg string) // Called on any error
 	OnStopped func(reason string)   // Called when preview is auto-stopped (timeout, crash, etc)
+	OnQueued  func(position int)    // Called when the preview waits for a free process slot (optional)
 }
@@ -20,9 +20,12 @@ type Callbacks struct {
 	OnClientsChanged     func(count int)
 
 	// Message routing callbacks (client requests)
-	OnGetSchedule    func(clientID string)
-	OnCommitSchedule func(clientID string, payload json.RawMessage)
-	OnGetStatus      func(clientID string)
+	OnGetSchedule        func(clientID string)
+	OnCommitSchedule     func(clientID string, payload json.RawMessage)
+	OnAutoFillSchedule   func(clientID string, payload json.RawMessage)
+	OnProbeMediaDuration func(clientID string, payload json.RawMessage)
+	OnExpandSchedule     func(clientID string, payload json.RawMessage)
+	OnGetStatus          func(clientID string)
 
 	// Source preview callbacks
 	OnStartPreview func(clientID, remoteAddr string, payload json.RawMessage)
@@ -172,6 +172,24 @@ func (h *Handler) routeMessage(msg *Message, connID string) {
 			h.callbacks.OnCommitSchedule(connID, msg.Payload)
 		}
 
+	case "autoFillSchedule":
+		h.logger.Debug("Routing 'autoFillSchedule' command", "connID", connID)
+		if h.callbacks.OnAutoFillSchedule != nil {
+			h.callbacks.OnAutoFillSchedule(connID, msg.Payload)
+		}
+
+	case "probeMediaDuration":
+		h.logger.Debug("Routing 'probeMediaDuration' command", "connID", connID)
+		if h.callbacks.OnProbeMediaDuration != nil {
+			h.callbacks.OnProbeMediaDuration(connID, msg.Payload)
+		}
+
+	case "expandSchedule":
+		h.logger.Debug("Routing 'expandSchedule' command", "connID", connID)
+		if h.callbacks.OnExpandSchedule != nil {
+			h.callbacks.OnExpandSchedule(connID, msg.Payload)
+		}
+
 	case "getStatus":
 		h.logger.Debug("Routing 'getStatus' command", "connID", connID)
 		if h.callbacks.OnGetStatus != nil {
@@ -9,6 +9,7 @@ package webserver
 import (
 	"encoding/json"
 
+	"scenescheduler/backend/eventbus"
 	"scenescheduler/backend/webserver/internal/sourcepreview"
 )
 
@@ -67,6 +68,9 @@ func (s *WebServer) handleStartPreview(clientID, remoteAddr string, payload json
 		OnStopped: func(reason string) {
 			s.sendPreviewStopped(clientID, reason)
 		},
+		OnQueued: func(position int) {
+			s.sendPreviewQueued(clientID, position)
+		},
 	}
 
 	// Start preview (async - result via callbacks)
@@ -100,6 +104,49 @@ func (s *WebServer) handleStopPreview(clientID, remoteAddr string) {
 	s.logger.Debug("Preview stopped successfully", "clientID", clientID, "remoteAddr", remoteAddr)
 }
 
+// =============================================================================
+// OBS Preview Backend
+// =============================================================================
+
+// useOBSPreviews makes the preview manager render previews in OBS. Starting
+// and stopping is requested from the OBS client over the event bus.
+func (s *WebServer) useOBSPreviews() {
+	s.previewManager.UseOBS(sourcepreview.OBSBackend{
+		Start: func(previewID uint64, req sourcepreview.StartPreviewRequest) {
+			settings, _ := req.InputSettings.(map[string]interface{})
+			eventbus.Publish(s.bus, eventbus.SourcePreviewRequested{
+				PreviewID:     previewID,
+				ClientID:      req.ConnectionID,
+				InputKind:     req.InputKind,
+				URI:           req.SourceURI,
+				InputSettings: settings,
+			})
+		},
+		Stop: func(previewID uint64) {
+			eventbus.Publish(s.bus, eventbus.SourcePreviewStopRequested{PreviewID: previewID})
+		},
+	})
+}
+
+// handleSourcePreviewReady tells the client that OBS is rendering its preview.
+//
+// Topic: obs.preview.ready
+func (s *WebServer) handleSourcePreviewReady(event eventbus.OBSSourcePreviewReady) {
+	if s.previewManager != nil {
+		s.previewManager.OBSPreviewReady(event.PreviewID)
+	}
+}
+
+// handleSourcePreviewFailed tells the client that OBS could not render its
+// preview, or stopped rendering it.
+//
+// Topic: obs.preview.failed
+func (s *WebServer) handleSourcePreviewFailed(event eventbus.OBSSourcePreviewFailed) {
+	if s.previewManager != nil {
+		s.previewManager.OBSPreviewFailed(event.PreviewID, event.Error)
+	}
+}
+
 // =============================================================================
 // Response Helpers
 // =============================================================================
@@ -114,9 +161,12 @@ func (s *WebServer) sendPreviewReady(clientID, hlsURL string) {
 		return
 	}
 
-	payload, err := json.Marshal(map[string]interface{}{
-		"hlsUrl": hlsURL,
-	})
+	// OBS previews have no stream; frames follow as previewFrame messages
+	message := map[string]interface{}{"mode": "hls", "hlsUrl": hlsURL}
+	if hlsURL == "" {
+		message = map[string]interface{}{"mode": "frames"}
+	}
+	payload, err := json.Marshal(message)
 	if err != nil {
 		s.logger.Error("Failed to marshal previewReady payload", "error", err)
 		return
@@ -125,6 +175,28 @@ func (s *WebServer) sendPreviewReady(clientID, hlsURL string) {
 	s.wsHandler.SendToClient(clientID, "previewReady", json.RawMessage(payload))
 }
 
+// sendPreviewQueued sends a previewQueued message to the WebSocket client,
+// whose preview waits for a free generator process.
+func (s *WebServer) sendPreviewQueued(clientID string, position int) {
+	s.logger.Info("Preview queued, sending to client",
+		"clientID", clientID,
+		"position", position)
+
+	if s.wsHandler == nil {
+		return
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"position": position,
+	})
+	if err != nil {
+		s.logger.Error("Failed to marshal previewQueued payload", "error", err)
+		return
+	}
+
+	s.wsHandler.SendToClient(clientID, "previewQueued", json.RawMessage(payload))
+}
+
 // sendPreviewError sends a previewError message to the WebSocket client.
 func (s *WebServer) sendPreviewError(clientID, errorMsg string) {
 	s.logger.Warn("Preview error, sending to client",
@@ -0,0 +1,126 @@
+// backend/webserver/thumbnails.go
+//
+// This file serves the confidence thumbnails captured by the OBS client.
+// The latest image of each slot is kept in memory and served at
+// /thumbnails/<slot>; clients are told over the WebSocket when an image
+// changes, with a versioned URL so browsers fetch the new image.
+//
+// Contents:
+// - Thumbnail Store
+// - Thumbnail Handlers
+
+package webserver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"scenescheduler/backend/eventbus"
+)
+
+// =============================================================================
+// Thumbnail Store
+// =============================================================================
+
+// thumbnailImage is the latest image of a thumbnail slot.
+type thumbnailImage struct {
+	data        []byte
+	contentType string
+	etag        string
+	capturedAt  time.Time
+}
+
+// thumbnailStore holds the latest image of each slot.
+type thumbnailStore struct {
+	mu     sync.RWMutex
+	images map[string]*thumbnailImage // Keyed by slot
+}
+
+// newThumbnailStore creates an empty store.
+func newThumbnailStore() *thumbnailStore {
+	return &thumbnailStore{images: make(map[string]*thumbnailImage)}
+}
+
+// set replaces the image of a slot and returns it; a nil data clears it.
+func (t *thumbnailStore) set(slot string, data []byte, format string, capturedAt time.Time) *thumbnailImage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(data) == 0 {
+		delete(t.images, slot)
+		return nil
+	}
+	sum := sha256.Sum256(data)
+	image := &thumbnailImage{
+		data:        data,
+		contentType: "image/jpeg",
+		etag:        hex.EncodeToString(sum[:8]),
+		capturedAt:  capturedAt,
+	}
+	if format == "png" {
+		image.contentType = "image/png"
+	}
+	t.images[slot] = image
+	return image
+}
+
+// get returns the image of a slot, or nil if it has none.
+func (t *thumbnailStore) get(slot string) *thumbnailImage {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.images[slot]
+}
+
+// =============================================================================
+// Thumbnail Handlers
+// =============================================================================
+
+// handleThumbnailRequest serves the latest image of a slot. Browsers must
+// revalidate, and get 304 Not Modified while the image is unchanged.
+func (s *WebServer) handleThumbnailRequest(w http.ResponseWriter, r *http.Request) {
+	slot := strings.TrimPrefix(r.URL.Path, "/thumbnails/")
+	image := s.thumbnails.get(slot)
+	if image == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", image.contentType)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Set("ETag", `"`+image.etag+`"`)
+	http.ServeContent(w, r, slot, image.capturedAt, bytes.NewReader(image.data))
+}
+
+// handleThumbnailUpdated stores a new thumbnail image and tells all WebSocket
+// clients where to fetch it. A cleared slot is broadcast with an empty URL.
+//
+// Topic: obs.thumbnail.updated
+func (s *WebServer) handleThumbnailUpdated(event eventbus.OBSThumbnailUpdated) {
+	s.logger.Debug("Thumbnail updated", "slot", event.Slot, "program", event.ProgramID, "bytes", len(event.Image))
+
+	image := s.thumbnails.set(event.Slot, event.Image, event.Format, event.CapturedAt)
+	if s.wsHandler == nil {
+		return
+	}
+
+	url := ""
+	if image != nil {
+		url = "/thumbnails/" + event.Slot + "?v=" + image.etag
+	}
+	payload, err := json.Marshal(struct {
+		eventbus.OBSThumbnailUpdated
+		URL string `json:"url"`
+	}{event, url})
+	if err != nil {
+		s.logger.Error("Failed to marshal ThumbnailUpdated payload", "error", err)
+		return
+	}
+
+	s.wsHandler.Broadcast("thumbnailUpdated", json.RawMessage(payload))
+}
@@ -12,6 +12,7 @@ import { createMenu, toggleMenu } from './menu.mjs';
 import { importSchedule, exportSchedule } from './schedule-adapter.mjs';
 import { subscribe, getState, setWorkingSchedule, setEditorDirty, setSchedule } from '../../shared/app-state.mjs';
 import { applyRecurringEventStyles, durationStringToMs } from './helpers.mjs';
+import { trackChainedItems } from './chained-items.mjs';
 
 // ================================
 // INITIALIZATION
@@ -143,6 +144,9 @@ export function initEditorCalendar(container) {
     });
 
 
+    // Chained items are placed at the times resolved by the server
+    trackChainedItems(calendar);
+
     try {
         calendar.render();
     } catch (error) {
@@ -2,6 +2,8 @@
 // This module centralizes all logic for creating, updating, and deleting calendar events.
 
 import { dateToHHMMSS } from './helpers.mjs';
+import { exportScheduleWithout } from './schedule-adapter.mjs';
+import { setWorkingSchedule } from '../../shared/app-state.mjs';
 
 /**
  * Creates or updates an event on the calendar.
@@ -12,6 +14,14 @@ import { dateToHHMMSS } from './helpers.mjs';
  */
 function updateEvent(calendar, eventData, existingEvent = null) {
     if (existingEvent) {
+        // Keep the identity and the original schedule item, which holds the
+        // fields the form does not edit (see schedule-adapter.mjs).
+        eventData.id = eventData.id || existingEvent.id;
+        eventData.extendedProps = {
+            ...eventData.extendedProps,
+            stored: eventData.extendedProps?.stored ?? existingEvent.extendedProps?.stored
+        };
+
         // If we are updating, remove the old event first.
         existingEvent.remove();
     }
@@ -22,13 +32,19 @@ function updateEvent(calendar, eventData, existingEvent = null) {
 /**
  * Deletes an event from the calendar after user confirmation.
  * @param {EventApi} eventToDelete - The event object to be deleted.
+ * @param {Calendar | null} calendar - The FullCalendar instance, needed for chained events.
  * @returns {boolean} - True if the event was deleted, false otherwise.
  */
-function deleteEvent(eventToDelete) {
+function deleteEvent(eventToDelete, calendar = null) {
     if (!eventToDelete) return false;
     
     if (confirm('Are you sure you want to delete this event?')) {
-        eventToDelete.remove();
+        if (calendar && eventToDelete.groupId?.startsWith('chain-')) {
+            // A chained item is shown once per occurrence; drop the item itself
+            setWorkingSchedule(exportScheduleWithout(calendar, eventToDelete.id));
+        } else {
+            eventToDelete.remove();
+        }
         return true;
     }
     return false;
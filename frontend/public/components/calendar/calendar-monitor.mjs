@@ -10,6 +10,7 @@ import { importSchedule } from './schedule-adapter.mjs';
 import { subscribe, getState } from '../../shared/app-state.mjs';
 import { openTaskModal } from './modal.mjs';
 import { applyRecurringEventStyles } from './helpers.mjs';
+import { trackChainedItems } from './chained-items.mjs';
 
 // ================================
 // INITIALIZATION
@@ -92,6 +93,9 @@ export function initMonitorCalendar(container) {
     });
 
 
+    // Chained items are placed at the times resolved by the server
+    trackChainedItems(calendar);
+
     try {
         calendar.render();
     } catch (error) {
@@ -0,0 +1,43 @@
+// File: components/calendar/chained-items.mjs
+// Places chained items (timing.after or timing.anchor) at the times the
+// server resolves for them. A calendar asks for the occurrences of its
+// visible range whenever the range or the server schedule changes; see
+// placeChainedItems in schedule-adapter.mjs.
+
+import { placeChainedItems } from './schedule-adapter.mjs';
+import { sendMessage } from '../../services/websocket.mjs';
+import { subscribe } from '../../shared/app-state.mjs';
+
+/**
+ * Keep the chained items of a calendar placed. Call before rendering, so
+ * the first range is requested as well.
+ * @param {Calendar} calendar - FullCalendar instance
+ */
+export function trackChainedItems(calendar) {
+    const request = () => {
+        const view = calendar.view;
+        if (!view) return;
+        sendMessage('expandSchedule', {
+            from: view.activeStart.toISOString(),
+            to: view.activeEnd.toISOString()
+        });
+    };
+
+    calendar.on('datesSet', request);
+
+    subscribe((path) => {
+        if (path === 'schedule') request();
+    });
+
+    // Responses are broadcast to every calendar; keep the one for our range
+    document.addEventListener('schedule:expanded', (e) => {
+        const view = calendar.view;
+        if (view && isSameTime(e.detail.from, view.activeStart) && isSameTime(e.detail.to, view.activeEnd)) {
+            placeChainedItems(calendar, e.detail);
+        }
+    });
+}
+
+function isSameTime(value, date) {
+    return new Date(value).getTime() === date.getTime();
+}
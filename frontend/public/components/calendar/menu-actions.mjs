@@ -3,6 +3,7 @@
 import { exportSchedule, importSchedule } from './schedule-adapter.mjs';
 import { sendMessage, getScheduleFromUser } from '../../services/websocket.mjs';
 import { addLogMessage } from '../../shared/ui-updater.mjs';
+import { showTimelineEvents } from './timeline-events.mjs';
 
 // =============================
 // EXPORTED FUNCTION
@@ -14,7 +15,8 @@ export function handleMenuAction(calendar, action) {
   switch (action) {
     case 'new':
       if (confirm('Are you sure? All current events will be removed.')) {
-        calendar.removeAllEvents();
+        // Also drops the tracks and events of the previous schedule
+        importSchedule(calendar, { version: '1.0', scheduleName: 'Schedule', schedule: [] });
       }
       break;
 
@@ -26,6 +28,11 @@ export function handleMenuAction(calendar, action) {
       saveScheduleToFile(calendar);
       break;
 
+    case 'timeline-events':
+      // Read-only: timeline events are kept from the imported schedule
+      showTimelineEvents(calendar);
+      break;
+
     case 'get-server':
       // Request schedule from server (user action)
       // Will prompt if there are unsaved changes
@@ -32,6 +32,10 @@ export function createMenu(calendar) {
             <div class="menu-item" data-action="save-local">Save to File</div>
         </div>
         <div class="menu-separator"></div>
+        <div class="menu-section">
+            <div class="menu-item" data-action="timeline-events">Timeline Events</div>
+        </div>
+        <div class="menu-separator"></div>
         <div class="menu-section">
             <div class="menu-item" data-action="get-server">Get from Server</div>
             <div class="menu-item" data-action="commit-server">Commit to Server</div>
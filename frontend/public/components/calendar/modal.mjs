@@ -7,6 +7,7 @@ import { populateForm, resetForm, setNewTaskDefaults, extractFormData } from './
 import { validateForm } from './modal/validation.mjs';
 import { initUIHandlers, resetDragState } from './modal/ui.mjs';
 import { initPreview, updatePreviewInfo, cleanupPreview } from './modal/preview.mjs';
+import { initMediaDuration } from './modal/duration.mjs';
 
 // ================================
 // DOM ELEMENT CACHE
@@ -118,7 +119,7 @@ function saveTask(e) {
  */
 function deleteTask() {
     // Call the centralized delete function
-    if (deleteEvent(activeEvent)) {
+    if (deleteEvent(activeEvent, localCalendarInstance)) {
         closeModal();
     }
 }
@@ -196,6 +197,7 @@ function removeReadOnlyMode() {
  */
 function initModal() {
     initUIHandlers(saveTask, deleteTask, closeModal);
+    initMediaDuration();
 }
 
 // Initialize on script load
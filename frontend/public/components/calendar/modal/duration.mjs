@@ -0,0 +1,137 @@
+// File: components/calendar/modal/duration.mjs
+// Media duration discovery for the source picker. When the URI or kind of a
+// media source changes, the server is asked for the length of the media; the
+// result is shown with the option to set the end time from it, and saved
+// with the source as mediaDurationMs.
+
+import { sendMessage } from '../../../services/websocket.mjs';
+import { genId, msToTime, toLocalDateString, dateToHHMMSS } from '../helpers.mjs';
+
+// ================================
+// CONSTANTS
+// ================================
+const PROBED_KINDS = new Set(['ffmpeg_source', 'vlc_source', 'media_source']);
+
+// ================================
+// DOM ELEMENT CACHE
+// ================================
+const dom = {
+    start: document.getElementById('task-start'),
+    end: document.getElementById('task-end'),
+    inputKind: document.getElementById('task-input-kind'),
+    inputUri: document.getElementById('task-input-uri'),
+    inputSettings: document.getElementById('task-input-settings'),
+    hint: document.getElementById('media-duration-hint'),
+    text: document.getElementById('media-duration-text'),
+    applyBtn: document.getElementById('media-duration-apply')
+};
+
+// ================================
+// STATE
+// ================================
+let durationMs = 0;        // Known duration of the current media (0 = unknown)
+let pendingRequestId = null;
+
+// ================================
+// PUBLIC API
+// ================================
+
+/**
+ * Set the known duration of the media, e.g. from the event being edited
+ * @param {number} value - Duration in milliseconds (0 or missing = unknown)
+ */
+export function setMediaDuration(value) {
+    durationMs = Number(value) > 0 ? Number(value) : 0;
+    pendingRequestId = null;
+    showDuration();
+}
+
+/**
+ * Get the known duration of the media, in milliseconds (0 = unknown)
+ * @returns {number}
+ */
+export function getMediaDuration() {
+    return durationMs;
+}
+
+/**
+ * Initialize the source picker listeners
+ */
+export function initMediaDuration() {
+    dom.inputUri.addEventListener('change', probeDuration);
+    dom.inputKind.addEventListener('change', probeDuration);
+    dom.applyBtn.addEventListener('click', applyDuration);
+
+    document.addEventListener('media:duration', (e) => {
+        if (e.detail.requestId !== pendingRequestId) return;
+        pendingRequestId = null;
+        durationMs = e.detail.durationMs;
+        showDuration();
+    });
+
+    document.addEventListener('media:durationError', (e) => {
+        if (e.detail.requestId !== pendingRequestId) return;
+        pendingRequestId = null;
+        showText(`Media duration unknown: ${e.detail.message}`, false);
+    });
+}
+
+// ================================
+// PRIVATE HELPERS
+// ================================
+
+/**
+ * Ask the server for the duration of the media in the source picker
+ */
+function probeDuration() {
+    setMediaDuration(0);
+
+    const uri = dom.inputUri.value.trim();
+    if (!uri || !PROBED_KINDS.has(dom.inputKind.value)) return;
+
+    pendingRequestId = genId('probe-');
+    const start = new Date(dom.start.value);
+    sendMessage('probeMediaDuration', {
+        requestId: pendingRequestId,
+        inputKind: dom.inputKind.value,
+        uri,
+        inputSettings: parseSettings(dom.inputSettings.value),
+        start: isNaN(start) ? undefined : start.toISOString()
+    });
+    showText('Determining media duration...', false);
+}
+
+/**
+ * Set the end time to the start time plus the media duration
+ */
+function applyDuration() {
+    const start = new Date(dom.start.value);
+    if (!durationMs || isNaN(start) || dom.end.disabled) return;
+
+    const end = new Date(start.getTime() + durationMs);
+    dom.end.value = `${toLocalDateString(end)}T${dateToHHMMSS(end)}`;
+    dom.end.dispatchEvent(new Event('change'));
+}
+
+function showDuration() {
+    if (durationMs > 0) {
+        showText(`Media duration: ${msToTime(durationMs)}`, true);
+    } else {
+        dom.hint.style.display = 'none';
+    }
+}
+
+function showText(text, canApply) {
+    dom.text.textContent = text;
+    dom.applyBtn.style.display = canApply ? '' : 'none';
+    dom.hint.style.display = '';
+}
+
+function parseSettings(raw) {
+    try {
+        const obj = JSON.parse(raw || '{}');
+        return obj && typeof obj === 'object' ? obj : {};
+    } catch {
+        return {};
+    }
+}
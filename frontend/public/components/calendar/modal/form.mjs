@@ -4,6 +4,7 @@
 
 import { toLocalDateTimeString, toLocalDateString, formatTodayYYYYMMDD, ensureHHMMSS } from '../helpers.mjs';
 import { parseJsonField, getUriHint, isAllowedKind } from './validation.mjs';
+import { setMediaDuration, getMediaDuration } from './duration.mjs';
 
 // ================================
 // DOM ELEMENT CACHE
@@ -56,7 +57,7 @@ export function populateForm(event) {
     dom.title.value = event.title || '';
     dom.description.value = ext.description || '';
     dom.tags.value = (ext.tags || []).join(' ');
-    dom.classNames.value = (event.classNames || []).filter(c => c !== 'recurring-event').join(' ');
+    dom.classNames.value = (event.classNames || []).filter(c => c !== 'recurring-event' && c !== 'chained-event').join(' ');
 
     setColorInputValue(dom.textColor, event.textColor || '#f8fafc');
     setColorInputValue(dom.backgroundColor, event.backgroundColor || '#3b82f6');
@@ -69,6 +70,7 @@ export function populateForm(event) {
     dom.inputUri.value = ext.inputUri || '';
     dom.inputSettings.value = JSON.stringify(ext.inputSettings || {}, null, 2);
     dom.transform.value = Object.keys(ext.transform || {}).length ? JSON.stringify(ext.transform, null, 2) : '';
+    setMediaDuration(ext.mediaDurationMs);
 
     // Behavior Tab
     dom.enabled.checked = ext.enabled ?? true;
@@ -103,6 +105,7 @@ export function populateForm(event) {
 export function resetForm() {
     dom.form.reset();
     dom.duration.value = '';
+    setMediaDuration(0);
 
     if (dom.filePreview) {
         dom.filePreview.src = '';
@@ -198,6 +201,7 @@ export function extractFormData() {
             inputUri: dom.inputUri.value,
             inputSettings: settingsObj,
             transform: transformObj,
+            mediaDurationMs: getMediaDuration() || undefined,
             recurrence: {}
         }
     };
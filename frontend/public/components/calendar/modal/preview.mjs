@@ -1,6 +1,7 @@
 // File: components/calendar/modal/preview.mjs
 // Source preview functionality for the modal
-// Handles HLS playback of source previews generated by the backend
+// Handles HLS playback of source previews generated by the backend,
+// or frames of previews rendered by OBS
 
 import { sendMessage } from '../../../services/websocket.mjs';
 
@@ -9,6 +10,7 @@ import { sendMessage } from '../../../services/websocket.mjs';
 // ================================
 const dom = {
     video: document.getElementById('modal-preview-video'),
+    image: document.getElementById('modal-preview-image'),
     playBtn: document.getElementById('modal-preview-btn'),
     stopBtn: document.getElementById('modal-stop-preview-btn'),
     sourceUri: document.getElementById('preview-source-uri'),
@@ -22,6 +24,7 @@ const dom = {
 // ================================
 let hls = null; // HLS.js instance
 let currentState = 'idle'; // idle, loading, playing, error
+let currentMode = 'hls'; // hls, frames (rendered by OBS)
 let currentSource = null; // { inputKind, uri, inputSettings }
 
 // ================================
@@ -41,6 +44,8 @@ export function initPreview() {
     document.addEventListener('preview:ready', handlePreviewReadyEvent);
     document.addEventListener('preview:error', handlePreviewErrorEvent);
     document.addEventListener('preview:stopped', handlePreviewStoppedEvent);
+    document.addEventListener('preview:frame', handlePreviewFrameEvent);
+    document.addEventListener('preview:queued', handlePreviewQueuedEvent);
 
     // Reset state
     resetPreview();
@@ -84,17 +89,24 @@ export function cleanupPreview() {
     dom.stopBtn.removeEventListener('click', handleStopClick);
     document.removeEventListener('preview:ready', handlePreviewReadyEvent);
     document.removeEventListener('preview:error', handlePreviewErrorEvent);
+    document.removeEventListener('preview:frame', handlePreviewFrameEvent);
+    document.removeEventListener('preview:queued', handlePreviewQueuedEvent);
 }
 
 /**
  * Handle preview ready response from backend
  * @param {string} hlsUrl - HLS playlist URL
+ * @param {string} mode - 'hls', or 'frames' when OBS renders the preview
  */
-export function handlePreviewReady(hlsUrl) {
+export function handlePreviewReady(hlsUrl, mode = 'hls') {
     if (currentState !== 'loading') return;
 
+    currentMode = mode;
     setState('playing');
 
+    // Frames rendered by OBS arrive as preview:frame events
+    if (mode === 'frames') return;
+
     // Initialize HLS.js and load stream
     if (Hls.isSupported()) {
         hls = new Hls({
@@ -173,6 +185,7 @@ export function handlePreviewStopped(reason) {
         dom.video.src = '';
         dom.video.load();
     }
+    dom.image.removeAttribute('src');
 
     // Update UI to show the reason with info styling (blue background)
     setState('idle');
@@ -231,6 +244,7 @@ function stopPreview() {
     // Stop video element
     dom.video.pause();
     dom.video.src = '';
+    dom.image.removeAttribute('src');
 
     // Send stop request to backend
     sendMessage('stopPreview', {});
@@ -240,6 +254,7 @@ function stopPreview() {
 
 function setState(newState) {
     currentState = newState;
+    dom.image.style.display = 'none';
 
     switch (newState) {
         case 'idle':
@@ -262,7 +277,8 @@ function setState(newState) {
         case 'playing':
             dom.playBtn.style.display = 'none';
             dom.stopBtn.style.display = 'block';
-            dom.video.style.display = 'block';
+            dom.video.style.display = currentMode === 'frames' ? 'none' : 'block';
+            dom.image.style.display = currentMode === 'frames' ? 'block' : 'none';
             dom.loadingContainer.style.display = 'none';
             break;
 
@@ -285,8 +301,20 @@ function resetPreview() {
 }
 
 function handlePreviewReadyEvent(event) {
-    const { hlsUrl } = event.detail;
-    handlePreviewReady(hlsUrl);
+    const { hlsUrl, mode } = event.detail;
+    handlePreviewReady(hlsUrl, mode);
+}
+
+function handlePreviewQueuedEvent(event) {
+    if (currentState !== 'loading') return;
+    const { position } = event.detail;
+    dom.loadingText.textContent = `Waiting for a free preview slot (position ${position})...`;
+}
+
+function handlePreviewFrameEvent(event) {
+    // Frames of a stopped preview may still be in flight
+    if (currentState !== 'playing' || currentMode !== 'frames') return;
+    dom.image.src = event.detail.image;
 }
 
 function handlePreviewErrorEvent(event) {
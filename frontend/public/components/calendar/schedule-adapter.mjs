@@ -26,7 +26,8 @@
 //         "inputKind": "string",
 //         "uri": "string",
 //         "inputSettings": {},
-//         "transform": {}
+//         "transform": {},
+//         "mediaDurationMs": number // Length of the media, if known
 //       },
 //       "timing": {
 //         "start": "YYYY-MM-DDTHH:MM:SSZ",
@@ -36,16 +37,34 @@
 //           "daysOfWeek": ["MON", "TUE", "WED", "THU", "FRI", "SAT", "SUN"],
 //           "startRecur": "YYYY-MM-DD",
 //           "endRecur": "YYYY-MM-DD"
-//         }
+//         },
+//         // Chained items have no start/end of their own, see below
+//         "after": "string",
+//         "anchor": "string",
+//         "offsetSeconds": number,
+//         "durationSeconds": number
 //       },
 //       "behavior": {
 //         "onEndAction": "string",
 //         "preloadSeconds": number
 //       }
 //     }
-//   ]
+//   ],
+//   "tracks": [],  // Parallel tracks, not edited in the calendar
+//   "events": []   // Standalone OBS actions, not edited in the calendar
 // }
 //
+// Top-level sections the calendar does not edit are kept from the last
+// imported schedule and written back unchanged on export. Likewise, each
+// event keeps its original item (extendedProps.stored), so fields the
+// calendar does not edit, such as rundowns, breaks, layers or fallbacks,
+// survive a round trip.
+//
+// Chained items (timing.after or timing.anchor) are placed at the times the
+// server resolved for them, from a scheduleExpansion response (see
+// placeChainedItems). Only their duration can be edited in the calendar;
+// those without a known occurrence are kept as they are.
+//
 // Order of sections:
 // 1) Public API
 // 2) Mappers (Event -> Schedule Item, Schedule Item -> Event)
@@ -58,25 +77,40 @@ import {
   weekdaysNamesToNums
 } from './helpers.mjs';
 
+// Last schedule imported into each calendar, the base for its export
+const importedSchedules = new WeakMap();
+
+// Resolved occurrences of chained items by ID, for each calendar
+const chainedOccurrences = new WeakMap();
+
+// Chained items of each calendar without a known occurrence
+const unplacedItems = new WeakMap();
+
 // =============================
 // PUBLIC API
 // =============================
 
 /**
- * Build a Schedule 1.0 object from current FullCalendar events.
+ * Build a Schedule 1.0 object from current FullCalendar events, on top of
+ * the last imported schedule.
  */
 export function exportSchedule(
   calendar,
-  { scheduleName = 'Schedule', version = '1.0' } = {}
+  { scheduleName, version = '1.0' } = {}
 ) {
+  const imported = importedSchedules.get(calendar) || {};
   const singles = [];
   const seriesMap = new Map();
+  const chainedMap = new Map();
 
   for (const ev of calendar.getEvents()) {
     const item = eventToScheduleItem(ev);
     if (!item) continue;
 
-    if (item.timing.isRecurring) {
+    if (isChained(item)) {
+      // One event per occurrence; the last one added holds the latest edit
+      chainedMap.set(item.id, item);
+    } else if (item.timing.isRecurring) {
       const key = buildSeriesKey(item);
       if (!seriesMap.has(key)) seriesMap.set(key, item);
     } else {
@@ -84,8 +118,17 @@ export function exportSchedule(
     }
   }
 
-  const schedule = [...singles, ...seriesMap.values()];
-  return { version, scheduleName, schedule };
+  for (const item of unplacedItems.get(calendar) || []) {
+    if (!chainedMap.has(item.id)) chainedMap.set(item.id, item);
+  }
+
+  const schedule = [...singles, ...seriesMap.values(), ...chainedMap.values()];
+  return {
+    ...imported,
+    version,
+    scheduleName: scheduleName ?? imported.scheduleName ?? 'Schedule',
+    schedule
+  };
 }
 
 /**
@@ -93,48 +136,108 @@ export function exportSchedule(
  */
 export function importSchedule(calendar, scheduleJson) {
   if (!scheduleJson || !Array.isArray(scheduleJson.schedule)) return;
-  const inputs = scheduleJson.schedule.map(scheduleItemToEvent);
+  importedSchedules.set(calendar, scheduleJson);
+
+  const occurrences = chainedOccurrences.get(calendar) || new Map();
+  const inputs = [];
+  const unplaced = [];
+  for (const item of scheduleJson.schedule) {
+    if (!isChained(item)) {
+      inputs.push(scheduleItemToEvent(item));
+      continue;
+    }
+    const placed = occurrences.get(item.id) || [];
+    if (placed.length === 0) unplaced.push(item);
+    for (const occ of placed) inputs.push(chainedItemToEvent(item, occ));
+  }
+  unplacedItems.set(calendar, unplaced);
+
   calendar.removeAllEvents();
   calendar.addEventSource(inputs);
 }
 
+/**
+ * Place the chained items of a calendar at the occurrences of a
+ * scheduleExpansion response, replacing those of any previous response.
+ */
+export function placeChainedItems(calendar, expansion) {
+  const byId = new Map();
+  for (const occ of expansion?.items || []) {
+    if (occ.track || occ.kind !== 'scheduled') continue;
+    if (!byId.has(occ.programId)) byId.set(occ.programId, []);
+    byId.get(occ.programId).push(occ);
+  }
+  chainedOccurrences.set(calendar, byId);
+
+  if (importedSchedules.has(calendar)) {
+    importSchedule(calendar, exportSchedule(calendar));
+  }
+}
+
+/**
+ * Build a Schedule 1.0 object from a calendar without one of its items. Used
+ * for chained items, which may be shown more than once.
+ */
+export function exportScheduleWithout(calendar, id) {
+  const schedule = exportSchedule(calendar);
+  return { ...schedule, schedule: schedule.schedule.filter(item => item.id !== id) };
+}
+
 // =============================
 // MAPPERS
 // =============================
 
 /**
- * FullCalendar EventApi -> Schedule 1.0 item.
+ * FullCalendar EventApi -> Schedule 1.0 item. Starts from the original item,
+ * if any, and overwrites what the calendar edits.
  */
 function eventToScheduleItem(ev) {
   const xp = ev.extendedProps || {};
+  const stored = xp.stored || {};
   const isRecurring = !!(xp.recurrence && Object.keys(xp.recurrence).length > 0);
 
   const base = {
-    id: ev.id || genId(),
+    ...stored,
+    id: ev.id || stored.id || genId(),
     title: ev.title || '',
     enabled: Boolean(xp.enabled ?? true),
     general: {
+        ...stored.general,
         description: (xp.description ?? "").toString(),
         tags: Array.isArray(xp.tags) ? xp.tags : [],
-        classNames: (ev.classNames || []).filter(c => c !== 'recurring-event'),
+        classNames: (ev.classNames || []).filter(c => c !== 'recurring-event' && c !== 'chained-event'),
         textColor: ev.textColor || "",
         backgroundColor: ev.backgroundColor || "",
         borderColor: ev.borderColor || ""
     },
     source: {
+        ...stored.source,
         name: xp.inputName || ev.title || '',
         inputKind: xp.inputKind || 'browser_source',
         uri: (xp.inputUri ?? "").toString(),
         inputSettings: xp.inputSettings || {},
-        transform: (xp.transform && typeof xp.transform === 'object') ? xp.transform : {}
+        transform: (xp.transform && typeof xp.transform === 'object') ? xp.transform : {},
+        // Dropped when the media changed and its new duration is unknown
+        mediaDurationMs: xp.mediaDurationMs > 0 ? xp.mediaDurationMs : undefined
     },
     behavior: {
+        ...stored.behavior,
         onEndAction: xp.automation?.onEndAction ?? 'hide',
         preloadSeconds: Number(xp.automation?.preloadSeconds ?? 0),
     }
   };
+
+  // A chained item keeps its reference; only its duration is edited here
+  if (isChained(stored)) {
+    const timing = { ...stored.timing };
+    if (ev.start && ev.end) {
+      timing.durationSeconds = Math.round((ev.end - ev.start) / 1000);
+    }
+    return { ...base, timing };
+  }
   
   const timing = {
+      ...stored.timing,
       // For non-recurring events, convert dates to UTC ISO string with 'Z'
       start: ev.start ? ev.start.toISOString() : null,
       end: ev.end ? ev.end.toISOString() : null,
@@ -189,7 +292,9 @@ function scheduleItemToEvent(item) {
     inputUri: (source.uri ?? "").toString(),
     inputSettings: source.inputSettings || {},
     transform: (source.transform && typeof source.transform === 'object') ? source.transform : {},
-    recurrence: {}
+    mediaDurationMs: Number(source.mediaDurationMs || 0),
+    recurrence: {},
+    stored: item
   };
   
   const classNames = Array.isArray(general.classNames) ? general.classNames : [];
@@ -242,10 +347,43 @@ function scheduleItemToEvent(item) {
 }
 
 
+/**
+ * Chained Schedule 1.0 item -> FullCalendar EventInput at one resolved
+ * occurrence. Occurrences share a group, so a resize applies to all of them.
+ */
+function chainedItemToEvent(item, occ) {
+  const event = scheduleItemToEvent({
+    ...item,
+    timing: { ...item.timing, isRecurring: false }
+  });
+  event.extendedProps.stored = item;
+
+  // The end follows the edited duration, which the server may not know yet
+  const start = new Date(occ.start);
+  const durationSeconds = Number(item.timing.durationSeconds || 0);
+  const end = durationSeconds > 0 ? new Date(start.getTime() + durationSeconds * 1000) : new Date(occ.end);
+
+  return {
+    ...event,
+    groupId: `chain-${item.id}`,
+    start,
+    end,
+    startEditable: false,
+    classNames: uniq([...event.classNames, 'chained-event'])
+  };
+}
+
 // =============================
 // LOCAL HELPERS
 // =============================
 
+/**
+ * Reports whether an item starts relative to another one.
+ */
+function isChained(item) {
+  return !!(item?.timing?.after || item?.timing?.anchor);
+}
+
 /**
  * Creates a stable JSON string key to identify a unique recurring series.
  */
@@ -0,0 +1,115 @@
+// File: components/calendar/timeline-events.mjs
+// Read-only list of the standalone timeline events (OBS actions and macros)
+// of the schedule. They are not edited in the calendar; the adapter keeps
+// them from the imported schedule, see schedule-adapter.mjs.
+
+import { exportSchedule } from './schedule-adapter.mjs';
+
+// ================================
+// STATE
+// ================================
+let dialogElement = null;
+
+// ================================
+// PUBLIC API
+// ================================
+
+/**
+ * Show the timeline events of the schedule loaded in a calendar
+ * @param {Calendar} calendar - FullCalendar instance
+ */
+export function showTimelineEvents(calendar) {
+    if (!dialogElement) createDialog();
+
+    const events = exportSchedule(calendar).events || [];
+    const body = dialogElement.querySelector('.timeline-events-body');
+    body.innerHTML = '';
+
+    if (events.length === 0) {
+        const empty = document.createElement('p');
+        empty.className = 'hint';
+        empty.textContent = 'This schedule has no timeline events.';
+        body.appendChild(empty);
+    } else {
+        const table = document.createElement('table');
+        table.className = 'timeline-events-table';
+        table.appendChild(buildRow('th', ['Title', 'Action', 'When', 'Enabled']));
+        for (const ev of events) {
+            table.appendChild(buildRow('td', [
+                ev.title || ev.id || '',
+                describeAction(ev),
+                describeTiming(ev.timing || {}),
+                ev.enabled ? 'yes' : 'no'
+            ]));
+        }
+        body.appendChild(table);
+    }
+
+    dialogElement.style.display = 'flex';
+}
+
+// ================================
+// DIALOG CREATION
+// ================================
+
+function createDialog() {
+    dialogElement = document.createElement('div');
+    dialogElement.id = 'events-modal';
+    dialogElement.className = 'modal';
+    dialogElement.setAttribute('role', 'dialog');
+    dialogElement.setAttribute('aria-modal', 'true');
+    dialogElement.innerHTML = `
+        <div class="modal-content">
+            <header class="modal-header">
+                <h3>Timeline Events</h3>
+                <button type="button" class="icon-btn" aria-label="Close">&times;</button>
+            </header>
+            <div class="timeline-events-body"></div>
+            <p class="hint timeline-events-note">Timeline events are kept as they are when the schedule is saved. Edit them in the schedule file.</p>
+        </div>
+    `;
+
+    dialogElement.querySelector('.icon-btn').addEventListener('click', hideDialog);
+    dialogElement.addEventListener('click', (e) => {
+        if (e.target === dialogElement) hideDialog();
+    });
+
+    document.body.appendChild(dialogElement);
+}
+
+function hideDialog() {
+    if (dialogElement) dialogElement.style.display = 'none';
+}
+
+// ================================
+// PRIVATE HELPERS
+// ================================
+
+function buildRow(cellTag, values) {
+    const row = document.createElement('tr');
+    for (const value of values) {
+        const cell = document.createElement(cellTag);
+        cell.textContent = value;
+        row.appendChild(cell);
+    }
+    return row;
+}
+
+function describeAction(ev) {
+    if (ev.action === 'macro') {
+        const steps = ev.steps || [];
+        return `macro: ${steps.map(s => s.requestType).join(', ') || 'no steps'}`;
+    }
+    return ev.filename ? `${ev.action} (${ev.filename})` : (ev.action || '');
+}
+
+function describeTiming(timing) {
+    if (!timing.start) return '';
+    if (!timing.isRecurring) return new Date(timing.start).toLocaleString();
+
+    const rec = timing.recurrence || {};
+    const time = (timing.start.split('T')[1] || '').replace('Z', '');
+    const days = (rec.daysOfWeek || []).join(' ') || 'every day';
+    const range = [rec.startRecur, rec.endRecur].filter(Boolean).join(' to ');
+    return range ? `${time} ${days}, ${range}` : `${time} ${days}`;
+}
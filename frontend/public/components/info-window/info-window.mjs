@@ -28,8 +28,8 @@ export function initInfoWindow(container) {
         const msg = e.detail;
         const action = msg.action || 'N/A';
 
-        // Skip logging currentSchedule messages (schedule updates are frequent and noisy)
-        if (action === 'currentSchedule') {
+        // Skip logging currentSchedule, thumbnailUpdated and previewFrame messages (frequent and noisy)
+        if (action === 'currentSchedule' || action === 'thumbnailUpdated' || action === 'previewFrame') {
             return;
         }
 
@@ -4,6 +4,7 @@
 import { initInfoWindow } from '../info-window/info-window.mjs';
 import { initLivePreview, cleanupLivePreview } from '../live-preview/live-preview.mjs';
 import { initMonitorCalendar } from '../calendar/calendar-monitor.mjs';
+import { initThumbnails } from '../thumbnails/thumbnails.mjs';
 
 /**
  * Initialize Monitor View
@@ -14,6 +15,7 @@ export async function initMonitorView() {
     // Get containers
     const livePreviewContainer = document.getElementById('live-preview-container');
     const infoWindowContainer = document.getElementById('info-window-container');
+    const thumbnailsContainer = document.getElementById('thumbnails-container');
     const monitorCalendarContainer = document.getElementById('monitor-calendar');
 
     // Initialize sub-components
@@ -21,6 +23,10 @@ export async function initMonitorView() {
         await initLivePreview(livePreviewContainer);
     }
 
+    if (thumbnailsContainer) {
+        initThumbnails(thumbnailsContainer);
+    }
+
     if (infoWindowContainer) {
         await initInfoWindow(infoWindowContainer);
     }
@@ -0,0 +1,43 @@
+// File: components/thumbnails/thumbnails.mjs
+// Responsibility: Show the confidence thumbnails of the program on air and of the next program.
+// Images are served by the backend at /thumbnails/<slot>; the WebSocket service
+// dispatches 'thumbnail:updated' with a new URL whenever one changes.
+
+const slotLabels = {
+    program: 'On air',
+    next: 'Next'
+};
+
+/**
+ * Initializes the thumbnail grid.
+ * @param {HTMLElement} container - The container element for the thumbnails.
+ */
+export function initThumbnails(container) {
+    const grid = container.querySelector('.thumbnail-grid');
+    if (!grid) {
+        // Missing required elements - silently fail
+        return;
+    }
+
+    document.addEventListener('thumbnail:updated', (e) => {
+        const { slot, url, title } = e.detail;
+        const figure = grid.querySelector(`figure[data-slot="${slot}"]`);
+        if (!figure) {
+            return;
+        }
+
+        const img = figure.querySelector('img');
+        const caption = figure.querySelector('figcaption');
+        const label = slotLabels[slot] || slot;
+
+        if (url) {
+            img.src = url;
+            img.alt = `${label}: ${title}`;
+            caption.textContent = `${label}: ${title}`;
+        } else {
+            img.removeAttribute('src');
+            img.alt = '';
+            caption.textContent = `${label}: —`;
+        }
+    });
+}
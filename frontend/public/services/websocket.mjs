@@ -7,10 +7,18 @@
 //   => { action: "getSchedule", payload: {} }
 // - commitSchedule: Sends the current schedule to be saved.
 //   => { action: "commitSchedule", payload: { Schedule 1.0 JSON object } }
+// - expandSchedule: Requests the resolved occurrences over a time range.
+//   => { action: "expandSchedule", payload: { from: "ISO", to: "ISO" } }
+// - probeMediaDuration: Requests the length of a media source.
+//   => { action: "probeMediaDuration", payload: { requestId, inputKind, uri, inputSettings, start } }
 //
 // --- Incoming Actions (Server -> Client) ---
 // - currentSchedule: Carries the full schedule payload from the server.
 //   => { action: "currentSchedule", payload: { Schedule 1.0 JSON object } }
+// - scheduleExpansion: Carries the occurrences of an expandSchedule range.
+//   => { action: "scheduleExpansion", payload: { from, to, items: [...] } }
+// - mediaDuration / mediaDurationError: Answer a probeMediaDuration request.
+//   => { action: "mediaDuration", payload: { requestId, uri, durationMs, method, proposedEnd } }
 // - log: Carries a generic message for logging.
 //   => { action: "log", payload: "Server message here..." }
 
@@ -143,6 +151,30 @@ function handleMessage(message) {
             addLogMessage(`Schedule loaded (${eventCount} events)`, 'info');
             break;
 
+        case 'scheduleExpansion':
+            // Resolved occurrences of a range, used to place chained items
+            document.dispatchEvent(new CustomEvent('schedule:expanded', {
+                detail: { from: payload.from, to: payload.to, items: payload.items || [] }
+            }));
+            break;
+
+        case 'expandScheduleError':
+            addLogMessage(`Could not expand the schedule: ${payload.message}`, 'warning');
+            break;
+
+        case 'mediaDuration':
+            // Length of a media source, for the source picker
+            document.dispatchEvent(new CustomEvent('media:duration', {
+                detail: { requestId: payload.requestId, durationMs: payload.durationMs, proposedEnd: payload.proposedEnd }
+            }));
+            break;
+
+        case 'mediaDurationError':
+            document.dispatchEvent(new CustomEvent('media:durationError', {
+                detail: { requestId: payload.requestId, message: payload.message }
+            }));
+            break;
+
         case 'log':
             // Add log message to activity log
             addLogMessage(payload, 'info');
@@ -165,6 +197,34 @@ function handleMessage(message) {
             addLogMessage('OBS disconnected', 'warning');
             break;
 
+        case 'frameAnomaly':
+            // Picture on air is black, frozen or partly static, or back to normal
+            if (payload.active) {
+                const shown = payload.fallbackActive ? ', fallback shown' : '';
+                addLogMessage(`Picture anomaly on "${payload.title}": ${payload.detail}${shown}`, 'warning');
+            } else {
+                addLogMessage(`Picture on "${payload.title}" is back to normal`, 'info');
+            }
+            break;
+
+        case 'silenceAlert':
+            // Program on air went silent, or its audio is back
+            if (payload.silent) {
+                addLogMessage(`Dead air on "${payload.title}"${payload.fallbackActive ? ', fallback shown' : ''}`, 'warning');
+            } else {
+                addLogMessage(`Audio is back on "${payload.title}"`, 'info');
+            }
+            break;
+
+        case 'preflightAlert':
+            // Pre-flight check of an upcoming program failed, or passed again
+            if (!payload.passed) {
+                addLogMessage(`Pre-flight failed for "${payload.title}": ${(payload.problems || []).join('; ')}${payload.willReplace ? ' (fallback will air)' : ''}`, 'warning');
+            } else {
+                addLogMessage(`Pre-flight passed for "${payload.title}"`, 'info');
+            }
+            break;
+
         case 'virtualCamStarted':
             // VirtualCam started - stream is now available
             setPreviewStatus('available');
@@ -196,10 +256,31 @@ function handleMessage(message) {
             }
             break;
 
+        case 'thumbnailUpdated':
+            // Confidence thumbnail changed; an empty URL clears the slot
+            document.dispatchEvent(new CustomEvent('thumbnail:updated', {
+                detail: { slot: payload.slot, url: payload.url, title: payload.title }
+            }));
+            break;
+
         case 'previewReady':
-            // Source preview HLS stream is ready
+            // Source preview is ready: an HLS stream, or frames rendered by OBS
             document.dispatchEvent(new CustomEvent('preview:ready', {
-                detail: { hlsUrl: payload.hlsUrl }
+                detail: { mode: payload.mode, hlsUrl: payload.hlsUrl }
+            }));
+            break;
+
+        case 'previewFrame':
+            // Frame of a source preview rendered by OBS
+            document.dispatchEvent(new CustomEvent('preview:frame', {
+                detail: { previewId: payload.previewId, image: payload.image }
+            }));
+            break;
+
+        case 'previewQueued':
+            // Source preview waits for a free generator process
+            document.dispatchEvent(new CustomEvent('preview:queued', {
+                detail: { position: payload.position }
             }));
             break;
 
@@ -90,7 +90,7 @@ func main() {
 	mainWebServer := webserver.New(mainCtx, mainLogger, &cfg.WebServer, mainEventBus, frontendFS)
 
 	//*************** 6. OBS Client *****************************************************
-	mainObsClient := obsclient.New(mainCtx, mainLogger, &cfg.OBS, mainEventBus)
+	mainObsClient := obsclient.New(mainCtx, mainLogger, &cfg.OBS, &cfg.Paths, mainEventBus)
 
 	//*************** 7. Scheduler (includes internal FileWatcher) **********************
 	mainScheduler := scheduler.New(mainCtx, mainLogger, &cfg.Paths, &cfg.Scheduler, mainEventBus)